@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var GraphOutput string
+
+func init() {
+	graphCmd.Flags().StringVarP(&GraphOutput, "output", "o", "", "File to write the graph to, defaults to stdout")
+
+	rootCmd.AddCommand(graphCmd)
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Render the tracks and steps as a Graphviz DOT graph",
+	Long: `This will render the tracks and steps that would be deployed as a Graphviz DOT graph,
+e.g. runiac graph | dot -Tsvg > runiac.svg. Nothing is deployed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.GetConfig()
+		if _, invalid := err.(validator.ValidationErrors); err != nil && !invalid {
+			log.Fatalf("Unable to read the configuration: %s\n", err)
+		}
+
+		// gathering the default track copies its steps, keep the copies in memory
+		fs := afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(appFS), afero.NewMemMapFs())
+
+		logger := logrus.New()
+		logger.SetLevel(logrus.WarnLevel)
+
+		tracker := tracks.DirectoryBasedTracker{Fs: fs, Log: logrus.NewEntry(logger)}
+
+		gathered, err := tracker.GatherTracks(cfg)
+		if err != nil {
+			log.Fatalf("Unable to gather the tracks: %s\n", err)
+		}
+
+		graph, err := tracks.RenderGraph(gathered)
+		if err != nil {
+			log.Fatalf("Unable to render the graph: %s\n", err)
+		}
+
+		if GraphOutput == "" {
+			fmt.Print(graph)
+			return
+		}
+
+		if err := afero.WriteFile(appFS, GraphOutput, []byte(graph), 0644); err != nil {
+			log.Fatalf("Unable to write the graph to %s: %s\n", GraphOutput, err)
+		}
+	},
+}
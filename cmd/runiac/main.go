@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/optum/runiac/pkg/config"
 	"github.com/optum/runiac/pkg/logging"
+	"github.com/optum/runiac/pkg/reporting"
+	"github.com/optum/runiac/pkg/steps"
 	"github.com/optum/runiac/pkg/tracks"
 	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
 	"github.com/sirupsen/logrus"
@@ -25,37 +32,118 @@ func main() {
 
 	log.Debugf("Beginning Account Deployment: %s", deployment.Config.AccountID)
 
+	if deployment.Config.ApplyBundle != "" {
+		dir, err := afero.TempDir(fs, "", "runiac-bundle")
+		if err != nil {
+			log.WithError(err).Fatal("Unable to create a directory for the plan bundle")
+		}
+
+		manifest, err := tracks.ExtractPlanBundle(fs, deployment.Config.ApplyBundle, dir)
+		if err != nil {
+			log.WithError(err).Fatalf("Unable to extract plan bundle %s", deployment.Config.ApplyBundle)
+		}
+
+		log.Infof("Applying %d plan(s) from plan bundle %s", len(manifest.Plans), deployment.Config.ApplyBundle)
+		deployment.Config.BundledPlansDir = dir
+	}
+
+	pauser := &tracks.Pauser{}
+	tracks.DeploymentPause = pauser
+	notifyPauseSignals(pauser)
+
+	var results *tracks.BufferedResultPublisher
+	if deployment.Config.ResultStreamURL != "" {
+		results = tracks.NewBufferedResultPublisher(log, tracks.HTTPResultStreamer{
+			URL:    deployment.Config.ResultStreamURL,
+			Client: &http.Client{Timeout: 10 * time.Second},
+		}, deployment.Config.ResultStreamBufferSize, 50, time.Second)
+		tracks.Results = results
+	}
+
 	log.Debug("Executing tracks...")
 
-	output := tracker.ExecuteTracks(deployment.Config)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifyCancelSignals(cancel)
+
+	output := tracker.ExecuteTracks(ctx, deployment.Config)
+
+	if results != nil {
+		results.Close()
+	}
 
 	log.Debug("Completed executing tracks...")
 
+	if deployment.Config.PlanBundleFile != "" {
+		if err := tracks.WritePlanBundle(fs, deployment.Config.PlanBundleFile, deployment.Config, output); err != nil {
+			log.WithError(err).Error("Failed to write plan bundle")
+		} else {
+			log.Infof("Wrote plan bundle %s", deployment.Config.PlanBundleFile)
+		}
+	}
+
+	if deployment.Config.ResultsJSON != "" {
+		if err := tracks.WriteStageJSON(fs, deployment.Config.ResultsJSON, output); err != nil {
+			log.WithError(err).Errorf("Failed to write results to %s", deployment.Config.ResultsJSON)
+		}
+	}
+
+	if deployment.Config.JUnitReportFile != "" {
+		if err := reporting.WriteJUnitReport(fs, deployment.Config.JUnitReportFile, output); err != nil {
+			log.WithError(err).Errorf("Failed to write JUnit report to %s", deployment.Config.JUnitReportFile)
+		}
+	}
+
 	trackCount := len(output.Tracks)
 	failedSteps := []string{}
 	skippedSteps := []string{}
+	cancelledSteps := []string{}
 	skippedTracks := []string{}
+	partialTracks := []string{}
+	failedTracks := []string{}
+	failureCategories := map[config.FailureCategory]int{}
 	failedDestroySteps := []string{}
+	retriedSteps := []string{}
 	stepCount := 0
 	executedStepCount := 0
 	failedTestCount := 0
+	rateLimitedCount := 0
 
 	for _, t := range output.Tracks {
 		if t.Skipped {
-			skippedTracks = append(skippedTracks, t.Name)
+			skippedTracks = append(skippedTracks, fmt.Sprintf("%v (%v)", t.Name, t.SkipReason))
+		}
+
+		if t.Output.Partial {
+			partialTracks = append(partialTracks, t.Name)
+		}
+
+		if t.Output.Err != nil {
+			failedTracks = append(failedTracks, fmt.Sprintf("%v (%v)", t.Name, t.Output.Err))
 		}
 
 		for _, tExecution := range t.Output.Executions {
 			executedStepCount += tExecution.Output.ExecutedCount
-			stepCount += tExecution.Output.ExecutedCount + tExecution.Output.SkippedCount
+			stepCount += tExecution.Output.ExecutedCount + tExecution.Output.SkippedCount + tExecution.Output.CancelledCount
 			failedTestCount += tExecution.Output.FailedTestCount
+			rateLimitedCount += tExecution.Output.RateLimitedCount
 
 			for _, s := range tExecution.Output.Steps {
+				if s.Output.Attempts > 1 {
+					retriedSteps = append(retriedSteps, fmt.Sprintf("%v/%v/%v/%v (%v attempts)", t.Name, s.Name, tExecution.RegionDeployType, tExecution.Region, s.Output.Attempts))
+				}
+
 				switch s.Output.Status {
 				case config.Fail:
 					failedSteps = append(failedSteps, fmt.Sprintf("%v/%v/%v/%v", t.Name, s.Name, tExecution.RegionDeployType, tExecution.Region))
+
+					if s.Output.FailureCategory != "" {
+						failureCategories[s.Output.FailureCategory]++
+					}
 				case config.Skipped:
 					skippedSteps = append(skippedSteps, fmt.Sprintf("%v/%v/%v/%v", t.Name, s.Name, tExecution.RegionDeployType, tExecution.Region))
+				case config.Cancelled:
+					cancelledSteps = append(cancelledSteps, fmt.Sprintf("%v/%v/%v/%v", t.Name, s.Name, tExecution.RegionDeployType, tExecution.Region))
 				}
 			}
 
@@ -68,6 +156,16 @@ func main() {
 		}
 	}
 
+	// tracks and steps are gathered in map order, sort them so summaries of the same result are identical
+	sort.Strings(failedSteps)
+	sort.Strings(skippedSteps)
+	sort.Strings(cancelledSteps)
+	sort.Strings(failedTracks)
+	sort.Strings(partialTracks)
+	sort.Strings(failedDestroySteps)
+	sort.Strings(skippedTracks)
+	sort.Strings(retriedSteps)
+
 	failedStepCount := len(failedSteps)
 
 	resultMessage := fmt.Sprintf("Executed %v/%v steps successfully with %v test failure(s) across %v track(s).",
@@ -75,21 +173,130 @@ func main() {
 
 	result := "success"
 
+	if output.Err != nil {
+		resultMessage = fmt.Sprintf("Deployment aborted: %v.  %s", output.Err, resultMessage)
+		result = "fail"
+	}
+
 	if failedStepCount > 0 {
 		resultMessage += fmt.Sprintf("  Failed: %v.", strings.Join(failedSteps, ", "))
 		result = "fail"
 	}
 
+	if len(failureCategories) > 0 {
+		categories := []string{}
+		for category, count := range failureCategories {
+			categories = append(categories, fmt.Sprintf("%v: %v", category, count))
+		}
+		sort.Strings(categories)
+
+		resultMessage += fmt.Sprintf("  Failure categories: %v.", strings.Join(categories, ", "))
+	}
+
+	if len(failedTracks) > 0 {
+		resultMessage += fmt.Sprintf("  Failed tracks: %v.", strings.Join(failedTracks, ", "))
+		result = "fail"
+	}
+
 	if len(skippedSteps) > 0 {
 		resultMessage += fmt.Sprintf("  Skipped: %v.", strings.Join(skippedSteps, ", "))
 		result = "fail"
 	}
 
+	if len(cancelledSteps) > 0 {
+		resultMessage += fmt.Sprintf("  Cancelled: %v.", strings.Join(cancelledSteps, ", "))
+		result = "fail"
+	}
+
+	if len(skippedTracks) > 0 {
+		resultMessage += fmt.Sprintf("  Skipped tracks: %v.", strings.Join(skippedTracks, ", "))
+	}
+
+	if len(retriedSteps) > 0 {
+		resultMessage += fmt.Sprintf("  Retried: %v.", strings.Join(retriedSteps, ", "))
+	}
+
+	if summary, planned := output.PlanSummary(); planned && deployment.Config.DryRun {
+		resultMessage += fmt.Sprintf("  Plan: %s.", summary)
+	}
+
+	if rateLimitedCount > 0 {
+		resultMessage += fmt.Sprintf("  Rate limited: %v step(s).", rateLimitedCount)
+	}
+
+	if len(partialTracks) > 0 {
+		resultMessage += fmt.Sprintf("  Partially deployed: %v.", strings.Join(partialTracks, ", "))
+	}
+
 	if len(failedDestroySteps) > 0 {
 		resultMessage += fmt.Sprintf("  Failed to destroy: %v.", strings.Join(failedDestroySteps, ", "))
 		result = "fail"
 	}
 
+	if warnings := output.Warnings(); len(warnings) > 0 {
+		stepWarnings := []string{}
+		for _, w := range warnings {
+			stepWarnings = append(stepWarnings, w.String())
+		}
+
+		resultMessage += fmt.Sprintf("  Warnings: %v.", strings.Join(stepWarnings, ", "))
+
+		warningsJSON, err := json.Marshal(warnings)
+		if err != nil {
+			log.WithError(err).Error("Failed to marshal step warnings")
+		} else {
+			log.WithField("type", "warnings").Info(string(warningsJSON))
+		}
+	}
+
+	if deployment.Config.SlowestStepsReportCount > 0 {
+		report := output.TimingReport(deployment.Config.SlowestStepsReportCount)
+		resultMessage += fmt.Sprintf("  Timing: %s", report)
+
+		log.WithFields(logrus.Fields{
+			"type":        "timing",
+			"wallClock":   report.WallClock.String(),
+			"summedSteps": report.SummedStepTime.String(),
+			"parallelism": report.Parallelism(),
+		}).Info(report.String())
+	}
+
+	if deployment.Config.DestroyPreview {
+		preview := output.DestroyPreview()
+		resultMessage += fmt.Sprintf("  %s", preview)
+
+		previewJSON, err := json.Marshal(preview)
+		if err != nil {
+			log.WithError(err).Error("Failed to marshal destroy preview")
+		} else {
+			log.WithField("type", "destroyPreview").Info(string(previewJSON))
+		}
+	}
+
+	if deployment.Config.EmitInventory {
+		inventory, err := json.Marshal(output.Inventory())
+		if err != nil {
+			log.WithError(err).Error("Failed to marshal resource inventory")
+		} else {
+			log.WithField("type", "inventory").Info(string(inventory))
+		}
+	}
+
+	if graph := output.DependencyGraph(); len(graph) > 0 {
+		dependencies, err := json.Marshal(graph)
+		if err != nil {
+			log.WithError(err).Error("Failed to marshal step dependency graph")
+		} else {
+			log.WithField("type", "dependencies").Info(string(dependencies))
+		}
+	}
+
+	if deployment.Config.CloudEventsSink != "" {
+		if err := tracks.EmitCloudEvent(deployment.Config.CloudEventsSink, output.CloudEvent(deployment.Config)); err != nil {
+			log.WithError(err).Error("Failed to emit deployment completed cloud event")
+		}
+	}
+
 	slog := log.WithFields(logrus.Fields{
 		"type":          "summary",
 		"skipped":       strings.Join(skippedSteps, ","),
@@ -106,6 +313,19 @@ func main() {
 	}
 }
 
+// notifyCancelSignals cancels the deployment on SIGINT or SIGTERM, interrupting the in-flight steps and skipping the
+// steps that have not started
+func notifyCancelSignals(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+		log.Warnf("Received %v, cancelling the deployment", sig)
+		cancel()
+	}()
+}
+
 func initFunc() {
 	// Log as JSON instead of the default ASCII formatter.
 	logger := logrus.New()
@@ -133,6 +353,9 @@ func initFunc() {
 		log.WithError(err).Fatal(err.Error())
 	}
 
+	// files and directories runiac creates honor the configured permissions
+	fs = steps.NewPermissionsFs(fs, deployment.Config.FileMode, deployment.Config.DirMode)
+
 	// Only log the warning severity or above.
 	lvl, err := logrus.ParseLevel(deployment.Config.LogLevel)
 
@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/optum/runiac/pkg/tracks"
+)
+
+// notifyPauseSignals pauses the deployment on SIGUSR1 and resumes it on SIGUSR2
+func notifyPauseSignals(pauser *tracks.Pauser) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for sig := range signals {
+			switch sig {
+			case syscall.SIGUSR1:
+				log.Warn("Deployment paused, no new tracks or steps will start until SIGUSR2 is received")
+				pauser.Pause()
+			case syscall.SIGUSR2:
+				log.Info("Deployment resumed")
+				pauser.Resume()
+			}
+		}
+	}()
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package main
+
+import "github.com/optum/runiac/pkg/tracks"
+
+// notifyPauseSignals is a no-op on windows, SIGUSR1 and SIGUSR2 are not supported
+func notifyPauseSignals(pauser *tracks.Pauser) {}
@@ -2,7 +2,9 @@ package cloudaccountdeployment
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/optum/runiac/pkg/config"
 
@@ -51,6 +53,7 @@ type UpdateStatusPayload struct {
 	Step                string   `json:"step"`
 	TargetRegions       []string `json:"targeted_regions"`
 	PrimaryRegion       string   `json:"primary_region"`
+	Environment         string   `json:"environment"`
 }
 
 type UpdateRegionalStatusPayload struct {
@@ -73,9 +76,37 @@ type ExecutionResult struct {
 	TargetRegions           []string
 }
 
+// StepDeployments are the recorded step executions not yet flushed, keyed by track, step, region deploy type and region.
+// While steps are executing, only record and flush steps rather than accessing it directly
 var StepDeployments = map[string]ExecutionResult{}
+
+// stepDeploymentsMutex guards StepDeployments, steps are recorded concurrently across tracks and regions while
+// completed tracks are flushed
+var stepDeploymentsMutex sync.Mutex
+
+// recordStepDeployment records the result of a step's execution in a region
+func recordStepDeployment(track string, step string, regionDeployType string, region string, result ExecutionResult) {
+	stepDeploymentsMutex.Lock()
+	defer stepDeploymentsMutex.Unlock()
+
+	d := Cfg.IDDelimiter()
+	StepDeployments[d+strings.Join([]string{track, step, regionDeployType, region}, d)] = result
+}
+
 var Cfg, _ = config.GetConfig()
 
+// ReportRegionalStatusFunc reports a flushed step's regional status to the status backend
+type ReportRegionalStatusFunc func(logger *logrus.Entry, payload UpdateRegionalStatusPayload) error
+
+// ReportRegionalStatus reports the statuses of flushed steps unless Cfg.DisableStatusReporting is set, nothing is
+// reported when no status backend is configured
+var ReportRegionalStatus ReportRegionalStatusFunc
+
+// accountStepDeploymentID identifies a step's deployment, joining its names with the configured step id delimiter
+func accountStepDeploymentID(executionID string, stage string, track string, step string) string {
+	return strings.Join([]string{executionID, stage, track, step}, Cfg.IDDelimiter())
+}
+
 func RecordStepStart(logger *logrus.Entry, accountID string, track string, step string, regionDeployType string, region string, dryRun bool, csp string, version string, executionID string, stepFunctionName string, codePipelineExecutionID string, stage string, runiacTargetRegions []string) {
 	//deployPhase := PreDeploy
 	//result := InProgress
@@ -110,6 +141,7 @@ func RecordStepStart(logger *logrus.Entry, accountID string, track string, step
 	//	Track:               track,
 	//	PrimaryRegion:       region,
 	//	TargetRegions:       runiacTargetRegions,
+	//	Environment:         Cfg.Environment,
 	//}
 	//
 	//InvokeLambdaFunc(logger, p)
@@ -119,41 +151,41 @@ func RecordStepSuccess(logger *logrus.Entry, csp string, track string, step stri
 	result := Success
 	//resultMessage := "Success"
 
-	StepDeployments[fmt.Sprintf("#%s#%s#%s#%s", track, step, regionDeployType, region)] = ExecutionResult{
+	recordStepDeployment(track, step, regionDeployType, region, ExecutionResult{
 		Result:                  result,
 		Region:                  region,
 		RegionDeployType:        regionDeployType,
-		AccountStepDeploymentID: fmt.Sprintf("%s#%s#%s#%s", executionID, stage, track, step),
+		AccountStepDeploymentID: accountStepDeploymentID(executionID, stage, track, step),
 		CSP:                     csp,
 		TargetRegions:           runiacTargetRegions,
-	}
+	})
 }
 
 func RecordStepFail(logger *logrus.Entry, csp string, track string, step string, regionDeployType string, region string, executionID string, stage string, runiacTargetRegions []string, err error) {
 	result := Fail
 	//resultMessage := ""
 
-	StepDeployments[fmt.Sprintf("#%s#%s#%s#%s", track, step, regionDeployType, region)] = ExecutionResult{
+	recordStepDeployment(track, step, regionDeployType, region, ExecutionResult{
 		Result:                  result,
 		Region:                  region,
 		RegionDeployType:        regionDeployType,
-		AccountStepDeploymentID: fmt.Sprintf("%s#%s#%s#%s", executionID, stage, track, step),
+		AccountStepDeploymentID: accountStepDeploymentID(executionID, stage, track, step),
 		CSP:                     csp,
 		TargetRegions:           runiacTargetRegions,
-	}
+	})
 }
 
 func RecordStepTestFail(logger *logrus.Entry, csp string, track string, step string, regionDeployType string, region string, executionID string, stage string, runiacTargetRegions []string, err error) {
 	result := Unstable
 
-	StepDeployments[fmt.Sprintf("#%s#%s#%s#%s", track, step, regionDeployType, region)] = ExecutionResult{
+	recordStepDeployment(track, step, regionDeployType, region, ExecutionResult{
 		Result:                  result,
 		Region:                  region,
 		RegionDeployType:        regionDeployType,
-		AccountStepDeploymentID: fmt.Sprintf("%s#%s#%s#%s", executionID, stage, track, step),
+		AccountStepDeploymentID: accountStepDeploymentID(executionID, stage, track, step),
 		CSP:                     csp,
 		TargetRegions:           runiacTargetRegions,
-	}
+	})
 }
 
 // Flush track will record a track's regional deployments
@@ -161,12 +193,12 @@ func FlushTrack(logger *logrus.Entry, track string) (steps map[string]*UpdateReg
 	steps = map[string]*UpdateRegionalStatusPayload{}
 	flushedSteps := []string{}
 
-	if len(StepDeployments) == 0 {
-		logger.Warnf("FlushTrack: No steps to flush for track")
-	}
+	// tracks are flushed as they complete, while other tracks are still recording their steps
+	stepDeploymentsMutex.Lock()
 
+	d := Cfg.IDDelimiter()
 	for k, v := range StepDeployments {
-		if !strings.HasPrefix(k, "#"+track+"#") {
+		if !strings.HasPrefix(k, d+track+d) {
 			continue
 		}
 
@@ -190,20 +222,27 @@ func FlushTrack(logger *logrus.Entry, track string) (steps map[string]*UpdateReg
 		flushedSteps = append(flushedSteps, k)
 	}
 
+	// reset step deployments, only removing the flushed track's steps
+	for _, flushedStep := range flushedSteps {
+		delete(StepDeployments, flushedStep)
+	}
+
+	stepDeploymentsMutex.Unlock()
+
+	// tracks without recorded steps, e.g. skipped tracks, have nothing to report
+	if len(flushedSteps) == 0 {
+		logger.Debugf("FlushTrack: No steps to flush for track %s", track)
+		return steps, nil
+	}
+
 	for stepID, v := range steps {
-		failedExecutionCount := len(v.FailedRegions)
-		if failedExecutionCount >= len(v.TargetRegions) {
-			v.Result = Fail.String()
-		} else if failedExecutionCount > 0 {
-			v.Result = Unstable.String()
-		} else {
-			v.Result = Success.String()
-		}
+		// executions are recorded concurrently, report their failures in a stable order
+		sort.Strings(v.FailedRegions)
 
 		includeRegional := false
 		includePrimary := false
 		primaryRegion := ""
-		primaryFailCount := 0
+		primaryDeployFailed := false
 		regionalFailCount := 0
 		failures := []string{}
 		for _, execution := range v.Executions {
@@ -219,13 +258,24 @@ func FlushTrack(logger *logrus.Entry, track string) (steps map[string]*UpdateReg
 				includePrimary = true
 				primaryRegion = execution.Region
 
+				primaryDeployFailed = execution.Result == Fail
+
 				if execution.Result == Fail || execution.Result == Unstable {
-					primaryFailCount++
 					failures = append(failures, fmt.Sprintf("%s/%s", execution.RegionDeployType, execution.Region))
 				}
 			}
 		}
 
+		// a step fails when its primary deployment failed or it failed in every regional region, and is otherwise unstable
+		// when any of its executions failed
+		if primaryDeployFailed || (len(v.TargetRegions) > 0 && regionalFailCount >= len(v.TargetRegions)) {
+			v.Result = Fail.String()
+		} else if len(v.FailedRegions) > 0 {
+			v.Result = Unstable.String()
+		} else {
+			v.Result = Success.String()
+		}
+
 		v.ResultMessage += fmt.Sprintf("%s:", v.Result)
 
 		if includePrimary {
@@ -239,15 +289,20 @@ func FlushTrack(logger *logrus.Entry, track string) (steps map[string]*UpdateReg
 		}
 
 		if len(failures) > 0 {
+			sort.Strings(failures)
 			v.ResultMessage += fmt.Sprintf("  Failed executions: %s", strings.Join(failures, ", "))
 		}
 
 		logger.Infof("%s: %s", stepID, v.ResultMessage)
-	}
 
-	// reset step deployments
-	for _, flushedStep := range flushedSteps {
-		delete(StepDeployments, flushedStep)
+		if ReportRegionalStatus == nil || Cfg.DisableStatusReporting {
+			continue
+		}
+
+		if reportErr := ReportRegionalStatus(logger, *v); reportErr != nil {
+			logger.WithError(reportErr).Errorf("FlushTrack: Unable to report the status of step %s", stepID)
+			err = reportErr
+		}
 	}
 
 	return steps, err
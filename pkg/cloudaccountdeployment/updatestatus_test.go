@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
@@ -146,3 +147,358 @@ func TestFlushTrack_ShouldReportAllStepsInSingleTrack(t *testing.T) {
 		require.True(t, strings.HasPrefix(m.AccountStepDeploymentID, "93d12293-3933-4d98-4b13-a8b357fb4697#CUSTOMER#logging#"), m.Result, "AccountStepDeploymentID contains correct prefix")
 	}
 }
+
+func TestFlushTrack_ShouldReportFailedRegionalRegions(t *testing.T) {
+	var test = map[string]struct {
+		failedRegions         []string
+		expectedFailedRegions []string
+		expectedResult        string
+	}{
+		"one of three regional regions failed": {
+			failedRegions:         []string{"us-east-2"},
+			expectedFailedRegions: []string{"regional/us-east-2"},
+			expectedResult:        cloudaccountdeployment.Unstable.String(),
+		},
+		"two of three regional regions failed": {
+			failedRegions:         []string{"us-west-2", "us-east-1"},
+			expectedFailedRegions: []string{"regional/us-east-1", "regional/us-west-2"},
+			expectedResult:        cloudaccountdeployment.Unstable.String(),
+		},
+		"all regional regions failed": {
+			failedRegions:         []string{"us-east-1", "us-east-2", "us-west-2"},
+			expectedFailedRegions: []string{"regional/us-east-1", "regional/us-east-2", "regional/us-west-2"},
+			expectedResult:        cloudaccountdeployment.Fail.String(),
+		},
+		"no regional regions failed": {
+			expectedFailedRegions: []string{},
+			expectedResult:        cloudaccountdeployment.Success.String(),
+		},
+	}
+
+	for name, tc := range test {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+
+			cloudaccountdeployment.RecordStepSuccess(logger, "", "network", "vpc", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+			cloudaccountdeployment.RecordStepSuccess(logger, "", "network", "dns", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+
+			for _, reg := range stubConfig.RegionalRegions {
+				if contains(tc.failedRegions, reg) {
+					cloudaccountdeployment.RecordStepFail(logger, "", "network", "vpc", config.RegionalRegionDeployType.String(), reg, stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions, fmt.Errorf("apply failed"))
+				} else {
+					cloudaccountdeployment.RecordStepSuccess(logger, "", "network", "vpc", config.RegionalRegionDeployType.String(), reg, stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+				}
+			}
+
+			// act
+			steps, err := cloudaccountdeployment.FlushTrack(logger, "network")
+
+			// assert
+			require.NoError(t, err)
+
+			vpc := steps[stubConfig.UniqueExternalExecutionID+"#"+stubConfig.Project+"#network#vpc"]
+			require.NotNil(t, vpc)
+			require.Equal(t, tc.expectedFailedRegions, vpc.FailedRegions, "Only the failed regional regions should be reported")
+			require.Equal(t, tc.expectedResult, vpc.Result)
+			require.Len(t, vpc.Executions, len(stubConfig.RegionalRegions)+1, "Every region's outcome should be recorded")
+
+			if len(tc.failedRegions) > 0 {
+				require.Contains(t, vpc.ResultMessage, "Failed executions: "+strings.Join(tc.expectedFailedRegions, ", "))
+			}
+
+			dns := steps[stubConfig.UniqueExternalExecutionID+"#"+stubConfig.Project+"#network#dns"]
+			require.NotNil(t, dns)
+			require.Empty(t, dns.FailedRegions, "A step without failed executions should not report failed regions")
+			require.Equal(t, cloudaccountdeployment.Success.String(), dns.Result)
+		})
+	}
+}
+
+func TestFlushTrack_ShouldReportFailedStepAndRemoveIt(t *testing.T) {
+	// arrange
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+
+	cloudaccountdeployment.RecordStepStart(logger, stubConfig.AccountID, "network", "vpc", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.DryRun, "", stubConfig.Version, stubConfig.UniqueExternalExecutionID, "", "", stubConfig.Project, stubConfig.RegionalRegions)
+	cloudaccountdeployment.RecordStepFail(logger, "", "network", "vpc", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions, fmt.Errorf("apply failed"))
+
+	// act
+	steps, err := cloudaccountdeployment.FlushTrack(logger, "network")
+
+	// assert
+	require.NoError(t, err)
+
+	vpc := steps[stubConfig.UniqueExternalExecutionID+"#"+stubConfig.Project+"#network#vpc"]
+	require.NotNil(t, vpc)
+	require.Equal(t, cloudaccountdeployment.Fail.String(), vpc.Result, "A step whose primary deployment failed should fail")
+	require.Equal(t, []string{"primary/us-east-1"}, vpc.FailedRegions)
+	require.Contains(t, vpc.ResultMessage, "Failed executions: primary/us-east-1")
+	require.Empty(t, cloudaccountdeployment.StepDeployments, "The failed step should be removed once flushed")
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestFlushTrack_ShouldOnlyRemoveFlushedTrackWhileOtherTrackIsRecording(t *testing.T) {
+	// arrange
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+
+	flushedTrack := "flushed"
+	recordingTrack := "recording"
+	stubStepCount := 200
+
+	for i := 0; i < stubStepCount; i++ {
+		cloudaccountdeployment.RecordStepSuccess(logger, "", flushedTrack, fmt.Sprintf("step-%d", i), config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// act
+	go func() {
+		defer wg.Done()
+		for i := 0; i < stubStepCount; i++ {
+			stubStep := fmt.Sprintf("step-%d", i)
+			cloudaccountdeployment.RecordStepStart(logger, stubConfig.AccountID, recordingTrack, stubStep, config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.DryRun, "", stubConfig.Version, stubConfig.UniqueExternalExecutionID, "", "", stubConfig.Project, stubConfig.RegionalRegions)
+			cloudaccountdeployment.RecordStepSuccess(logger, "", recordingTrack, stubStep, config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+		}
+	}()
+
+	var flushedSteps int
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			steps, err := cloudaccountdeployment.FlushTrack(logger, flushedTrack)
+			if err != nil {
+				t.Error(err)
+			}
+			flushedSteps += len(steps)
+		}
+	}()
+
+	wg.Wait()
+
+	// assert
+	require.Equal(t, stubStepCount, flushedSteps, "Each of the flushed track's steps should be flushed exactly once")
+
+	recordedSteps, err := cloudaccountdeployment.FlushTrack(logger, recordingTrack)
+	require.NoError(t, err)
+	require.Len(t, recordedSteps, stubStepCount, "Flushing a track should not remove steps of a track that is still recording")
+}
+
+func TestRecordStep_ShouldBeSafeForConcurrentRecordsAcrossTracks(t *testing.T) {
+	// arrange
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+
+	stubTrackCount := 8
+	stubStepCount := 25
+	regions := append([]string{"us-east-1"}, stubConfig.RegionalRegions...)
+
+	var wg sync.WaitGroup
+
+	// act
+	for tI := 0; tI < stubTrackCount; tI++ {
+		stubTrack := fmt.Sprintf("track%d", tI)
+		for i := 0; i < stubStepCount; i++ {
+			stubStep := fmt.Sprintf("step-%d", i)
+			for rI, reg := range regions {
+				regionDeployType := config.RegionalRegionDeployType.String()
+				if rI == 0 {
+					regionDeployType = config.PrimaryRegionDeployType.String()
+				}
+
+				wg.Add(1)
+				go func(i int, stubStep string, regionDeployType string, reg string) {
+					defer wg.Done()
+					cloudaccountdeployment.RecordStepStart(logger, stubConfig.AccountID, stubTrack, stubStep, regionDeployType, reg, stubConfig.DryRun, "", stubConfig.Version, stubConfig.UniqueExternalExecutionID, "", "", stubConfig.Project, stubConfig.RegionalRegions)
+
+					if i%5 == 0 {
+						cloudaccountdeployment.RecordStepFail(logger, "", stubTrack, stubStep, regionDeployType, reg, stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions, fmt.Errorf("failed"))
+					} else if i%7 == 0 {
+						cloudaccountdeployment.RecordStepTestFail(logger, "", stubTrack, stubStep, regionDeployType, reg, stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions, fmt.Errorf("tests failed"))
+					} else {
+						cloudaccountdeployment.RecordStepSuccess(logger, "", stubTrack, stubStep, regionDeployType, reg, stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+					}
+				}(i, stubStep, regionDeployType, reg)
+			}
+		}
+	}
+
+	// tracks that never recorded steps are flushed while the other tracks are recording
+	for tI := 0; tI < stubTrackCount; tI++ {
+		wg.Add(1)
+		go func(tI int) {
+			defer wg.Done()
+			if _, err := cloudaccountdeployment.FlushTrack(logger, fmt.Sprintf("idle%d", tI)); err != nil {
+				t.Error(err)
+			}
+		}(tI)
+	}
+
+	wg.Wait()
+
+	// assert
+	for tI := 0; tI < stubTrackCount; tI++ {
+		steps, err := cloudaccountdeployment.FlushTrack(logger, fmt.Sprintf("track%d", tI))
+		require.NoError(t, err)
+		require.Len(t, steps, stubStepCount, "Every step of every track should be recorded")
+
+		for _, step := range steps {
+			require.Len(t, step.Executions, len(regions), "Every region of every step should be recorded")
+		}
+	}
+
+	require.Empty(t, cloudaccountdeployment.StepDeployments, "Flushing every track should remove every recorded step")
+}
+
+func TestFlushTrack_ShouldReturnNothingForTrackWithoutRecordedSteps(t *testing.T) {
+	// arrange
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+	cloudaccountdeployment.RecordStepSuccess(logger, "", "nonexistent-prefix", "vpc", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+
+	// act
+	steps, err := cloudaccountdeployment.FlushTrack(logger, "nonexistent")
+	again, againErr := cloudaccountdeployment.FlushTrack(logger, "nonexistent")
+
+	// assert
+	require.NoError(t, err)
+	require.NotNil(t, steps)
+	require.Empty(t, steps, "A track without recorded steps should have nothing to report")
+	require.NoError(t, againErr)
+	require.Empty(t, again)
+	require.Len(t, cloudaccountdeployment.StepDeployments, 1, "Tracks whose names share a prefix should not be flushed")
+}
+
+func TestFlushTrack_ShouldNotRemoveStepsOfTrackFlushedConcurrently(t *testing.T) {
+	// arrange
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+
+	flushedTracks := []string{"network", "app"}
+	stubStepCount := 100
+
+	for _, track := range flushedTracks {
+		for i := 0; i < stubStepCount; i++ {
+			cloudaccountdeployment.RecordStepSuccess(logger, "", track, fmt.Sprintf("step-%d", i), config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+		}
+	}
+
+	flushedSteps := make([]int, len(flushedTracks))
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	// act
+	for tI, track := range flushedTracks {
+		for flush := 0; flush < 10; flush++ {
+			wg.Add(1)
+			go func(tI int, track string) {
+				defer wg.Done()
+
+				steps, err := cloudaccountdeployment.FlushTrack(logger, track)
+				if err != nil {
+					t.Error(err)
+				}
+
+				for stepID := range steps {
+					if !strings.Contains(stepID, "#"+track+"#") {
+						t.Errorf("step %s was flushed with track %s", stepID, track)
+					}
+				}
+
+				mutex.Lock()
+				flushedSteps[tI] += len(steps)
+				mutex.Unlock()
+			}(tI, track)
+		}
+	}
+
+	wg.Wait()
+
+	// assert
+	for tI, track := range flushedTracks {
+		require.Equal(t, stubStepCount, flushedSteps[tI], "Each of the steps of track %s should be flushed exactly once", track)
+	}
+	require.Empty(t, cloudaccountdeployment.StepDeployments)
+}
+
+func TestFlushTrack_ShouldOnlyReportStatusWhenStatusReportingIsEnabled(t *testing.T) {
+	var test = map[string]struct {
+		disableStatusReporting bool
+		expectedReports        int
+	}{
+		"enabled":  {disableStatusReporting: false, expectedReports: 2},
+		"disabled": {disableStatusReporting: true, expectedReports: 0},
+	}
+
+	for name, tc := range test {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			defer func(previous bool) { cloudaccountdeployment.Cfg.DisableStatusReporting = previous }(cloudaccountdeployment.Cfg.DisableStatusReporting)
+			cloudaccountdeployment.Cfg.DisableStatusReporting = tc.disableStatusReporting
+
+			var reported []cloudaccountdeployment.UpdateRegionalStatusPayload
+			cloudaccountdeployment.ReportRegionalStatus = func(logger *logrus.Entry, payload cloudaccountdeployment.UpdateRegionalStatusPayload) error {
+				reported = append(reported, payload)
+				return nil
+			}
+			defer func() { cloudaccountdeployment.ReportRegionalStatus = nil }()
+
+			cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+			cloudaccountdeployment.RecordStepSuccess(logger, "", "network", "vpc", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+			cloudaccountdeployment.RecordStepFail(logger, "", "network", "dns", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions, fmt.Errorf("apply failed"))
+
+			// act
+			steps, err := cloudaccountdeployment.FlushTrack(logger, "network")
+
+			// assert
+			require.NoError(t, err)
+			require.Len(t, steps, 2, "Steps should be recorded and summarized regardless of status reporting")
+			require.Len(t, reported, tc.expectedReports)
+		})
+	}
+}
+
+func TestFlushTrack_ShouldReturnStatusReportingErrors(t *testing.T) {
+	// arrange
+	cloudaccountdeployment.ReportRegionalStatus = func(logger *logrus.Entry, payload cloudaccountdeployment.UpdateRegionalStatusPayload) error {
+		return fmt.Errorf("status backend unavailable")
+	}
+	defer func() { cloudaccountdeployment.ReportRegionalStatus = nil }()
+
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+	cloudaccountdeployment.RecordStepSuccess(logger, "", "network", "vpc", config.PrimaryRegionDeployType.String(), "us-east-1", stubConfig.UniqueExternalExecutionID, stubConfig.Project, stubConfig.RegionalRegions)
+
+	// act
+	steps, err := cloudaccountdeployment.FlushTrack(logger, "network")
+
+	// assert
+	require.EqualError(t, err, "status backend unavailable")
+	require.Len(t, steps, 1, "Steps should be summarized even when their status could not be reported")
+	require.Empty(t, cloudaccountdeployment.StepDeployments)
+}
+
+func TestFlushTrack_ShouldUseCustomStepIDDelimiter(t *testing.T) {
+	// arrange
+	defer func(previous string) { cloudaccountdeployment.Cfg.StepIDDelimiter = previous }(cloudaccountdeployment.Cfg.StepIDDelimiter)
+	cloudaccountdeployment.Cfg.StepIDDelimiter = "|"
+
+	cloudaccountdeployment.StepDeployments = map[string]cloudaccountdeployment.ExecutionResult{}
+	cloudaccountdeployment.RecordStepSuccess(logger, "", "logging", "flow_logs", config.PrimaryRegionDeployType.String(), "us-east-1", "taskID", "project#1", []string{"us-east-1"})
+	cloudaccountdeployment.RecordStepSuccess(logger, "", "archive", "flow_logs", config.PrimaryRegionDeployType.String(), "us-east-1", "taskID", "project#1", []string{"us-east-1"})
+
+	// act
+	steps, err := cloudaccountdeployment.FlushTrack(logger, "logging")
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, steps, 1, "Only the flushed track's steps should be flushed")
+	require.Contains(t, steps, "taskID|project#1|logging|flow_logs", "Project names containing the default delimiter should not corrupt the id")
+	require.Equal(t, cloudaccountdeployment.Success.String(), steps["taskID|project#1|logging|flow_logs"].Result)
+}
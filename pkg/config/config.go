@@ -6,6 +6,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -37,6 +41,83 @@ type Config struct {
 	LogLevel                  string          `mapstructure:"log_level"`
 	CoreAccounts              CoreAccountsMap `mapstructure:"core_accounts"`
 	RegionGroups              RegionGroupsMap `mapstructure:"region_grouprs"`
+	ShutdownGracePeriod       time.Duration   `mapstructure:"shutdown_grace_period"`        // Time given to in-flight runner processes (e.g. terraform) to exit after cancellation before they are killed
+	PolicyCommand             string          `mapstructure:"policy_command"`               // Command run against each step's plan JSON before apply (e.g. conftest test), a nonzero exit fails the step
+	PolicyWarnOnly            bool            `mapstructure:"policy_warn_only"`             // When true, policy failures are logged as warnings instead of failing the step
+	OutputVariablesDir        string          `mapstructure:"output_variables_dir"`         // When set, each track's output variables are written to {dir}/{track}/{regionDeployType}-{region}.json
+	RegionStatusDir           string          `mapstructure:"region_status_dir"`            // When set, the status and timestamp of each track region execution are written to {dir}/{track}/{region}.status as it starts and completes
+	FailOnEmptySteps          bool            `mapstructure:"fail_on_empty_steps"`          // When true, a step directory without runnable content excludes its track with an error instead of skipping the step with a warning
+	BeforeAllCommand          string          `mapstructure:"before_all_command"`           // Command run once before any track executes, a failure aborts the deployment. {run_id} is replaced with the unique external execution id
+	AfterAllCommand           string          `mapstructure:"after_all_command"`            // Command run once after all tracks (and destroys) complete, failures are logged. {run_id} is replaced with the unique external execution id
+	SlowestStepsReportCount   int             `mapstructure:"slowest_steps_report_count"`   // When greater than zero, the summary includes a timing report with this many of the slowest steps
+	CloudEventsSink           string          `mapstructure:"cloud_events_sink"`            // When set, a CloudEvents deployment completed event is posted to this URL
+	TestAgainstPlan           bool            `mapstructure:"test_against_plan"`            // Implies DryRun, step tests run against each step's plan in plan assertion mode instead of being skipped
+	MaxOutputValueBytes       int             `mapstructure:"max_output_value_bytes"`       // When greater than zero, step output values larger than this are truncated (structured values are rejected) before being passed along
+	RejectOversizedOutputs    bool            `mapstructure:"reject_oversized_outputs"`     // When true, output values larger than MaxOutputValueBytes are dropped instead of truncated
+	TrackRoots                []string        `mapstructure:"track_roots"`                  // Directories each containing a tracks directory to gather tracks from. Defaults to the working directory
+	ApprovalCommand           string          `mapstructure:"approval_command"`             // Command run when a track pauses for approval, exiting zero approves. Approval is denied when unset
+	SinceLastSuccess          bool            `mapstructure:"since_last_success"`           // When true, only steps whose content changed since their last successful apply recorded in ManifestFile are executed
+	ManifestFile              string          `mapstructure:"manifest_file"`                // The file step content hashes are recorded to after a successful apply
+	EmitInventory             bool            `mapstructure:"emit_inventory"`               // When true, the resources managed by each step are listed after apply and reported as an inventory
+	MaxTrackDepth             int             `mapstructure:"max_track_depth"`              // The directory depth below a tracks directory gathering may descend to, tracks are at 1 and steps at 2
+	Stages                    []string        `mapstructure:"stages"`                       // Ordered stage names tracks are grouped into, each stage's tracks complete before the next stage begins
+	ProviderUserAgentSuffix   string          `mapstructure:"provider_user_agent_suffix"`   // Appended to the user agent of provider API calls for tracing. {run_id} is replaced with the unique external execution id
+	RetryableFailureRetries   int             `mapstructure:"retryable_failure_retries"`    // The times a step whose failure is classified as retryable is executed again
+	TrackOrder                []string        `mapstructure:"track_order"`                  // Track names executed one at a time in this order, unlisted tracks are executed in parallel afterwards
+	TrackOrderExcludeUnlisted bool            `mapstructure:"track_order_exclude_unlisted"` // When true, tracks missing from TrackOrder are skipped instead of executed afterwards
+	RequireStepOutputs        bool            `mapstructure:"require_step_outputs"`         // When true, a successful step deploy that exports no output variables fails the step
+	CleanEnv                  bool            `mapstructure:"clean_env"`                    // When true, runners only inherit the environment variables in CleanEnvAllowlist (plus PATH, HOME and TMPDIR)
+	CleanEnvAllowlist         []string        `mapstructure:"clean_env_allowlist"`          // Environment variable names, or prefixes ending in *, runners inherit when CleanEnv is set, e.g. AWS_*
+	IncludeDependencies       bool            `mapstructure:"include_dependencies"`         // When true, targeting a track's steps also targets every step of the tracks it transitively depends on
+	PlanBundleFile            string          `mapstructure:"plan_bundle_file"`             // When set, every step's plan is written along with the resolved configuration and a manifest of the plans to this .tar.gz bundle
+	ApplyBundle               string          `mapstructure:"apply_bundle"`                 // A plan bundle whose plans are applied instead of planning each step
+	TerraformParallelism      int             `mapstructure:"terraform_parallelism"`        // When greater than zero, limits terraform's concurrent operations during plan and apply with -parallelism, steps may override it
+	RegionalOutputKeyStrategy string          `mapstructure:"regional_output_key_strategy"` // How regional step output variables are keyed for later steps: suffix (default), prefix or nested
+	DestroyPreview            bool            `mapstructure:"destroy_preview"`              // When true, every track is planned and then destroy planned without applying, reporting the resources a destroy would delete
+	SmokeDeploy               bool            `mapstructure:"smoke_deploy"`                 // When true, only the first step progression of each track is deployed to the primary region, the rest are skipped
+	RegionalOnly              bool            `mapstructure:"regional_only"`                // When true, only regional regions are deployed, using the primary step outputs persisted to OutputVariablesDir by an earlier deployment
+	AllowDestroyPlanChanges   bool            `mapstructure:"allow_destroy_plan_changes"`   // When true, destroy plans that would create or update resources (e.g. due to drift) are applied instead of failing the step
+	PrintOutputs              []string        `mapstructure:"print_outputs"`                // Output variable names, optionally qualified by track (e.g. app.endpoint_url), printed to stdout when the deployment completes
+	MockProvider              bool            `mapstructure:"mock_provider"`                // When true, steps are simulated with the outputs in MockFixturesFile instead of calling their runner or cloud provider
+	MockFixturesFile          string          `mapstructure:"mock_fixtures_file"`           // JSON file of the outputs of each simulated step, e.g. {"track": {"step": {"output": "value"}}}
+	ChannelBufferSize         int             `mapstructure:"channel_buffer_size"`          // The buffer size of the step and test result channels, larger buffers reduce goroutine handoff for wide step progressions
+	RegionalTestRegions       []string        `mapstructure:"regional_test_regions"`        // When set, regional tests are only executed in these regions, regional steps are still deployed to every regional region
+	EmitPlanJSON              string          `mapstructure:"emit_plan_json"`               // When set, the resolved execution plan of the tracks, their order, regions and steps, is written as JSON to this file before executing
+	FileMode                  os.FileMode     `mapstructure:"file_mode"`                    // When set, e.g. 0640, the permissions of the files runiac creates, including working copies of step files
+	DirMode                   os.FileMode     `mapstructure:"dir_mode"`                     // When set, e.g. 0750, the permissions of the directories runiac creates, including working copies of step directories
+	AdHocRegion               string          `mapstructure:"ad_hoc_region"`                // When set, the regional phase deploys and destroys only this region instead of RegionalRegions, e.g. to try out a new region
+	FailedStepsIncludeTests   bool            `mapstructure:"failed_steps_include_tests"`   // When true, steps whose tests failed while their deploy succeeded are included in each region execution's failed steps
+	WorkdirRetention          string          `mapstructure:"workdir_retention"`            // Whether regional working directories are kept after their region execution, one of the WorkdirRetention values, defaults to onFailure
+	AllowedRegions            []string        `mapstructure:"allowed_regions"`              // When set, the deployment fails before executing any step if a configured region is not one of these regions
+	MaxParallelTracks         int             `mapstructure:"max_parallel_tracks"`          // When greater than zero, limits the number of tracks deployed or destroyed concurrently
+	ResultStreamURL           string          `mapstructure:"result_stream_url"`            // When set, step and track results are posted to this URL in batches as they complete
+	ResultStreamBufferSize    int             `mapstructure:"result_stream_buffer_size"`    // The number of results buffered for a slow result stream before results are dropped
+	RetryBackoff              time.Duration   `mapstructure:"retry_backoff"`                // The wait before retrying a step with a retryable failure, doubling for each further retry
+	RetryablePattern          string          `mapstructure:"retryable_pattern"`            // When set, failed steps whose error or output matches this regular expression are retryable, e.g. eventual consistency errors
+	ResultsJSON               string          `mapstructure:"results_json"`                 // When set, the results of the tracks, their region executions and step output variables, are written as JSON to this file once the deployment completes
+	JUnitReportFile           string          `mapstructure:"junit_report_file"`            // When set, a JUnit XML report of each track's steps and step tests is written to this file once the deployment completes
+	TracksDir                 string          `mapstructure:"tracks_dir"`                   // The directory named tracks are read from, relative to each track root. Its parent directory holds the steps of the default track
+	SkipPreTrack              bool            `mapstructure:"skip_pretrack"`                // When true, the pretrack is neither deployed nor destroyed, tracks receive its outputs persisted in OutputVariablesDir instead
+	DestroyOnly               bool            `mapstructure:"destroy_only"`                 // When true, tracks are destroyed without being deployed, using the step output variables persisted to OutputVariablesDir by an earlier deployment
+	ContinueOnError           bool            `mapstructure:"continue_on_error"`            // When true, steps in later progressions are still executed after a step fails, the track is still failed
+	StepIDDelimiter           string          `mapstructure:"step_id_delimiter"`            // Separates the names in step ids, e.g. #project#track#step, must not be part of the project, track or step names
+	TrackTimeout              time.Duration   `mapstructure:"track_timeout"`                // When greater than zero, a track deployed, or destroyed, for longer fails with a timeout error and its remaining steps are cancelled
+	StrictOutputVariables     bool            `mapstructure:"strict_output_variables"`      // When true, a step overwriting an output variable set by a different step fails, otherwise a warning is logged
+	CopyDefaultTrack          bool            `mapstructure:"copy_default_track"`           // When true, the default track's steps are copied to {tracks dir}/default when top-level terraform files exist
+	DestroyRegions            []string        `mapstructure:"destroy_regions"`              // When set, tracks are only destroyed in these regions, the primary region included only when listed
+	DisableStatusReporting    bool            `mapstructure:"disable_status_reporting"`     // Step statuses are still recorded and summarized, but not reported to the status backend
+	CSP                       string          `mapstructure:"csp"`                          // The cloud service provider being deployed to (e.g. AWS or AZU), steps targeting another CSP are skipped
+	BundledPlansDir           string          // Set when applying a plan bundle, the directory the bundle's plans were extracted to
+	// Default track step ids omit the track name, e.g. #project#step, unless DefaultTrackIDIncludesName is set, e.g. #project#default#step
+	DefaultTrackIDIncludesName bool `mapstructure:"default_track_id_includes_name"`
+	// When true, top-level step directories that would be copied into an auto-created default track fail the run instead
+	FailOnDefaultTrackCreation bool `mapstructure:"fail_on_default_track_creation"`
+	// When greater than zero, limits the steps executed concurrently within each progression level of a region, e.g. to avoid provider API throttling
+	MaxParallelStepsPerProgression int `mapstructure:"max_parallel_steps_per_progression"`
+
+	ExtraStepInputs           map[string]string `mapstructure:"extra_step_inputs"`             // K={step}-{output variable}, V=the value every step receives as if the step output it, e.g. {"vpc-vpc_id": "vpc-123"}
+	ExtraStepInputsAsDefaults bool              `mapstructure:"extra_step_inputs_as_defaults"` // When true, the step output variables named by extra step inputs take precedence over them
+
 	// Set at task definition creation
 	Namespace   string `mapstructure:"namespace"`                   // The namespace to use in the Terraform run.
 	Environment string `mapstructure:"environment" required:"true"` // The name of the environment (e.g. pr, nonprod, prod)
@@ -120,6 +201,77 @@ func GetConfig() (Config, error) {
 	_ = viper.BindEnv("max_retries")
 	_ = viper.BindEnv("max_test_retries")
 	_ = viper.BindEnv("account_id")
+	_ = viper.BindEnv("shutdown_grace_period")
+	_ = viper.BindEnv("policy_command")
+	_ = viper.BindEnv("policy_warn_only")
+	_ = viper.BindEnv("output_variables_dir")
+	_ = viper.BindEnv("region_status_dir")
+	_ = viper.BindEnv("fail_on_empty_steps")
+	_ = viper.BindEnv("before_all_command")
+	_ = viper.BindEnv("after_all_command")
+	_ = viper.BindEnv("slowest_steps_report_count")
+	_ = viper.BindEnv("cloud_events_sink")
+	_ = viper.BindEnv("test_against_plan")
+	_ = viper.BindEnv("max_output_value_bytes")
+	_ = viper.BindEnv("reject_oversized_outputs")
+	_ = viper.BindEnv("track_roots")
+	_ = viper.BindEnv("approval_command")
+	_ = viper.BindEnv("since_last_success")
+	_ = viper.BindEnv("manifest_file")
+	_ = viper.BindEnv("emit_inventory")
+	_ = viper.BindEnv("max_track_depth")
+	_ = viper.BindEnv("stages")
+	_ = viper.BindEnv("provider_user_agent_suffix")
+	_ = viper.BindEnv("retryable_failure_retries")
+	_ = viper.BindEnv("track_order")
+	_ = viper.BindEnv("track_order_exclude_unlisted")
+	_ = viper.BindEnv("require_step_outputs")
+	_ = viper.BindEnv("clean_env")
+	_ = viper.BindEnv("clean_env_allowlist")
+	_ = viper.BindEnv("include_dependencies")
+	_ = viper.BindEnv("plan_bundle_file")
+	_ = viper.BindEnv("apply_bundle")
+	_ = viper.BindEnv("terraform_parallelism")
+	_ = viper.BindEnv("regional_output_key_strategy")
+	_ = viper.BindEnv("destroy_preview")
+	_ = viper.BindEnv("smoke_deploy")
+	_ = viper.BindEnv("regional_only")
+	_ = viper.BindEnv("allow_destroy_plan_changes")
+	_ = viper.BindEnv("print_outputs")
+	_ = viper.BindEnv("regional_test_regions")
+	_ = viper.BindEnv("emit_plan_json")
+	_ = viper.BindEnv("file_mode")
+	_ = viper.BindEnv("dir_mode")
+	_ = viper.BindEnv("ad_hoc_region")
+	_ = viper.BindEnv("failed_steps_include_tests")
+	_ = viper.BindEnv("workdir_retention")
+	_ = viper.BindEnv("allowed_regions")
+	_ = viper.BindEnv("max_parallel_tracks")
+	_ = viper.BindEnv("result_stream_url")
+	_ = viper.BindEnv("result_stream_buffer_size")
+	_ = viper.BindEnv("retry_backoff")
+	_ = viper.BindEnv("retryable_pattern")
+	_ = viper.BindEnv("results_json")
+	_ = viper.BindEnv("junit_report_file")
+	_ = viper.BindEnv("tracks_dir")
+	_ = viper.BindEnv("skip_pretrack")
+	_ = viper.BindEnv("destroy_only")
+	_ = viper.BindEnv("continue_on_error")
+	_ = viper.BindEnv("step_id_delimiter")
+	_ = viper.BindEnv("track_timeout")
+	_ = viper.BindEnv("strict_output_variables")
+	_ = viper.BindEnv("copy_default_track")
+	_ = viper.BindEnv("destroy_regions")
+	_ = viper.BindEnv("disable_status_reporting")
+	_ = viper.BindEnv("csp")
+	_ = viper.BindEnv("extra_step_inputs")
+	_ = viper.BindEnv("extra_step_inputs_as_defaults")
+	_ = viper.BindEnv("mock_provider")
+	_ = viper.BindEnv("mock_fixtures_file")
+	_ = viper.BindEnv("channel_buffer_size")
+	_ = viper.BindEnv("default_track_id_includes_name")
+	_ = viper.BindEnv("fail_on_default_track_creation")
+	_ = viper.BindEnv("max_parallel_steps_per_progression")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -130,12 +282,24 @@ func GetConfig() (Config, error) {
 	}
 
 	conf := &Config{
-		MaxTestRetries: 2,
-		MaxRetries:     3,
-		LogLevel:       logrus.InfoLevel.String(),
-		Project:        "runiac",
-		TargetAll:      true,
+		MaxTestRetries:         2,
+		MaxRetries:             3,
+		LogLevel:               logrus.InfoLevel.String(),
+		Project:                "runiac",
+		TargetAll:              true,
+		ShutdownGracePeriod:    60 * time.Second,
+		ManifestFile:           "runiac-manifest.json",
+		MaxTrackDepth:          8,
+		ResultStreamBufferSize: 1000,
+		TracksDir:              "./tracks",
+		StepIDDelimiter:        DefaultStepIDDelimiter,
+	}
+
+	// environment variables can not be decoded into maps, read extra step inputs set in one as JSON
+	if inputs := viper.GetStringMapString("extra_step_inputs"); len(inputs) > 0 {
+		viper.Set("extra_step_inputs", inputs)
 	}
+
 	err := viper.Unmarshal(conf)
 
 	if err != nil {
@@ -156,12 +320,23 @@ func GetConfig() (Config, error) {
 		conf.TargetAll = false
 	}
 
+	// testing against the plan must never apply
+	if conf.TestAgainstPlan {
+		conf.DryRun = true
+	}
+
 	return *conf, nil
 }
 
 func InputValidation(sl validator.StructLevel) {
 	input := sl.Current().Interface().(Config)
 
+	for name := range input.ExtraStepInputs {
+		if _, _, ok := SplitExtraStepInput(name); !ok {
+			sl.ReportError(input.ExtraStepInputs, "extra_step_inputs", "extraStepInputs", "invalid-extra-step-input", name)
+		}
+	}
+
 	if input.Environment == "" {
 		sl.ReportError(input.Namespace, "environment", "environment", "required-environment", "")
 	}
@@ -169,4 +344,89 @@ func InputValidation(sl validator.StructLevel) {
 	if input.PrimaryRegion == "" {
 		sl.ReportError(input.Namespace, "primary_region", "primaryRegion", "required-primary-region", "")
 	}
+
+	if strings.Contains(input.Project, input.IDDelimiter()) {
+		sl.ReportError(input.Project, "project", "project", "invalid-project-step-id-delimiter", "")
+	}
+
+	for _, entry := range input.StepWhitelist {
+		if pattern := strings.TrimPrefix(entry, StepWhitelistRegexPrefix); pattern != entry {
+			if _, err := regexp.Compile(pattern); err != nil {
+				sl.ReportError(input.StepWhitelist, "step_whitelist", "stepWhitelist", "invalid-step-whitelist-regex", "")
+			}
+		} else if _, err := path.Match(entry, ""); err != nil {
+			sl.ReportError(input.StepWhitelist, "step_whitelist", "stepWhitelist", "invalid-step-whitelist-pattern", "")
+		}
+	}
+
+	if len(input.TrackOrder) > 0 && len(input.Stages) > 0 {
+		sl.ReportError(input.TrackOrder, "track_order", "trackOrder", "exclusive-track-order-stages", "")
+	}
+
+	if input.RegionalOnly && input.OutputVariablesDir == "" {
+		sl.ReportError(input.RegionalOnly, "regional_only", "regionalOnly", "required-output-variables-dir", "")
+	}
+
+	if input.DestroyOnly && input.OutputVariablesDir == "" {
+		sl.ReportError(input.DestroyOnly, "destroy_only", "destroyOnly", "required-output-variables-dir", "")
+	}
+
+	if input.RegionalOnly && input.SmokeDeploy {
+		sl.ReportError(input.RegionalOnly, "regional_only", "regionalOnly", "exclusive-regional-only-smoke-deploy", "")
+	}
+
+	if input.TrackTimeout < 0 {
+		sl.ReportError(input.TrackTimeout, "track_timeout", "trackTimeout", "invalid-track-timeout", "")
+	}
+
+	if input.ChannelBufferSize < 0 {
+		sl.ReportError(input.ChannelBufferSize, "channel_buffer_size", "channelBufferSize", "invalid-channel-buffer-size", "")
+	}
+
+	if input.MaxParallelTracks < 0 {
+		sl.ReportError(input.MaxParallelTracks, "max_parallel_tracks", "maxParallelTracks", "invalid-max-parallel-tracks", "")
+	}
+
+	if input.MaxParallelStepsPerProgression < 0 {
+		sl.ReportError(input.MaxParallelStepsPerProgression, "max_parallel_steps_per_progression", "maxParallelStepsPerProgression", "invalid-max-parallel-steps-per-progression", "")
+	}
+
+	if input.ResultStreamBufferSize < 0 {
+		sl.ReportError(input.ResultStreamBufferSize, "result_stream_buffer_size", "resultStreamBufferSize", "invalid-result-stream-buffer-size", "")
+	}
+
+	if _, err := regexp.Compile(input.RetryablePattern); err != nil {
+		sl.ReportError(input.RetryablePattern, "retryable_pattern", "retryablePattern", "invalid-retryable-pattern", "")
+	}
+
+	if input.FileMode&^os.ModePerm != 0 {
+		sl.ReportError(input.FileMode, "file_mode", "fileMode", "invalid-file-mode", "")
+	}
+
+	if input.DirMode&^os.ModePerm != 0 {
+		sl.ReportError(input.DirMode, "dir_mode", "dirMode", "invalid-dir-mode", "")
+	}
+
+	switch input.WorkdirRetention {
+	case "", AlwaysRetainWorkdirs, RetainWorkdirsOnFailure, NeverRetainWorkdirs:
+	default:
+		sl.ReportError(input.WorkdirRetention, "workdir_retention", "workdirRetention", "invalid-workdir-retention", "")
+	}
+
+	switch input.RegionalOutputKeyStrategy {
+	case "", SuffixRegionalOutputKey, PrefixRegionalOutputKey, NestedRegionalOutputKey:
+	default:
+		sl.ReportError(input.RegionalOutputKeyStrategy, "regional_output_key_strategy", "regionalOutputKeyStrategy", "invalid-regional-output-key-strategy", "")
+	}
+}
+
+// SplitExtraStepInput splits the name of an extra step input, {step}-{output variable}, at its last dash. The name is
+// invalid when either part is empty
+func SplitExtraStepInput(name string) (step string, outputVariable string, ok bool) {
+	i := strings.LastIndex(name, "-")
+	if i <= 0 || i == len(name)-1 {
+		return "", "", false
+	}
+
+	return name[:i], name[i+1:], true
 }
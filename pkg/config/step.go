@@ -1,6 +1,11 @@
 package config
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
@@ -35,6 +40,22 @@ type StepExecution struct {
 	DefaultStepOutputVariables map[string]map[string]string // Previous step output variables are available in this map. K=StepName,V=map[VarName:VarVal]
 	OptionalStepParams         map[string]string
 	RequiredStepParams         map[string]interface{}
+	Context                    context.Context // Cancelling this context interrupts any in-flight runner processes
+	ShutdownGracePeriod        time.Duration   // The time an interrupted runner process has to exit before it is killed
+	PolicyCommand              string          // Command to validate the step's plan JSON with before apply
+	PolicyWarnOnly             bool            // Policy failures are only logged when true
+	TestAgainstPlan            bool            // When true, the step is only planned and its tests run in plan assertion mode
+	EmitInventory              bool            // When true, the resources managed by the step are listed after apply
+	ExpectedOutputs            []string        // Output variables the step must export after a successful deploy in this region
+	ExpectedOutputsWarnOnly    bool            // Missing expected outputs are only logged when true
+	RequireOutputs             bool            // When true, a successful deploy must export at least one output variable
+	TestRunner                 string          // The name of the test runner forced by the step's configuration
+	TestCommand                string          // The command run by the command test runner
+	ProviderUserAgentSuffix    string          // Appended to the user agent of the runner's provider API calls
+	EnvAllowlist               []string        // When set, only these inherited environment variables (names, or prefixes ending in *) reach the runner
+	BundledPlanFile            string          // When set, this previously captured plan is applied instead of planning the step
+	TerraformParallelism       int             // When greater than zero, limits terraform's concurrent operations during plan and apply
+	AllowDestroyPlanChanges    bool            // When true, destroy plans that create or update resources are applied instead of failing the step
 }
 
 // Step represents a delivery framework step, e.g. the executions needed to implement a track
@@ -45,6 +66,7 @@ type Step struct {
 	Dir                    string
 	ProgressionLevel       int // 1, 2, 3...
 	RegionalResourcesExist bool
+	RegionalOnly           bool // If true, the step only contains regional resources and is not executed in the primary region
 	TestsExist             bool
 	RegionalTestsExist     bool // TODO: remove the need for these TestsExists and evaulate in real time during evaluation vs gather?
 	DeployConfig           Config
@@ -52,7 +74,104 @@ type Step struct {
 	Output                 StepOutput
 	TestOutput             StepTestOutput
 	Runner                 Stepper
-	//runiacConfig       runiacConfig
+	Config                 StepConfig
+	ContentHash            string                       // Hash of the step directory's contents, set when deploying since the last success
+	UnchangedOutputs       map[string]map[string]string // Set when deploying since the last success, K={regionDeployType}-{region} the step's content was last successfully applied to, V=the output variables it produced
+	TestsFailedOnly        bool                         // Set when the step is in FailedSteps because its tests failed while its deploy succeeded
+	CSP                    string                       // The cloud service provider the step targets (e.g. AWS or AZU), empty when it targets any
+}
+
+// StepConfig represents the optional runiac.yaml configuration file within a step's directory
+type StepConfig struct {
+	Runner           string `mapstructure:"runner"`             // Forces the named runner (e.g. terraform) instead of detecting one from the step's contents
+	HasTests         *bool  `mapstructure:"has_tests"`          // Overrides whether the step's tests directory has tests to execute
+	HasRegionalTests *bool  `mapstructure:"has_regional_tests"` // Overrides whether the step's regional tests directory has tests to execute
+	TestRunner       string `mapstructure:"test_runner"`        // Forces the named test runner (go or command) instead of detecting one from the tests directory
+	TestCommand      string `mapstructure:"test_command"`       // Command the command test runner executes from the step's directory
+	CSP              string `mapstructure:"csp"`                // The cloud service provider the step targets (e.g. AWS or AZU), the step is skipped when deploying to another
+
+	TerraformParallelism int `mapstructure:"terraform_parallelism"` // Overrides the configured TerraformParallelism for the step
+
+	ExpectedOutputs         []string `mapstructure:"expected_outputs"`           // Output variables the step must export after a successful primary deploy
+	ExpectedRegionalOutputs []string `mapstructure:"expected_regional_outputs"`  // Output variables the step must export after each successful regional deploy
+	ExpectedOutputsWarnOnly bool     `mapstructure:"expected_outputs_warn_only"` // When true, missing expected outputs are logged as warnings instead of failing the step
+
+	OutputScope string `mapstructure:"output_scope"` // Which later steps receive the step's output variables, one of the OutputScope values, defaults to track
+
+	RetryableFailureRetries int           `mapstructure:"retryable_failure_retries"` // Overrides the configured RetryableFailureRetries for the step
+	RetryBackoff            time.Duration `mapstructure:"retry_backoff"`             // Overrides the configured RetryBackoff for the step, e.g. 30s
+
+	PerRegionGroup map[string]StepConfig `mapstructure:"per_region_group"` // K=region group, V=configuration overriding the step's when deploying to the region group
+}
+
+// ForRegionGroup returns the step configuration with the region group's overrides, if any, merged over it
+func (c StepConfig) ForRegionGroup(regionGroup string) StepConfig {
+	override, ok := c.PerRegionGroup[regionGroup]
+	if !ok {
+		return c
+	}
+
+	if override.Runner != "" {
+		c.Runner = override.Runner
+	}
+	if override.HasTests != nil {
+		c.HasTests = override.HasTests
+	}
+	if override.HasRegionalTests != nil {
+		c.HasRegionalTests = override.HasRegionalTests
+	}
+	if override.TestRunner != "" {
+		c.TestRunner = override.TestRunner
+	}
+	if override.TestCommand != "" {
+		c.TestCommand = override.TestCommand
+	}
+	if override.CSP != "" {
+		c.CSP = override.CSP
+	}
+	if override.TerraformParallelism > 0 {
+		c.TerraformParallelism = override.TerraformParallelism
+	}
+	if override.ExpectedOutputs != nil {
+		c.ExpectedOutputs = override.ExpectedOutputs
+	}
+	if override.ExpectedRegionalOutputs != nil {
+		c.ExpectedRegionalOutputs = override.ExpectedRegionalOutputs
+	}
+	if override.ExpectedOutputsWarnOnly {
+		c.ExpectedOutputsWarnOnly = true
+	}
+	if override.OutputScope != "" {
+		c.OutputScope = override.OutputScope
+	}
+	if override.RetryableFailureRetries > 0 {
+		c.RetryableFailureRetries = override.RetryableFailureRetries
+	}
+	if override.RetryBackoff > 0 {
+		c.RetryBackoff = override.RetryBackoff
+	}
+
+	return c
+}
+
+// Validate returns an error if the step configuration is invalid
+func (c StepConfig) Validate() error {
+	switch c.OutputScope {
+	case "", OutputScopeTrack, OutputScopeProgression, OutputScopeNone:
+	default:
+		return fmt.Errorf("output_scope %q must be one of %s, %s or %s", c.OutputScope, OutputScopeTrack, OutputScopeProgression, OutputScopeNone)
+	}
+
+	return nil
+}
+
+// ReadStepConfig reads the step configuration file from dir, returning an empty configuration when none exists
+func ReadStepConfig(fs afero.Fs, dir string) (StepConfig, error) {
+	conf := StepConfig{}
+
+	_, err := readConfigFile(fs, dir, &conf)
+
+	return conf, err
 }
 
 // StepTestOutput represents the output of a step's test
@@ -64,15 +183,64 @@ type StepTestOutput struct {
 
 // StepOutput represents the output of a step
 type StepOutput struct {
-	Status           DeployResult
-	RegionDeployType RegionDeployType
-	Region           string
-	StepName         string
-	StreamOutput     string
-	Err              error
-	OutputVariables  map[string]interface{}
+	Status            DeployResult
+	RegionDeployType  RegionDeployType
+	Region            string
+	StepName          string
+	StreamOutput      string
+	Err               error
+	OutputVariables   map[string]interface{}
+	PolicyOutput      string              // Output of the policy command run against the step's plan, if configured
+	Duration          time.Duration       // How long the step's runner took to execute
+	StartTime         time.Time           // When the step's runner started executing
+	EndTime           time.Time           // When the step's runner finished executing, including any retries
+	RateLimited       bool                // Indicates the step's runner encountered provider API rate limiting (throttling)
+	Resources         []string            // Addresses of the resources managed by the step, set when emitting an inventory
+	FailureCategory   FailureCategory     // The classification of a failed step's error, empty unless the step failed
+	ConsumedVariables map[string][]string // Previous step output variables the step referenced. K={step name}, V=[outputVarName]
+	PlanFile          string              // Path of the plan the step's runner applied, or would have applied during a dry run
+	PlannedDeletions  []string            // Addresses of the resources the step's plan deletes, including replacements
+	PlanSummary       *PlanSummary        // The resource changes of the step's plan, nil when the step's runner does not plan
+	Warnings          []string            // Warnings (e.g. deprecations) reported by the step's runner, which do not fail the step
+	Attempts          int                 // The times the step's runner was executed, more than one when retryable failures were retried
+}
+
+// PlanSummary counts the resource changes of a plan, replaced resources are counted as both created and destroyed
+type PlanSummary struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
+// Plus returns the sum of both plan summaries
+func (p PlanSummary) Plus(other PlanSummary) PlanSummary {
+	return PlanSummary{
+		Add:     p.Add + other.Add,
+		Change:  p.Change + other.Change,
+		Destroy: p.Destroy + other.Destroy,
+	}
+}
+
+func (p PlanSummary) String() string {
+	return fmt.Sprintf("will create %d, change %d, destroy %d", p.Add, p.Change, p.Destroy)
 }
 
+// FailureCategory classifies why a step failed
+type FailureCategory string
+
+const (
+	// RetryableFailure is a transient failure (e.g. throttling or a network error) that may succeed when retried
+	RetryableFailure FailureCategory = "retryable"
+	// PermanentFailure is a failure that will not succeed without a change to the environment (e.g. missing permissions)
+	PermanentFailure FailureCategory = "permanent"
+	// UserFailure is a failure caused by the step's configuration or code (e.g. an invalid terraform argument)
+	UserFailure FailureCategory = "user_error"
+	// InitFailure is a failure initializing the step's execution (e.g. a missing directory), before its runner executed
+	InitFailure FailureCategory = "init"
+	// UnknownFailure is a failure the classifier did not recognize
+	UnknownFailure FailureCategory = "unknown"
+)
+
 // TFProviderType represents a Terraform provider type
 type RegionDeployType int
 
@@ -89,6 +257,100 @@ func (p RegionDeployType) String() string {
 	return [...]string{"primary", "regional"}[p]
 }
 
+// StringToRegionDeployType converts a string to a RegionDeployType
+func StringToRegionDeployType(s string) (RegionDeployType, error) {
+	switch strings.ToLower(s) {
+	case PrimaryRegionDeployType.String():
+		return PrimaryRegionDeployType, nil
+	case RegionalRegionDeployType.String():
+		return RegionalRegionDeployType, nil
+	}
+
+	return PrimaryRegionDeployType, fmt.Errorf("invalid region deploy type %q", s)
+}
+
+// DefaultStepIDDelimiter separates the names in step ids, e.g. #{project}#{track}#{step}
+const DefaultStepIDDelimiter = "#"
+
+// IDDelimiter is the configured StepIDDelimiter, or DefaultStepIDDelimiter when none is configured
+func (c Config) IDDelimiter() string {
+	if c.StepIDDelimiter == "" {
+		return DefaultStepIDDelimiter
+	}
+
+	return c.StepIDDelimiter
+}
+
+// NewStepID joins the names, e.g. the project, track and step, into a step id prefixed by the delimiter, e.g.
+// #project#track#step. Names containing the delimiter would corrupt the id and are rejected
+func NewStepID(delimiter string, names ...string) (string, error) {
+	for _, name := range names {
+		if strings.Contains(name, delimiter) {
+			return "", fmt.Errorf("%q contains the step id delimiter %q", name, delimiter)
+		}
+	}
+
+	return delimiter + strings.Join(names, delimiter), nil
+}
+
+// ParseStepID splits a step id created by NewStepID into its names
+func ParseStepID(delimiter string, id string) []string {
+	if !strings.HasPrefix(id, delimiter) {
+		return nil
+	}
+
+	return strings.Split(strings.TrimPrefix(id, delimiter), delimiter)
+}
+
+// StepWhitelistRegexPrefix marks step whitelist entries that are regular expressions matched against step ids
+const StepWhitelistRegexPrefix = "regex:"
+
+// IsStepIDPattern reports whether a step whitelist entry is a glob pattern, e.g. #project#track#*, rather than a step id
+func IsStepIDPattern(entry string) bool {
+	return strings.ContainsAny(entry, "*?[")
+}
+
+// Regional output key strategies determine how a regional step's output variables are keyed for later steps
+const (
+	// SuffixRegionalOutputKey keys the outputs as {step}-regional, steps declare them as {step}-regional-{output}
+	SuffixRegionalOutputKey = "suffix"
+	// PrefixRegionalOutputKey keys the outputs as regional-{step}, steps declare them as regional-{step}-{output}
+	PrefixRegionalOutputKey = "prefix"
+	// NestedRegionalOutputKey nests the outputs in a single map, steps declare them as {step}-regional of type map(string)
+	NestedRegionalOutputKey = "nested"
+)
+
+// Workdir retention policies determine whether the working directories a step's regional resources are copied to for
+// each region are kept for debugging after the region execution completes
+const (
+	// AlwaysRetainWorkdirs keeps every working directory
+	AlwaysRetainWorkdirs = "always"
+	// RetainWorkdirsOnFailure keeps the working directories of steps that failed their deploy or tests, removing the rest
+	RetainWorkdirsOnFailure = "onFailure"
+	// NeverRetainWorkdirs removes every working directory
+	NeverRetainWorkdirs = "never"
+)
+
+// Output scopes determine which later steps in the track receive a step's output variables
+const (
+	// OutputScopeTrack passes the outputs to every later step in the track, including the track's regional deployments
+	OutputScopeTrack = "track"
+	// OutputScopeProgression passes the outputs to the steps in the next progression level only
+	OutputScopeProgression = "progression"
+	// OutputScopeNone withholds the outputs from every later step
+	OutputScopeNone = "none"
+)
+
+// TestRunner executes a step's tests, e.g. a compiled go test binary or a generic command
+type TestRunner interface {
+	RunTests(execution StepExecution, testDir string, env map[string]string) (output string, err error)
+}
+
+// FailureClassifier categorizes a failed step's output, e.g. to decide whether the failure is worth retrying
+type FailureClassifier interface {
+	Classify(output StepOutput) FailureCategory
+}
+
 // Stepper is an interface for working with delivery framework steps, e.g. the executions needed to implement a track
 // All Step methods will handle logging of errors while logger has appropriate fields set.
 // Therefore, there should be no need to logger Output.Errs from this interface
@@ -107,9 +369,10 @@ const (
 	Success
 	Unstable
 	Skipped
-	Na // not applicable (e.g. no regional resources exist or step was disabled for execution)
+	Na        // not applicable (e.g. no regional resources exist or step was disabled for execution)
+	Cancelled // the deployment was cancelled before, or while, the step executed
 )
 
 func (d DeployResult) String() string {
-	return [...]string{"FAIL", "SUCCESS", "UNSTABLE", "SKIPPED"}[d]
+	return [...]string{"FAIL", "SUCCESS", "UNSTABLE", "SKIPPED", "NA", "CANCELLED"}[d]
 }
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// ConfigFileNames are the optional configuration files read from a track or step directory, in order of precedence
+var ConfigFileNames = []string{"runiac.yaml", "runiac.yml"}
+
+// TrackConfig represents the optional runiac.yaml configuration file within a track's directory
+type TrackConfig struct {
+	RegionDeployTypes     []string `mapstructure:"region_deploy_types"`     // The region deploy types the track participates in, e.g. [primary]. Defaults to all
+	PauseBeforeRegional   bool     `mapstructure:"pause_before_regional"`   // When true, regional deployments wait for approval after the primary region succeeds
+	Stage                 string   `mapstructure:"stage"`                   // The stage the track is executed in, one of cfg.Stages. Tracks without a stage are executed after all stages
+	RegionalRegionsOutput string   `mapstructure:"regional_regions_output"` // A primary step output variable, as {step}.{output}, holding the regional regions to deploy to instead of cfg.RegionalRegions
+	MinSuccessfulRegions  int      `mapstructure:"min_successful_regions"`  // The track fails when fewer regional regions than this deploy successfully. Defaults to 0, no minimum
+	DependsOn             []string `mapstructure:"depends_on"`              // The names of the tracks this track depends on
+	AlwaysRun             bool     `mapstructure:"always_run"`              // When true, every step of the track is executed even when the track is not targeted, e.g. a mandatory baseline
+	MaxRegionalFailures   int      `mapstructure:"max_regional_failures"`   // When greater than zero, the remaining regional regions are cancelled once more than this many regional regions fail
+	IndependentOfPreTrack bool     `mapstructure:"independent_of_pretrack"` // When true, the track is executed alongside the pretrack rather than after it, without the pretrack's outputs
+	RunOnFailure          bool     `mapstructure:"run_on_failure"`          // When true, the posttrack is executed even when other tracks failed, e.g. for cleanup
+	PrimaryRegion         string   `mapstructure:"primary_region"`          // The primary region of the track instead of cfg.PrimaryRegion
+	RegionalRegions       []string `mapstructure:"regional_regions"`        // The regional regions of the track instead of cfg.RegionalRegions, patterns such as eu-* select from cfg.RegionalRegions
+}
+
+// ReadTrackConfig reads the track configuration file from dir, returning an empty configuration when none exists
+func ReadTrackConfig(fs afero.Fs, dir string) (TrackConfig, error) {
+	conf := TrackConfig{}
+
+	found, err := readConfigFile(fs, dir, &conf)
+	if err != nil || !found {
+		return conf, err
+	}
+
+	return conf, conf.Validate()
+}
+
+// readConfigFile decodes the first configuration file found in dir into out, reporting whether one was found
+func readConfigFile(fs afero.Fs, dir string, out interface{}) (bool, error) {
+	for _, name := range ConfigFileNames {
+		file := filepath.Join(dir, name)
+
+		b, err := afero.ReadFile(fs, file)
+		if err != nil {
+			continue
+		}
+
+		v := viper.New()
+		v.SetConfigType("yaml")
+
+		if err := v.ReadConfig(bytes.NewReader(b)); err != nil {
+			return true, fmt.Errorf("unable to read %s: %w", file, err)
+		}
+
+		if err := v.Unmarshal(out); err != nil {
+			return true, fmt.Errorf("unable to decode %s: %w", file, err)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Validate ensures the track configuration values are supported
+func (c TrackConfig) Validate() error {
+	for _, t := range c.RegionDeployTypes {
+		if _, err := StringToRegionDeployType(t); err != nil {
+			return err
+		}
+	}
+
+	// regional executions depend on the primary region's outputs, so a track cannot opt out of primary
+	if len(c.RegionDeployTypes) > 0 && !c.IncludesRegionDeployType(PrimaryRegionDeployType) {
+		return fmt.Errorf("region_deploy_types %v must include %s", c.RegionDeployTypes, PrimaryRegionDeployType)
+	}
+
+	if c.MinSuccessfulRegions < 0 {
+		return fmt.Errorf("min_successful_regions %d must not be negative", c.MinSuccessfulRegions)
+	}
+
+	if c.MaxRegionalFailures < 0 {
+		return fmt.Errorf("max_regional_failures %d must not be negative", c.MaxRegionalFailures)
+	}
+
+	if c.RegionalRegionsOutput != "" {
+		if _, _, err := c.RegionalRegionsOutputKey(); err != nil {
+			return err
+		}
+	}
+
+	for _, region := range c.RegionalRegions {
+		if _, err := path.Match(region, ""); err != nil {
+			return fmt.Errorf("regional_regions pattern %s is invalid: %w", region, err)
+		}
+	}
+
+	return nil
+}
+
+// IsRegionPattern reports whether a regional_regions entry is a pattern, e.g. eu-*, rather than a region
+func IsRegionPattern(region string) bool {
+	return strings.ContainsAny(region, "*?[")
+}
+
+// RegionalRegionsOutputKey splits RegionalRegionsOutput into the primary step name and output variable name
+func (c TrackConfig) RegionalRegionsOutputKey() (step string, output string, err error) {
+	parts := strings.SplitN(c.RegionalRegionsOutput, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("regional_regions_output %s must be in the form {step}.{output}", c.RegionalRegionsOutput)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// IncludesRegionDeployType reports whether the track participates in the region deploy type
+func (c TrackConfig) IncludesRegionDeployType(regionDeployType RegionDeployType) bool {
+	if len(c.RegionDeployTypes) == 0 {
+		return true
+	}
+
+	for _, t := range c.RegionDeployTypes {
+		if strings.EqualFold(t, regionDeployType.String()) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,161 @@
+// Package reporting converts the results of a deployment into report formats consumed by other tooling
+package reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/spf13/afero"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a track of the deployment
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a step, or a step's tests, executed in a region
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure describes why a test case failed
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a test case as skipped
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// NewJUnitReport reports each track of the stage as a test suite, and each step and its tests in each region as test
+// cases named {step} ({regionDeployType}/{region}). Steps that were not applicable in a region are left out
+func NewJUnitReport(stage tracks.Stage) JUnitTestSuites {
+	report := JUnitTestSuites{
+		Time:   stage.Duration.Seconds(),
+		Suites: []JUnitTestSuite{},
+	}
+
+	for _, t := range stage.Tracks {
+		suite := JUnitTestSuite{Name: t.Name}
+
+		for _, exec := range t.Output.Executions {
+			for _, s := range exec.Output.Steps {
+				if s.Output.Status == config.Na {
+					continue
+				}
+
+				suite.TestCases = append(suite.TestCases, newStepTestCase(t.Name, exec, s))
+
+				if s.TestOutput.StepName != "" {
+					suite.TestCases = append(suite.TestCases, newStepTestsTestCase(t.Name, exec, s))
+				}
+			}
+		}
+
+		sort.Slice(suite.TestCases, func(i, j int) bool {
+			return suite.TestCases[i].Name < suite.TestCases[j].Name
+		})
+
+		for _, testCase := range suite.TestCases {
+			suite.Tests++
+			suite.Time += testCase.Time
+
+			if testCase.Failure != nil {
+				suite.Failures++
+			} else if testCase.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+	}
+
+	sort.Slice(report.Suites, func(i, j int) bool {
+		return report.Suites[i].Name < report.Suites[j].Name
+	})
+
+	return report
+}
+
+// WriteJUnitReport writes the stage's JUnit XML report to path
+func WriteJUnitReport(fs afero.Fs, path string, stage tracks.Stage) error {
+	b, err := xml.MarshalIndent(NewJUnitReport(stage), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return afero.WriteFile(fs, path, append([]byte(xml.Header), b...), 0644)
+}
+
+func newStepTestCase(trackName string, exec tracks.RegionExecution, s config.Step) JUnitTestCase {
+	testCase := JUnitTestCase{
+		Name:      fmt.Sprintf("%s (%s/%s)", s.Name, exec.RegionDeployType, exec.Region),
+		ClassName: trackName,
+		Time:      s.Output.Duration.Seconds(),
+	}
+
+	switch {
+	case s.Output.Status == config.Fail:
+		testCase.Failure = &JUnitFailure{Message: "step failed", Output: s.Output.StreamOutput}
+		if s.Output.Err != nil {
+			testCase.Failure.Message = s.Output.Err.Error()
+		}
+	case s.Output.Status == config.Cancelled:
+		// an interrupted deployment is not a failure of the step itself
+		testCase.Skipped = &JUnitSkipped{Message: "step was cancelled"}
+	case s.Output.Status == config.Skipped:
+		testCase.Skipped = &JUnitSkipped{}
+		if s.Output.Err != nil {
+			testCase.Skipped.Message = s.Output.Err.Error()
+		}
+	}
+
+	return testCase
+}
+
+func newStepTestsTestCase(trackName string, exec tracks.RegionExecution, s config.Step) JUnitTestCase {
+	testCase := JUnitTestCase{
+		Name:      fmt.Sprintf("%s tests (%s/%s)", s.Name, exec.RegionDeployType, exec.Region),
+		ClassName: trackName,
+	}
+
+	if s.TestOutput.Err != nil {
+		testCase.Failure = &JUnitFailure{Message: s.TestOutput.Err.Error(), Output: s.TestOutput.StreamOutput}
+	}
+
+	return testCase
+}
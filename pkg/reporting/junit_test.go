@@ -0,0 +1,141 @@
+package reporting_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/reporting"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func stubStage() tracks.Stage {
+	return tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc": {
+										Name:       "vpc",
+										Output:     config.StepOutput{Status: config.Success, Duration: time.Second},
+										TestOutput: config.StepTestOutput{StepName: "vpc", Err: errors.New("tests failed")},
+									},
+									"subnets": {
+										Name:   "subnets",
+										Output: config.StepOutput{Status: config.Fail, Err: errors.New("apply failed")},
+									},
+									"routes": {
+										Name:   "routes",
+										Output: config.StepOutput{Status: config.Skipped},
+									},
+								},
+							},
+						},
+						{
+							Region:           "us-east-2",
+							RegionDeployType: config.RegionalRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc":     {Name: "vpc", Output: config.StepOutput{Status: config.Success}},
+									"subnets": {Name: "subnets", Output: config.StepOutput{Status: config.Na}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewJUnitReport_ShouldReportStepsAndTestsAsTestCases(t *testing.T) {
+	// act
+	report := reporting.NewJUnitReport(stubStage())
+
+	// assert
+	require.Len(t, report.Suites, 1)
+	suite := report.Suites[0]
+	require.Equal(t, "network", suite.Name)
+
+	var names []string
+	for _, testCase := range suite.TestCases {
+		require.Equal(t, "network", testCase.ClassName)
+		names = append(names, testCase.Name)
+	}
+	require.Equal(t, []string{
+		"routes (primary/us-east-1)",
+		"subnets (primary/us-east-1)",
+		"vpc (primary/us-east-1)",
+		"vpc (regional/us-east-2)",
+		"vpc tests (primary/us-east-1)",
+	}, names, "Not applicable steps should be left out")
+
+	require.NotNil(t, suite.TestCases[0].Skipped, "Skipped steps should be reported as skipped")
+	require.Equal(t, "apply failed", suite.TestCases[1].Failure.Message, "Failed steps should be reported as failures")
+	require.Nil(t, suite.TestCases[2].Failure)
+	require.Equal(t, "tests failed", suite.TestCases[4].Failure.Message, "Failed tests should be reported as failures")
+
+	require.Equal(t, 5, report.Tests)
+	require.Equal(t, 2, report.Failures)
+	require.Equal(t, 1, report.Skipped)
+}
+
+func TestNewJUnitReport_ShouldReportCancelledStepsAsSkipped(t *testing.T) {
+	// arrange
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc": {Name: "vpc", Output: config.StepOutput{Status: config.Cancelled, Err: errors.New("signal: interrupt")}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	report := reporting.NewJUnitReport(stage)
+
+	// assert
+	testCase := report.Suites[0].TestCases[0]
+	require.Nil(t, testCase.Failure, "Cancelled steps should not be reported as failures")
+	require.Equal(t, "step was cancelled", testCase.Skipped.Message)
+	require.Equal(t, 0, report.Failures)
+	require.Equal(t, 1, report.Skipped)
+}
+
+func TestWriteJUnitReport_ShouldWriteXMLToPath(t *testing.T) {
+	// arrange
+	fs := afero.NewMemMapFs()
+
+	// act
+	err := reporting.WriteJUnitReport(fs, "output/junit.xml", stubStage())
+
+	// assert
+	require.NoError(t, err)
+
+	b, err := afero.ReadFile(fs, "output/junit.xml")
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<testsuites tests="5" failures="2" skipped="1"`)
+	require.Contains(t, string(b), `<testcase name="subnets (primary/us-east-1)" classname="network" time="0">`)
+	require.Contains(t, string(b), `<failure message="apply failed"></failure>`)
+}
@@ -2,6 +2,7 @@ package shell
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
@@ -11,18 +12,22 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
 // Command is a simpler struct for defining commands than Go's built-in Cmd.
 type Command struct {
-	Command           string            // The command to run
-	Args              []string          // The args to pass to the command
-	WorkingDir        string            // The working directory
-	Env               map[string]string // Additional environment variables to set
-	OutputMaxLineSize int               // The max line size of stdout and stderr (in bytes)
-	Logger            *logrus.Entry
-	NonInteractive    bool
-	SensitiveArgs     bool // If true, will not log the arguments to the command
+	Command             string            // The command to run
+	Args                []string          // The args to pass to the command
+	WorkingDir          string            // The working directory
+	Env                 map[string]string // Additional environment variables to set
+	EnvAllowlist        []string          // When set, only the inherited environment variables with these names, or prefixes ending in *, are passed to the command
+	OutputMaxLineSize   int               // The max line size of stdout and stderr (in bytes)
+	Logger              *logrus.Entry
+	NonInteractive      bool
+	SensitiveArgs       bool            // If true, will not log the arguments to the command
+	Context             context.Context // If set, the command is interrupted when the context is cancelled
+	ShutdownGracePeriod time.Duration   // The time an interrupted command has to exit before it is killed (defaults to DefaultShutdownGracePeriod)
 }
 
 // RunCommand runs a shell command and redirects its stdout and stderr to the stdout of the atomic script itself.
@@ -72,10 +77,11 @@ func runCommandAndStoreOutput(command Command, storedStdout *[]string, storedStd
 		return err
 	}
 
-	err = cmd.Start()
+	done, err := startCommand(command, cmd)
 	if err != nil {
 		return err
 	}
+	defer done()
 
 	if err := readStdoutAndStderr2(command.Logger, stdout, stderr, storedStdout, storedStderr, command.OutputMaxLineSize); err != nil {
 		return err
@@ -153,8 +159,34 @@ func GetExitCodeForRunCommandError(err error) (int, error) {
 
 func formatEnvVars(command Command) []string {
 	env := os.Environ()
+
+	if command.EnvAllowlist != nil {
+		allowed := []string{}
+		for _, v := range env {
+			if EnvAllowed(strings.SplitN(v, "=", 2)[0], command.EnvAllowlist) {
+				allowed = append(allowed, v)
+			}
+		}
+		env = allowed
+	}
+
 	for key, value := range command.Env {
 		env = append(env, fmt.Sprintf("%s=%s", key, value))
 	}
 	return env
 }
+
+// EnvAllowed reports whether the environment variable name matches one of the allowlist's names or prefixes ending in *
+func EnvAllowed(name string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if strings.HasSuffix(allowed, "*") && strings.HasPrefix(name, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+
+		if name == allowed {
+			return true
+		}
+	}
+
+	return false
+}
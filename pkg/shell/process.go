@@ -0,0 +1,59 @@
+package shell
+
+import (
+	"os/exec"
+	"time"
+)
+
+// DefaultShutdownGracePeriod is the amount of time an interrupted command is given to exit before it is killed.
+// Terraform uses this window to finish in-flight provider calls and release state locks, so err on the long side.
+const DefaultShutdownGracePeriod = 60 * time.Second
+
+// startCommand starts the command and, when the command's context is set, watches for cancellation. Only cancellable
+// commands are started in their own process group, others stay in runiac's so they receive its terminal's signals. On
+// cancellation the process group receives an interrupt, followed by a kill once the grace period elapses. The returned
+// function must be called after the command has been waited on.
+func startCommand(command Command, cmd *exec.Cmd) (done func(), err error) {
+	if command.Context == nil {
+		return func() {}, cmd.Start()
+	}
+
+	setProcessGroup(cmd)
+
+	if err = cmd.Start(); err != nil {
+		return func() {}, err
+	}
+
+	gracePeriod := command.ShutdownGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultShutdownGracePeriod
+	}
+
+	exited := make(chan struct{})
+
+	go func() {
+		select {
+		case <-exited:
+			return
+		case <-command.Context.Done():
+		}
+
+		if command.Logger != nil {
+			command.Logger.Warnf("Context cancelled, interrupting %s and waiting up to %s for it to exit", command.Command, gracePeriod)
+		}
+
+		_ = interruptProcessGroup(cmd)
+
+		select {
+		case <-exited:
+		case <-time.After(gracePeriod):
+			if command.Logger != nil {
+				command.Logger.Errorf("%s did not exit within %s of being interrupted, killing it", command.Command, gracePeriod)
+			}
+
+			_ = killProcessGroup(cmd)
+		}
+	}()
+
+	return func() { close(exited) }, nil
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package shell
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup places the command in a new process group so signals reach any children it spawns (e.g. providers)
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func interruptProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
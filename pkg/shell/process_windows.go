@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package shell
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on windows, process groups are not used
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func interruptProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Signal(os.Interrupt)
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -2,6 +2,7 @@ package shell
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -29,14 +30,17 @@ func RunShellCommand(command Command) error {
 
 	cmd.Dir = command.WorkingDir
 
-	if len(command.Env) > 0 {
-		cmd.Env = os.Environ()
-		for k, v := range command.Env {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-		}
+	if len(command.Env) > 0 || command.EnvAllowlist != nil {
+		cmd.Env = formatEnvVars(command)
 	}
 
-	return errors.WithStackTrace(cmd.Run())
+	done, err := startCommand(command, cmd)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer done()
+
+	return errors.WithStackTrace(cmd.Wait())
 }
 
 // Run the specified shell command with the specified arguments. Return its stdout and stderr as a string
@@ -52,15 +56,22 @@ func RunShellCommandAndGetOutput(command Command) (string, error) {
 	cmd.Stdin = os.Stdin
 	cmd.Dir = command.WorkingDir
 
-	if len(command.Env) > 0 {
-		cmd.Env = os.Environ()
-		for k, v := range command.Env {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-		}
+	if len(command.Env) > 0 || command.EnvAllowlist != nil {
+		cmd.Env = formatEnvVars(command)
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	done, err := startCommand(command, cmd)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
 	}
+	defer done()
 
-	out, err := cmd.CombinedOutput()
-	return string(out), errors.WithStackTrace(err)
+	err = cmd.Wait()
+	return out.String(), errors.WithStackTrace(err)
 }
 
 func KeysStringString(m map[string]string) string {
@@ -82,11 +93,8 @@ func RunShellCommandAndGetAndStreamOutput(command Command) (string, error) {
 
 	cmd := exec.Command(command.Command, command.Args...)
 
-	if len(command.Env) > 0 {
-		cmd.Env = os.Environ()
-		for k, v := range command.Env {
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
-		}
+	if len(command.Env) > 0 || command.EnvAllowlist != nil {
+		cmd.Env = formatEnvVars(command)
 	}
 
 	cmd.Dir = command.WorkingDir
@@ -103,9 +111,11 @@ func RunShellCommandAndGetAndStreamOutput(command Command) (string, error) {
 		return "", errors.WithStackTrace(err)
 	}
 
-	if err := cmd.Start(); err != nil {
+	done, err := startCommand(command, cmd)
+	if err != nil {
 		return "", errors.WithStackTrace(err)
 	}
+	defer done()
 
 	output, err := readStdoutAndStderr(stdout, stderr, command)
 	if err != nil {
@@ -0,0 +1,162 @@
+//go:build !windows
+// +build !windows
+
+package shell_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/shell"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+var logger = logrus.NewEntry(logrus.New())
+
+func TestRunShellCommandAndGetOutput_ShouldKillProcessIgnoringInterruptAfterGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	command := shell.Command{
+		Command:             "sh",
+		Args:                []string{"-c", "trap 'echo interrupted' INT; while true; do sleep 1; done"},
+		Logger:              logger,
+		Context:             ctx,
+		ShutdownGracePeriod: 500 * time.Millisecond,
+	}
+
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	output, err := shell.RunShellCommandAndGetOutput(command)
+	elapsed := time.Since(start)
+
+	require.Error(t, err, "The process should have been killed")
+	require.Contains(t, output, "interrupted", "The process should have received an interrupt before being killed")
+	require.True(t, elapsed >= 700*time.Millisecond, "The process should not be killed before the grace period elapses, took %s", elapsed)
+	require.True(t, elapsed < 10*time.Second, "The process should be killed once the grace period elapses, took %s", elapsed)
+}
+
+func TestRunShellCommandAndGetOutput_ShouldInterruptProcessWhenContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	command := shell.Command{
+		Command:             "sh",
+		Args:                []string{"-c", "sleep 30"},
+		Logger:              logger,
+		Context:             ctx,
+		ShutdownGracePeriod: time.Minute,
+	}
+
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := shell.RunShellCommandAndGetOutput(command)
+	elapsed := time.Since(start)
+
+	require.Error(t, err, "The process should have exited due to the interrupt")
+	require.True(t, elapsed < 10*time.Second, "The process should exit on interrupt without waiting for the grace period, took %s", elapsed)
+}
+
+func TestRunShellCommandAndGetOutput_ShouldRunToCompletionWithoutCancellation(t *testing.T) {
+	t.Parallel()
+
+	command := shell.Command{
+		Command: "sh",
+		Args:    []string{"-c", "echo hello"},
+		Logger:  logger,
+		Context: context.Background(),
+	}
+
+	output, err := shell.RunShellCommandAndGetOutput(command)
+
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", output)
+}
+
+func TestRunShellCommandAndGetOutput_ShouldOnlyStartCancellableCommandsInTheirOwnProcessGroup(t *testing.T) {
+	tests := map[string]struct {
+		ctx            context.Context
+		expectOwnGroup bool
+	}{
+		"ShouldKeepCommandWithoutContextInProcessGroup":  {ctx: nil, expectOwnGroup: false},
+		"ShouldStartCancellableCommandInOwnProcessGroup": {ctx: context.Background(), expectOwnGroup: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			command := shell.Command{
+				Command: "sh",
+				Args:    []string{"-c", "echo $$ $(ps -o pgid= -p $$)"},
+				Logger:  logger,
+				Context: test.ctx,
+			}
+
+			output, err := shell.RunShellCommandAndGetOutput(command)
+
+			require.NoError(t, err)
+			fields := strings.Fields(output)
+			require.Len(t, fields, 2)
+
+			if test.expectOwnGroup {
+				require.Equal(t, fields[0], fields[1], "Cancellable commands should lead their own process group")
+			} else {
+				require.Equal(t, strconv.Itoa(syscall.Getpgrp()), fields[1], "Commands that can not be cancelled should stay in runiac's process group")
+			}
+		})
+	}
+}
+
+func TestRunShellCommandAndGetOutput_ShouldOnlyInheritAllowlistedEnvVars(t *testing.T) {
+	// arrange
+	_ = os.Setenv("RUNIAC_SHELL_TEST_SECRET", "leaked")
+	_ = os.Setenv("RUNIAC_ALLOWED_VALUE", "allowed")
+	defer os.Unsetenv("RUNIAC_SHELL_TEST_SECRET")
+	defer os.Unsetenv("RUNIAC_ALLOWED_VALUE")
+
+	command := shell.Command{
+		Command:      "sh",
+		Args:         []string{"-c", "env"},
+		Env:          map[string]string{"INJECTED": "injected"},
+		EnvAllowlist: []string{"PATH", "RUNIAC_ALLOWED_*"},
+		Logger:       logger,
+	}
+
+	// act
+	output, err := shell.RunShellCommandAndGetOutput(command)
+
+	// assert
+	require.NoError(t, err)
+	require.NotContains(t, output, "RUNIAC_SHELL_TEST_SECRET", "Disallowed variables should not be inherited")
+	require.Contains(t, output, "RUNIAC_ALLOWED_VALUE=allowed", "Variables matching an allowed prefix should be inherited")
+	require.Contains(t, output, "INJECTED=injected", "Injected variables should always be set")
+	require.Contains(t, output, "PATH=")
+}
+
+func TestRunShellCommandAndGetOutput_ShouldInheritAllEnvVarsWithoutAllowlist(t *testing.T) {
+	// arrange
+	_ = os.Setenv("RUNIAC_SHELL_TEST_INHERITED", "inherited")
+	defer os.Unsetenv("RUNIAC_SHELL_TEST_INHERITED")
+
+	command := shell.Command{
+		Command: "sh",
+		Args:    []string{"-c", "env"},
+		Logger:  logger,
+	}
+
+	// act
+	output, err := shell.RunShellCommandAndGetOutput(command)
+
+	// assert
+	require.NoError(t, err)
+	require.Contains(t, output, "RUNIAC_SHELL_TEST_INHERITED=inherited")
+}
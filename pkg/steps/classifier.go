@@ -0,0 +1,59 @@
+package steps
+
+import (
+	"regexp"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// failureCategoryPattern matches a failed step's error or stream output to a failure category
+type failureCategoryPattern struct {
+	category config.FailureCategory
+	pattern  *regexp.Regexp
+}
+
+// defaultFailureCategoryPatterns are evaluated in order, the first match classifies the failure
+var defaultFailureCategoryPatterns = []failureCategoryPattern{
+	// terraform configuration errors are reported before any provider API is called
+	{config.UserFailure, regexp.MustCompile(`(?i)unsupported (argument|attribute|block type)`)},
+	{config.UserFailure, regexp.MustCompile(`(?i)missing required (argument|provider)`)},
+	{config.UserFailure, regexp.MustCompile(`(?i)reference to undeclared`)},
+	{config.UserFailure, regexp.MustCompile(`(?i)invalid (reference|value for (input )?variable|function argument)`)},
+	{config.UserFailure, regexp.MustCompile(`(?i)argument or block definition required`)},
+	{config.UserFailure, regexp.MustCompile(`(?i)no value for required variable`)},
+
+	// transient provider and network errors
+	{config.RetryableFailure, regexp.MustCompile(`(?i)throttl(ed|ing)`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)rate ?(exceeded|limit ?(ed|exceeded))`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)request ?limit ?exceeded`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)too many requests`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)(i/o|tls handshake) timeout|timed out|timeout while waiting`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)context deadline exceeded`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)connection (reset|refused)`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)service ?unavailable|internal ?server ?error|\b50[234]\b`)},
+	{config.RetryableFailure, regexp.MustCompile(`(?i)error acquiring the state lock`)},
+
+	// errors requiring a change to the environment the step deploys to
+	{config.PermanentFailure, regexp.MustCompile(`(?i)access ?denied|unauthorized ?operation|authorization ?failed|not authorized to perform|forbidden`)},
+	{config.PermanentFailure, regexp.MustCompile(`(?i)already exists`)},
+	{config.PermanentFailure, regexp.MustCompile(`(?i)(quota|limit) exceeded`)},
+}
+
+// DefaultFailureClassifier recognizes common terraform and cloud provider errors
+type DefaultFailureClassifier struct{}
+
+// Classify categorizes a failed step by its error and stream output, returning config.UnknownFailure when unrecognized
+func (DefaultFailureClassifier) Classify(output config.StepOutput) config.FailureCategory {
+	errMessage := ""
+	if output.Err != nil {
+		errMessage = output.Err.Error()
+	}
+
+	for _, p := range defaultFailureCategoryPatterns {
+		if p.pattern.MatchString(errMessage) || p.pattern.MatchString(output.StreamOutput) {
+			return p.category
+		}
+	}
+
+	return config.UnknownFailure
+}
@@ -0,0 +1,52 @@
+package steps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultFailureClassifier_ShouldClassifyCommonErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output   config.StepOutput
+		expected config.FailureCategory
+	}{
+		"ShouldClassifyThrottlingAsRetryable": {
+			output:   config.StepOutput{Err: errors.New("ThrottlingException: Rate exceeded")},
+			expected: config.RetryableFailure,
+		},
+		"ShouldClassifyStateLockAsRetryable": {
+			output:   config.StepOutput{Err: errors.New("exit status 1"), StreamOutput: "Error: Error acquiring the state lock"},
+			expected: config.RetryableFailure,
+		},
+		"ShouldClassifyRequestLimitExceededAsRetryable": {
+			output:   config.StepOutput{Err: errors.New("RequestLimitExceeded: Request limit exceeded.")},
+			expected: config.RetryableFailure,
+		},
+		"ShouldClassifyAccessDeniedAsPermanent": {
+			output:   config.StepOutput{Err: errors.New("AccessDenied: User is not authorized to perform: iam:CreateRole")},
+			expected: config.PermanentFailure,
+		},
+		"ShouldClassifyInvalidConfigurationAsUserError": {
+			output:   config.StepOutput{Err: errors.New("exit status 1"), StreamOutput: `Error: Unsupported argument on main.tf line 3`},
+			expected: config.UserFailure,
+		},
+		"ShouldClassifyUnrecognizedErrorsAsUnknown": {
+			output:   config.StepOutput{Err: errors.New("something unexpected happened")},
+			expected: config.UnknownFailure,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.expected, DefaultFailureClassifier{}.Classify(test.output))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+)
+
+// InitErrorCause categorizes why a step execution could not be initialized
+type InitErrorCause string
+
+const (
+	// MissingDirInitError is a step directory, e.g. the step's regional directory, that does not exist
+	MissingDirInitError InitErrorCause = "missing_dir"
+	// FilesystemInitError is a failure preparing the step's execution directory
+	FilesystemInitError InitErrorCause = "filesystem"
+)
+
+// InitError is returned when a step execution could not be initialized, distinguishing the failure from one
+// encountered while the step's runner executed
+type InitError struct {
+	Cause InitErrorCause
+	Err   error
+}
+
+func (err InitError) Error() string {
+	return fmt.Sprintf("unable to initialize step execution (%s): %v", err.Cause, err.Err)
+}
+
+func (err InitError) Unwrap() error {
+	return err.Err
+}
+
+// newInitError wraps a filesystem error, categorizing paths that do not exist as missing directories
+func newInitError(err error) InitError {
+	if os.IsNotExist(err) {
+		return InitError{Cause: MissingDirInitError, Err: err}
+	}
+
+	return InitError{Cause: FilesystemInitError, Err: err}
+}
@@ -0,0 +1,95 @@
+package steps
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// PermissionsFs creates files and directories with the configured permissions instead of those requested,
+// a zero mode leaves the requested permissions unchanged
+type PermissionsFs struct {
+	afero.Fs
+	FileMode os.FileMode
+	DirMode  os.FileMode
+}
+
+// NewPermissionsFs wraps fs to create files with fileMode and directories with dirMode, returning fs unchanged when
+// neither is configured
+func NewPermissionsFs(fs afero.Fs, fileMode os.FileMode, dirMode os.FileMode) afero.Fs {
+	if fileMode == 0 && dirMode == 0 {
+		return fs
+	}
+
+	return PermissionsFs{Fs: fs, FileMode: fileMode, DirMode: dirMode}
+}
+
+func (p PermissionsFs) Create(name string) (afero.File, error) {
+	return p.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (p PermissionsFs) Mkdir(name string, perm os.FileMode) error {
+	return p.Fs.Mkdir(name, p.dirPerm(perm))
+}
+
+func (p PermissionsFs) MkdirAll(path string, perm os.FileMode) error {
+	return p.Fs.MkdirAll(path, p.dirPerm(perm))
+}
+
+func (p PermissionsFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&os.O_CREATE != 0 && p.FileMode != 0 {
+		perm = p.FileMode
+	}
+
+	return p.Fs.OpenFile(name, flag, perm)
+}
+
+// LstatIfPossible lstats name when the wrapped filesystem supports symlinks, so they can still be detected through it
+func (p PermissionsFs) LstatIfPossible(name string) (os.FileInfo, bool, error) {
+	if lstater, ok := p.Fs.(afero.Lstater); ok {
+		return lstater.LstatIfPossible(name)
+	}
+
+	fi, err := p.Fs.Stat(name)
+	return fi, false, err
+}
+
+func (p PermissionsFs) dirPerm(perm os.FileMode) os.FileMode {
+	if p.DirMode != 0 {
+		return p.DirMode
+	}
+
+	return perm
+}
+
+// RestrictPermissions applies dirMode to the directories and fileMode to the files copied to dir on the os filesystem.
+// Executable files, e.g. compiled tests, keep the execute permissions allowed by dirMode. A zero mode leaves the
+// copied permissions unchanged
+func RestrictPermissions(dir string, fileMode os.FileMode, dirMode os.FileMode) error {
+	if fileMode == 0 && dirMode == 0 {
+		return nil
+	}
+
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.IsDir() && dirMode != 0:
+			return os.Chmod(path, dirMode)
+		case info.Mode().IsRegular() && fileMode != 0:
+			mode := fileMode
+			if executable := info.Mode() & 0111; executable != 0 && dirMode != 0 {
+				mode |= dirMode & 0111
+			} else {
+				mode |= executable
+			}
+
+			return os.Chmod(path, mode)
+		}
+
+		return nil
+	})
+}
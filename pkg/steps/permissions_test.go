@@ -0,0 +1,87 @@
+package steps
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPermissionsFs_ShouldCreateWithConfiguredModes(t *testing.T) {
+	t.Parallel()
+
+	fs := NewPermissionsFs(afero.NewMemMapFs(), 0640, 0750)
+
+	// act
+	require.NoError(t, fs.MkdirAll("out/track", 0755))
+	require.NoError(t, afero.WriteFile(fs, "out/track/vars.json", []byte("{}"), 0644))
+
+	// assert
+	dir, err := fs.Stat("out/track")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0750), dir.Mode().Perm())
+
+	file, err := fs.Stat("out/track/vars.json")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), file.Mode().Perm())
+}
+
+func TestNewPermissionsFs_ShouldNotWrapWithoutConfiguredModes(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	require.Equal(t, fs, NewPermissionsFs(fs, 0, 0))
+}
+
+func TestPermissionsFs_ShouldLstatWhenWrappedFsSupportsSymlinks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// act
+	_, osLstatCalled, osErr := NewPermissionsFs(afero.NewOsFs(), 0640, 0750).(afero.Lstater).LstatIfPossible(dir)
+	_, memLstatCalled, _ := NewPermissionsFs(afero.NewMemMapFs(), 0640, 0750).(afero.Lstater).LstatIfPossible(dir)
+
+	// assert
+	require.NoError(t, osErr)
+	require.True(t, osLstatCalled, "Symlinks should be detectable through the os filesystem")
+	require.False(t, memLstatCalled, "The memory filesystem does not support symlinks")
+}
+
+func TestInitExecution_ShouldRestrictRegionalWorkingCopyPermissions(t *testing.T) {
+	t.Parallel()
+
+	stepDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(stepDir, "regional", "tests"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(stepDir, "regional", "main.tf"), []byte(""), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(stepDir, "regional", "tests", "tests.test"), []byte(""), 0755))
+
+	stubStep := config.Step{
+		Dir:          stepDir,
+		Name:         "stubName",
+		DeployConfig: config.Config{FileMode: 0600, DirMode: 0700},
+	}
+
+	// act
+	exec, err := InitExecution(context.Background(), stubStep, logger, afero.NewOsFs(), config.RegionalRegionDeployType, "us-east-2", map[string]map[string]string{})
+
+	// assert
+	require.NoError(t, err)
+
+	for path, expected := range map[string]os.FileMode{
+		exec.Dir:                                       0700,
+		filepath.Join(exec.Dir, "tests"):               0700,
+		filepath.Join(exec.Dir, "main.tf"):             0600,
+		filepath.Join(exec.Dir, "tests", "tests.test"): 0700,
+	} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, info.Mode().Perm(), path)
+	}
+}
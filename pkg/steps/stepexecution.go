@@ -1,6 +1,7 @@
 package steps
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/optum/runiac/pkg/cloudaccountdeployment"
@@ -13,8 +14,15 @@ import (
 	"strings"
 )
 
-func NewExecution(s config.Step, logger *logrus.Entry, fs afero.Fs, regionDeployType config.RegionDeployType, region string, defaultStepOutputVariables map[string]map[string]string) config.StepExecution {
+// NewExecution is the step's execution in the region, cancelling ctx interrupts the execution's runner processes
+func NewExecution(ctx context.Context, s config.Step, logger *logrus.Entry, fs afero.Fs, regionDeployType config.RegionDeployType, region string, defaultStepOutputVariables map[string]map[string]string) config.StepExecution {
+	expectedOutputs := s.Config.ExpectedOutputs
+	if regionDeployType == config.RegionalRegionDeployType {
+		expectedOutputs = s.Config.ExpectedRegionalOutputs
+	}
+
 	return config.StepExecution{
+		Context:                    ctx,
 		RegionDeployType:           regionDeployType,
 		Region:                     region,
 		Fs:                         fs,
@@ -39,6 +47,21 @@ func NewExecution(s config.Step, logger *logrus.Entry, fs afero.Fs, regionDeploy
 		UniqueExternalExecutionID:  s.DeployConfig.UniqueExternalExecutionID,
 		RegionGroups:               s.DeployConfig.RegionGroups,
 		SelfDestroy:                s.DeployConfig.SelfDestroy,
+		ShutdownGracePeriod:        s.DeployConfig.ShutdownGracePeriod,
+		PolicyCommand:              s.DeployConfig.PolicyCommand,
+		PolicyWarnOnly:             s.DeployConfig.PolicyWarnOnly,
+		TestAgainstPlan:            s.DeployConfig.TestAgainstPlan,
+		EmitInventory:              s.DeployConfig.EmitInventory,
+		ExpectedOutputs:            expectedOutputs,
+		ExpectedOutputsWarnOnly:    s.Config.ExpectedOutputsWarnOnly,
+		RequireOutputs:             s.DeployConfig.RequireStepOutputs,
+		TestRunner:                 s.Config.TestRunner,
+		TestCommand:                s.Config.TestCommand,
+		EnvAllowlist:               cleanEnvAllowlist(s.DeployConfig),
+		ProviderUserAgentSuffix:    strings.ReplaceAll(s.DeployConfig.ProviderUserAgentSuffix, "{run_id}", s.DeployConfig.UniqueExternalExecutionID),
+		BundledPlanFile:            bundledPlanFile(s, regionDeployType, region),
+		TerraformParallelism:       terraformParallelism(s),
+		AllowDestroyPlanChanges:    s.DeployConfig.AllowDestroyPlanChanges,
 		Logger: logger.WithFields(logrus.Fields{
 			"step":            s.Name,
 			"stepProgression": s.ProgressionLevel,
@@ -46,6 +69,42 @@ func NewExecution(s config.Step, logger *logrus.Entry, fs afero.Fs, regionDeploy
 	}
 }
 
+// CleanEnvBaseline are the inherited environment variables runners need to function, allowed in every clean environment
+var CleanEnvBaseline = []string{"PATH", "HOME", "TMPDIR"}
+
+// cleanEnvAllowlist returns the inherited environment variables runners are limited to, nil when the environment is
+// not cleaned
+func cleanEnvAllowlist(cfg config.Config) []string {
+	if !cfg.CleanEnv {
+		return nil
+	}
+
+	return append(append([]string{}, CleanEnvBaseline...), cfg.CleanEnvAllowlist...)
+}
+
+// terraformParallelism returns the step's configured terraform parallelism, defaulting to the deployment's
+func terraformParallelism(s config.Step) int {
+	if s.Config.TerraformParallelism > 0 {
+		return s.Config.TerraformParallelism
+	}
+
+	return s.DeployConfig.TerraformParallelism
+}
+
+// BundledPlanName returns the path of a step execution's plan within a plan bundle
+func BundledPlanName(trackName string, stepName string, regionDeployType config.RegionDeployType, region string) string {
+	return filepath.Join("plans", trackName, stepName, fmt.Sprintf("%s-%s.tfplan", regionDeployType, region))
+}
+
+// bundledPlanFile returns the extracted plan a step execution applies, empty unless applying a plan bundle
+func bundledPlanFile(s config.Step, regionDeployType config.RegionDeployType, region string) string {
+	if s.DeployConfig.BundledPlansDir == "" {
+		return ""
+	}
+
+	return filepath.Join(s.DeployConfig.BundledPlansDir, BundledPlanName(s.TrackName, s.Name, regionDeployType, region))
+}
+
 func ExecuteStep(stepper config.Stepper, exec config.StepExecution) config.StepOutput {
 
 	// Check if the step is filtered in the configuration // TODO: step configuration override
@@ -67,10 +126,49 @@ func ExecuteStep(stepper config.Stepper, exec config.StepExecution) config.StepO
 	exec.Logger.Debugf("%v", exec.OptionalStepParams)
 
 	output := stepper.ExecuteStep(exec)
+	output = validateExpectedOutputs(exec, output)
 	postStep(exec, output)
 	return output
 }
 
+// validateExpectedOutputs fails a successful step, or warns when configured, if any of the step's expected outputs
+// were not exported or, when outputs are required, it exported none. Dry runs are not validated as outputs are only
+// updated by apply.
+func validateExpectedOutputs(exec config.StepExecution, output config.StepOutput) config.StepOutput {
+	if exec.DryRun || output.Status != config.Success {
+		return output
+	}
+
+	var missing []string
+	for _, name := range exec.ExpectedOutputs {
+		if _, ok := output.OutputVariables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	var err error
+	if len(missing) > 0 {
+		err = fmt.Errorf("step %s is missing expected outputs: %s", exec.StepName, strings.Join(missing, ", "))
+	} else if exec.RequireOutputs && len(output.OutputVariables) == 0 {
+		err = fmt.Errorf("step %s did not produce any output variables", exec.StepName)
+	}
+
+	if err == nil {
+		return output
+	}
+
+	if exec.ExpectedOutputsWarnOnly {
+		exec.Logger.WithError(err).Warn("Step is missing expected outputs, continuing due to warn only mode")
+		return output
+	}
+
+	exec.Logger.WithError(err).Error("Step is missing expected outputs")
+	output.Status = config.Fail
+	output.Err = err
+
+	return output
+}
+
 func ExecuteStepDestroy(stepper config.Stepper, exec config.StepExecution) config.StepOutput {
 	return stepper.ExecuteStepDestroy(exec)
 }
@@ -81,30 +179,40 @@ func ExecuteStepTests(stepper config.Stepper, exec config.StepExecution) config.
 	return output
 }
 
-func InitExecution(s config.Step, logger *logrus.Entry, fs afero.Fs,
+// RegionalWorkdir is the working directory a step's regional resources are copied to for a regional execution in region,
+// isolating the concurrent regional executions from each other
+func RegionalWorkdir(s config.Step, region string) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("regional-%s", region))
+}
+
+// InitExecution prepares the step's execution, returning an InitError when its execution directory cannot be prepared
+func InitExecution(ctx context.Context, s config.Step, logger *logrus.Entry, fs afero.Fs,
 	regionDeployType config.RegionDeployType, region string,
 	defaultStepOutputVariables map[string]map[string]string) (
 	config.StepExecution, error) {
-	exec := NewExecution(s, logger, fs, regionDeployType, region, defaultStepOutputVariables)
+	exec := NewExecution(ctx, s, logger, fs, regionDeployType, region, defaultStepOutputVariables)
 
 	// set and create execution directory to enable safe concurrency
 	if exec.RegionDeployType == config.RegionalRegionDeployType {
 		regionalDir := filepath.Join(s.Dir, "regional")
-		execRegionalDir := filepath.Join(s.Dir, fmt.Sprintf("regional-%s", exec.Region))
+		execRegionalDir := RegionalWorkdir(s, exec.Region)
 		err := exec.Fs.MkdirAll(execRegionalDir, 0700)
 
 		if err != nil {
 			exec.Logger.WithError(err).Error(err)
-			return exec, err
+			return exec, newInitError(err)
 		}
 
 		exec.Logger.Infof("Copying %s regional to %s", exec.Region, execRegionalDir)
 
 		err = copy.Copy(regionalDir, execRegionalDir)
+		if err == nil {
+			err = RestrictPermissions(execRegionalDir, s.DeployConfig.FileMode, s.DeployConfig.DirMode)
+		}
 
 		if err != nil {
 			exec.Logger.WithError(err).Error(err)
-			return exec, err
+			return exec, newInitError(err)
 		}
 
 		exec.Dir = execRegionalDir
@@ -1,8 +1,12 @@
 package steps
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/optum/runiac/mocks"
 	"github.com/optum/runiac/pkg/config"
 
 	"github.com/sirupsen/logrus"
@@ -34,8 +38,11 @@ func TestNewExecution_ShouldSetFields(t *testing.T) {
 		},
 		TrackName: "stubTrackName",
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// act
-	mock := NewExecution(stubStep, logger, fs, stubRegionalDeployType, stubRegion, map[string]map[string]string{})
+	mock := NewExecution(ctx, stubStep, logger, fs, stubRegionalDeployType, stubRegion, map[string]map[string]string{})
 
 	// assert
 	require.Equal(t, stubStep.Dir, mock.Dir, "Dir should match stub value")
@@ -50,5 +57,234 @@ func TestNewExecution_ShouldSetFields(t *testing.T) {
 	require.Equal(t, stubStep.DeployConfig.RegionalRegions, mock.RegionGroupRegions, "RegionGroupRegions should match stub value")
 	require.Equal(t, stubStep.DeployConfig.MaxRetries, mock.MaxRetries, "MaxRetries should match stub value")
 	require.Equal(t, stubStep.DeployConfig.MaxTestRetries, mock.MaxTestRetries, "MaxTestRetries should match stub value")
+	require.Equal(t, ctx, mock.Context, "Context should interrupt the execution's runner processes")
+
+}
+
+func TestExecuteStep_ShouldValidateExpectedOutputs(t *testing.T) {
+	tests := map[string]struct {
+		expectedOutputs []string
+		warnOnly        bool
+		dryRun          bool
+		expectedStatus  config.DeployResult
+		expectedErr     bool
+	}{
+		"ShouldFailWhenExpectedOutputIsMissing": {
+			expectedOutputs: []string{"bucket_name", "bucket_arn"},
+			expectedStatus:  config.Fail,
+			expectedErr:     true,
+		},
+		"ShouldWarnWhenExpectedOutputIsMissingInWarnOnlyMode": {
+			expectedOutputs: []string{"bucket_name", "bucket_arn"},
+			warnOnly:        true,
+			expectedStatus:  config.Success,
+		},
+		"ShouldNotValidateDryRuns": {
+			expectedOutputs: []string{"bucket_name", "bucket_arn"},
+			dryRun:          true,
+			expectedStatus:  config.Success,
+		},
+		"ShouldSucceedWhenAllExpectedOutputsExist": {
+			expectedOutputs: []string{"bucket_name"},
+			expectedStatus:  config.Success,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubRunner := mocks.NewMockStepper(ctrl)
+			stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{
+				Status:          config.Success,
+				OutputVariables: map[string]interface{}{"bucket_name": "logs"},
+			})
+
+			exec := NewExecution(context.Background(), config.Step{
+				Name:         "bucket",
+				DeployConfig: config.Config{DryRun: test.dryRun},
+				Config: config.StepConfig{
+					ExpectedOutputs:         test.expectedOutputs,
+					ExpectedRegionalOutputs: []string{"ignored_in_primary"},
+					ExpectedOutputsWarnOnly: test.warnOnly,
+				},
+			}, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "us-east-1", map[string]map[string]string{})
+
+			// act
+			output := ExecuteStep(stubRunner, exec)
+
+			// assert
+			require.Equal(t, test.expectedStatus, output.Status)
+			if test.expectedErr {
+				require.EqualError(t, output.Err, "step bucket is missing expected outputs: bucket_arn", "Missing expected outputs should be flagged")
+			} else {
+				require.NoError(t, output.Err)
+			}
+		})
+	}
+}
+
+func TestNewExecution_ShouldUseRegionalExpectedOutputsForRegionalExecutions(t *testing.T) {
+	stubStep := config.Step{
+		Config: config.StepConfig{
+			ExpectedOutputs:         []string{"role_arn"},
+			ExpectedRegionalOutputs: []string{"bucket_arn"},
+		},
+	}
+
+	primary := NewExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "us-east-1", nil)
+	regional := NewExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.RegionalRegionDeployType, "us-east-2", nil)
+
+	require.Equal(t, []string{"role_arn"}, primary.ExpectedOutputs)
+	require.Equal(t, []string{"bucket_arn"}, regional.ExpectedOutputs)
+}
+
+func TestNewExecution_ShouldReplaceRunIDInProviderUserAgentSuffix(t *testing.T) {
+	t.Parallel()
+
+	stubStep := config.Step{
+		Dir: "stub",
+		DeployConfig: config.Config{
+			UniqueExternalExecutionID: "run-123",
+			ProviderUserAgentSuffix:   "runiac/{run_id}",
+		},
+	}
+
+	// act
+	exec := NewExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "region", map[string]map[string]string{})
+
+	// assert
+	require.Equal(t, "runiac/run-123", exec.ProviderUserAgentSuffix)
+}
+
+func TestExecuteStep_ShouldRequireStepOutputs(t *testing.T) {
+	tests := map[string]struct {
+		requireOutputs  bool
+		outputVariables map[string]interface{}
+		expectedStatus  config.DeployResult
+		expectedErr     bool
+	}{
+		"ShouldFailStepWithoutOutputsWhenRequired": {
+			requireOutputs:  true,
+			outputVariables: map[string]interface{}{},
+			expectedStatus:  config.Fail,
+			expectedErr:     true,
+		},
+		"ShouldSucceedStepWithOutputsWhenRequired": {
+			requireOutputs:  true,
+			outputVariables: map[string]interface{}{"bucket_name": "logs"},
+			expectedStatus:  config.Success,
+		},
+		"ShouldSucceedStepWithoutOutputsByDefault": {
+			outputVariables: map[string]interface{}{},
+			expectedStatus:  config.Success,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
+			stubRunner := mocks.NewMockStepper(ctrl)
+			stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{
+				Status:          config.Success,
+				OutputVariables: test.outputVariables,
+			})
+
+			exec := NewExecution(context.Background(), config.Step{
+				Name:         "bucket",
+				DeployConfig: config.Config{RequireStepOutputs: test.requireOutputs},
+			}, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "us-east-1", map[string]map[string]string{})
+
+			// act
+			output := ExecuteStep(stubRunner, exec)
+
+			// assert
+			require.Equal(t, test.expectedStatus, output.Status)
+			if test.expectedErr {
+				require.EqualError(t, output.Err, "step bucket did not produce any output variables")
+			} else {
+				require.NoError(t, output.Err)
+			}
+		})
+	}
+}
+
+func TestNewExecution_ShouldLimitRunnerEnvironmentWhenCleanEnvIsSet(t *testing.T) {
+	t.Parallel()
+
+	cleanExec := NewExecution(context.Background(), config.Step{DeployConfig: config.Config{CleanEnv: true, CleanEnvAllowlist: []string{"AWS_*"}}}, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "region", map[string]map[string]string{})
+	require.Equal(t, []string{"PATH", "HOME", "TMPDIR", "AWS_*"}, cleanExec.EnvAllowlist)
+
+	exec := NewExecution(context.Background(), config.Step{DeployConfig: config.Config{CleanEnvAllowlist: []string{"AWS_*"}}}, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "region", map[string]map[string]string{})
+	require.Nil(t, exec.EnvAllowlist, "The environment should only be limited when clean env is set")
+}
+
+func TestNewExecution_ShouldApplyBundledPlanWhenApplyingPlanBundle(t *testing.T) {
+	t.Parallel()
+
+	stubStep := config.Step{
+		Name:      "deploy",
+		TrackName: "network",
+	}
+
+	// act
+	exec := NewExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.RegionalRegionDeployType, "us-east-2", nil)
+	require.Empty(t, exec.BundledPlanFile, "Steps should be planned unless applying a plan bundle")
+
+	stubStep.DeployConfig.BundledPlansDir = "/tmp/bundle"
+	exec = NewExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.RegionalRegionDeployType, "us-east-2", nil)
+
+	// assert
+	require.Equal(t, "/tmp/bundle/plans/network/deploy/regional-us-east-2.tfplan", exec.BundledPlanFile)
+}
+
+func TestNewExecution_ShouldSetTerraformParallelism(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		global   int
+		step     int
+		expected int
+	}{
+		"ShouldNotLimitByDefault":       {expected: 0},
+		"ShouldUseGlobalDefault":        {global: 10, expected: 10},
+		"ShouldPreferStepConfiguration": {global: 10, step: 2, expected: 2},
+		"ShouldUseStepWithoutGlobalSet": {step: 3, expected: 3},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			stubStep := config.Step{
+				DeployConfig: config.Config{TerraformParallelism: test.global},
+				Config:       config.StepConfig{TerraformParallelism: test.step},
+			}
+
+			// act
+			exec := NewExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.PrimaryRegionDeployType, "us-east-1", nil)
+
+			// assert
+			require.Equal(t, test.expected, exec.TerraformParallelism)
+		})
+	}
+}
+
+func TestInitExecution_ShouldCategorizeMissingRegionalDir(t *testing.T) {
+	t.Parallel()
+
+	stubStep := config.Step{
+		Dir:  t.TempDir(),
+		Name: "stubName",
+	}
+
+	// act
+	_, err := InitExecution(context.Background(), stubStep, logger, afero.NewMemMapFs(), config.RegionalRegionDeployType, "us-east-2", map[string]map[string]string{})
+
+	// assert
+	var initErr InitError
+	require.True(t, errors.As(err, &initErr), "Init failures should be an InitError")
+	require.Equal(t, MissingDirInitError, initErr.Cause)
 }
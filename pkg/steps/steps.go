@@ -4,15 +4,110 @@ package steps
 
 import (
 	"fmt"
+	pluginsmock "github.com/optum/runiac/plugins/mock"
 	pluginsterraform "github.com/optum/runiac/plugins/terraform"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/optum/runiac/pkg/config"
+	"github.com/spf13/afero"
 )
 
-func DetermineRunner(s config.Step) config.Stepper {
-	// TODO(plugins): support multiple plugin step runners
-	return pluginsterraform.TerraformStepper{}
+// Runners are the step runners that can be selected by name in a step's configuration
+var Runners = map[string]config.Stepper{}
+
+// RunnerDetector returns the files directly in dir that a runner would execute, none when the runner does not apply
+type RunnerDetector func(fs afero.Fs, dir string) []string
+
+type detectedRunner struct {
+	name   string
+	detect RunnerDetector
+}
+
+// detectedRunners are the registered runners, in the order they are detected in
+var detectedRunners []detectedRunner
+
+func init() {
+	RegisterRunner("terraform", GlobDetector("*.tf"), pluginsterraform.TerraformStepper{})
+}
+
+// RegisterRunner registers a runner, e.g. from a plugin's init, making it selectable by name in a step's configuration
+// and detected for steps containing files it detects. Runners are detected in the order they are registered, a runner
+// registered again under the same name replaces the earlier registration
+func RegisterRunner(name string, detect RunnerDetector, runner config.Stepper) {
+	name = strings.ToLower(name)
+	Runners[name] = runner
+
+	for i, r := range detectedRunners {
+		if r.name == name {
+			detectedRunners[i].detect = detect
+			return
+		}
+	}
+
+	detectedRunners = append(detectedRunners, detectedRunner{name: name, detect: detect})
+}
+
+// GlobDetector detects the files directly in a directory matching any of the patterns, e.g. *.tf
+func GlobDetector(patterns ...string) RunnerDetector {
+	return func(fs afero.Fs, dir string) (files []string) {
+		for _, pattern := range patterns {
+			matches, _ := afero.Glob(fs, filepath.Join(dir, pattern))
+			files = append(files, matches...)
+		}
+
+		return files
+	}
+}
+
+// DetectRunner returns the name of the first registered runner detecting files directly in dir, along with the files
+// it detected. The name is empty when no runner applies to dir
+func DetectRunner(fs afero.Fs, dir string) (name string, files []string) {
+	for _, r := range detectedRunners {
+		if files := r.detect(fs, dir); len(files) > 0 {
+			return r.name, files
+		}
+	}
+
+	return "", nil
+}
+
+// DetermineRunner returns the runner for a step, preferring the runner named in the step's configuration, then the
+// first registered runner detecting the step's files, or its regional files. Every step is simulated with canned outputs
+// when mocking the provider
+func DetermineRunner(fs afero.Fs, s config.Step) (config.Stepper, error) {
+	if s.DeployConfig.MockProvider {
+		return pluginsmock.MockStepper{FixturesFile: s.DeployConfig.MockFixturesFile}, nil
+	}
+
+	if s.Config.Runner != "" {
+		runner, ok := Runners[strings.ToLower(s.Config.Runner)]
+		if !ok {
+			return nil, fmt.Errorf("unknown runner %q for step %s, expected one of %v", s.Config.Runner, s.ID, runnerNames())
+		}
+
+		return runner, nil
+	}
+
+	for _, dir := range []string{s.Dir, filepath.Join(s.Dir, "regional")} {
+		if name, _ := DetectRunner(fs, dir); name != "" {
+			return Runners[name], nil
+		}
+	}
+
+	// steps were deployed with terraform before runners were detected
+	return pluginsterraform.TerraformStepper{}, nil
+}
+
+func runnerNames() []string {
+	names := make([]string, 0, len(Runners))
+	for name := range Runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
 }
 
 // Adds previous step output to stepParams which get added as environment variables
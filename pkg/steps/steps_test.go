@@ -2,6 +2,8 @@ package steps_test
 
 import (
 	"flag"
+	"github.com/golang/mock/gomock"
+	"github.com/optum/runiac/mocks"
 	"github.com/optum/runiac/pkg/config"
 	plugins_terraform "github.com/optum/runiac/plugins/terraform"
 	"os"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/optum/runiac/pkg/steps"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,3 +57,66 @@ func TestAddTrackOutputToParams(t *testing.T) {
 	require.Equal(t, "v2", mockParams["cool_step1-k2"], "stepParams should be set with the correct key and value")
 	require.Equal(t, "v3", mockParams["cool_step2-k3"], "stepParams should be set with the correct key and value")
 }
+
+func TestDetermineRunner_ShouldPreferConfiguredRunnerOverDetection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	steps.Runners["script"] = stubRunner
+	defer delete(steps.Runners, "script")
+
+	// act
+	detected, err := steps.DetermineRunner(afero.NewMemMapFs(), config.Step{ID: "#runiac#track#detected"})
+	overridden, overrideErr := steps.DetermineRunner(afero.NewMemMapFs(), config.Step{ID: "#runiac#track#overridden", Config: config.StepConfig{Runner: "Script"}})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, plugins_terraform.TerraformStepper{}, detected, "Runner should be detected when not configured")
+	require.NoError(t, overrideErr)
+	require.Equal(t, stubRunner, overridden, "Configured runner should take precedence over detection")
+}
+
+func TestDetermineRunner_ShouldDetectRegisteredRunners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	steps.RegisterRunner("pulumi", steps.GlobDetector("Pulumi.yaml", "Pulumi.yml"), stubRunner)
+	defer func() {
+		steps.RegisterRunner("pulumi", func(fs afero.Fs, dir string) []string { return nil }, nil)
+		delete(steps.Runners, "pulumi")
+	}()
+
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_pulumi/Pulumi.yaml", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step2_regional/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step3_both/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step3_both/Pulumi.yaml", []byte(""), 0644)
+
+	// act
+	pulumi, err := steps.DetermineRunner(stubFs, config.Step{ID: "#runiac#track#pulumi", Dir: "tracks/track/step1_pulumi"})
+	regional, regionalErr := steps.DetermineRunner(stubFs, config.Step{ID: "#runiac#track#regional", Dir: "tracks/track/step2_regional"})
+	both, bothErr := steps.DetermineRunner(stubFs, config.Step{ID: "#runiac#track#both", Dir: "tracks/track/step3_both"})
+	name, files := steps.DetectRunner(stubFs, "tracks/track/step1_pulumi")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, stubRunner, pulumi, "A registered runner should be detected by its files")
+	require.NoError(t, regionalErr)
+	require.Equal(t, plugins_terraform.TerraformStepper{}, regional, "Runners should be detected from regional files")
+	require.NoError(t, bothErr)
+	require.Equal(t, plugins_terraform.TerraformStepper{}, both, "The first registered runner should be detected")
+	require.Equal(t, "pulumi", name)
+	require.Equal(t, []string{"tracks/track/step1_pulumi/Pulumi.yaml"}, files)
+}
+
+func TestDetermineRunner_ShouldErrorForUnknownRunner(t *testing.T) {
+	// act
+	runner, err := steps.DetermineRunner(afero.NewMemMapFs(), config.Step{ID: "#runiac#track#step", Config: config.StepConfig{Runner: "doesnotexist"}})
+
+	// assert
+	require.Error(t, err)
+	require.Nil(t, runner)
+	require.Contains(t, err.Error(), "doesnotexist")
+}
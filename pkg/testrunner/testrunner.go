@@ -0,0 +1,121 @@
+// Package testrunner executes step tests independently of the framework they are written in
+package testrunner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/shell"
+	"github.com/spf13/afero"
+)
+
+const (
+	GoTestRunnerName      = "go"      // Runs a compiled go test binary, compiling test sources when no binary exists
+	CommandTestRunnerName = "command" // Runs a shell command
+)
+
+// GoTestRunner runs the tests.test binary in a step's tests directory with gotestsum, reporting results as junit.
+// When no prebuilt binary exists, the go test sources in the directory are compiled into one.
+type GoTestRunner struct{}
+
+// CommandTestRunner runs Command with sh from the step's directory, a nonzero exit fails the tests
+type CommandTestRunner struct {
+	Command string
+}
+
+// Determine selects the test runner for a step execution. A configured runner or command is used when set, otherwise
+// the runner is detected from the contents of testDir.
+func Determine(exec config.StepExecution, testDir string) (config.TestRunner, error) {
+	switch strings.ToLower(exec.TestRunner) {
+	case GoTestRunnerName:
+		return GoTestRunner{}, nil
+	case CommandTestRunnerName:
+		if exec.TestCommand == "" {
+			return nil, fmt.Errorf("step %s uses the %s test runner without a test command", exec.StepName, CommandTestRunnerName)
+		}
+		return CommandTestRunner{Command: exec.TestCommand}, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("step %s uses an unknown test runner %s, supported runners are %s, %s", exec.StepName, exec.TestRunner, GoTestRunnerName, CommandTestRunnerName)
+	}
+
+	if exec.TestCommand != "" {
+		return CommandTestRunner{Command: exec.TestCommand}, nil
+	}
+
+	if exists, _ := afero.Exists(exec.Fs, filepath.Join(testDir, "tests.test")); exists {
+		return GoTestRunner{}, nil
+	}
+
+	if sources, _ := afero.Glob(exec.Fs, filepath.Join(testDir, "*_test.go")); len(sources) > 0 {
+		return GoTestRunner{}, nil
+	}
+
+	if scripts, _ := afero.Glob(exec.Fs, filepath.Join(testDir, "*.bats")); len(scripts) > 0 {
+		return CommandTestRunner{Command: `bats --tap "$RUNIAC_TEST_DIR"`}, nil
+	}
+
+	return nil, fmt.Errorf("no tests.test binary, go test sources, bats scripts or test command found for %s", testDir)
+}
+
+// RunTests executes the compiled go tests in testDir
+func (r GoTestRunner) RunTests(exec config.StepExecution, testDir string, env map[string]string) (string, error) {
+	if exists, _ := afero.Exists(exec.Fs, filepath.Join(testDir, "tests.test")); !exists {
+		exec.Logger.Info("No prebuilt tests.test binary found, compiling tests")
+
+		resp, err := shell.RunShellCommandAndGetAndStreamOutput(shell.Command{
+			Command:             "go",
+			Args:                []string{"test", "-c", "-o", "tests.test", "."},
+			Logger:              exec.Logger,
+			NonInteractive:      true,
+			WorkingDir:          testDir,
+			EnvAllowlist:        exec.EnvAllowlist,
+			Context:             exec.Context,
+			ShutdownGracePeriod: exec.ShutdownGracePeriod,
+		})
+		if err != nil {
+			return resp, fmt.Errorf("unable to compile tests in %s: %w", testDir, err)
+		}
+	}
+
+	stepDeployID := fmt.Sprintf("%s-%s-%s-%s-%s", exec.Project, exec.TrackName, exec.StepName, exec.RegionDeployType, exec.Region)
+
+	return shell.RunShellCommandAndGetAndStreamOutput(shell.Command{
+		Command:             "gotestsum",
+		Args:                []string{"--format", "standard-verbose", "--junitfile", fmt.Sprintf("/output/junit/%s.xml", stepDeployID), "--raw-command", "--", "test2json", "-p", stepDeployID, "./tests.test", "-test.v"},
+		Logger:              exec.Logger,
+		SensitiveArgs:       false,
+		NonInteractive:      true,
+		Env:                 env,
+		EnvAllowlist:        exec.EnvAllowlist,
+		WorkingDir:          testDir,
+		Context:             exec.Context,
+		ShutdownGracePeriod: exec.ShutdownGracePeriod,
+	})
+}
+
+// RunTests executes the test command, exposing the absolute path of testDir as RUNIAC_TEST_DIR
+func (r CommandTestRunner) RunTests(exec config.StepExecution, testDir string, env map[string]string) (string, error) {
+	if abs, err := filepath.Abs(testDir); err == nil {
+		testDir = abs
+	}
+
+	cmdEnv := map[string]string{"RUNIAC_TEST_DIR": testDir}
+	for k, v := range env {
+		cmdEnv[k] = v
+	}
+
+	return shell.RunShellCommandAndGetAndStreamOutput(shell.Command{
+		Command:             "sh",
+		Args:                []string{"-c", r.Command},
+		Logger:              exec.Logger,
+		NonInteractive:      true,
+		Env:                 cmdEnv,
+		EnvAllowlist:        exec.EnvAllowlist,
+		WorkingDir:          exec.Dir,
+		Context:             exec.Context,
+		ShutdownGracePeriod: exec.ShutdownGracePeriod,
+	})
+}
@@ -0,0 +1,71 @@
+package testrunner_test
+
+import (
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/testrunner"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var logger = logrus.NewEntry(logrus.New())
+
+func TestDetermine_ShouldSelectRunnerFromConfigurationOrTestContents(t *testing.T) {
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "binary/tests/tests.test", []byte(""), 0755)
+	_ = afero.WriteFile(stubFs, "gosource/tests/step_test.go", []byte("package tests"), 0644)
+	_ = afero.WriteFile(stubFs, "bats/tests/step.bats", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "none/tests/README.md", []byte(""), 0644)
+
+	tests := map[string]struct {
+		exec           config.StepExecution
+		testDir        string
+		expectedRunner config.TestRunner
+		expectedErr    bool
+	}{
+		"ShouldDetectBinary":                            {testDir: "binary/tests", expectedRunner: testrunner.GoTestRunner{}},
+		"ShouldDetectGoSources":                         {testDir: "gosource/tests", expectedRunner: testrunner.GoTestRunner{}},
+		"ShouldDetectBats":                              {testDir: "bats/tests", expectedRunner: testrunner.CommandTestRunner{Command: `bats --tap "$RUNIAC_TEST_DIR"`}},
+		"ShouldErrorWithoutTests":                       {testDir: "none/tests", expectedErr: true},
+		"ShouldUseConfiguredCommand":                    {exec: config.StepExecution{TestCommand: "npm test"}, testDir: "binary/tests", expectedRunner: testrunner.CommandTestRunner{Command: "npm test"}},
+		"ShouldUseConfiguredRunner":                     {exec: config.StepExecution{TestRunner: "Go", TestCommand: "npm test"}, testDir: "none/tests", expectedRunner: testrunner.GoTestRunner{}},
+		"ShouldErrorForCommandRunnerWithoutTestCommand": {exec: config.StepExecution{TestRunner: "command"}, testDir: "binary/tests", expectedErr: true},
+		"ShouldErrorForUnknownConfiguration":            {exec: config.StepExecution{TestRunner: "pytest"}, testDir: "binary/tests", expectedErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			exec := test.exec
+			exec.Fs = stubFs
+
+			runner, err := testrunner.Determine(exec, test.testDir)
+
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedRunner, runner)
+		})
+	}
+}
+
+func TestCommandTestRunner_ShouldPassTestEnvironmentToCommand(t *testing.T) {
+	exec := config.StepExecution{Logger: logger}
+
+	out, err := testrunner.CommandTestRunner{Command: `echo "$TF_VAR_bucket in $(basename "$RUNIAC_TEST_DIR")"`}.RunTests(exec, "tests", map[string]string{"TF_VAR_bucket": "logs"})
+
+	require.NoError(t, err)
+	require.Contains(t, out, "logs in tests", "Command should receive the test environment")
+}
+
+func TestCommandTestRunner_ShouldFailTestsWhenCommandFails(t *testing.T) {
+	exec := config.StepExecution{Logger: logger}
+
+	_, err := testrunner.CommandTestRunner{Command: "exit 1"}.RunTests(exec, "tests", nil)
+
+	require.Error(t, err, "A failing command should fail the tests")
+}
@@ -0,0 +1,191 @@
+package tracks
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/steps"
+	"github.com/spf13/afero"
+)
+
+const (
+	// PlanBundleConfigFile is the resolved configuration the bundle's plans were created with
+	PlanBundleConfigFile = "config.json"
+	// PlanBundleManifestFile lists the plans within the bundle
+	PlanBundleManifestFile = "manifest.json"
+)
+
+// PlanBundleManifest describes the contents of a plan bundle for review
+type PlanBundleManifest struct {
+	Plans []BundledPlan `json:"plans"`
+}
+
+// BundledPlan is a step execution's plan within a plan bundle
+type BundledPlan struct {
+	StepID           string `json:"stepId"`
+	Track            string `json:"track"`
+	Step             string `json:"step"`
+	RegionDeployType string `json:"regionDeployType"`
+	Region           string `json:"region"`
+	File             string `json:"file"` // The plan's path within the bundle
+}
+
+// WritePlanBundle writes the plan of every step execution in the stage, the resolved configuration and a manifest of
+// the plans to a gzipped tar at path
+func WritePlanBundle(fs afero.Fs, path string, cfg config.Config, stage Stage) (err error) {
+	manifest := PlanBundleManifest{Plans: []BundledPlan{}}
+	planFiles := map[string]string{} // K=file within the bundle, V=plan file
+
+	for _, t := range stage.Tracks {
+		for _, exec := range t.Output.Executions {
+			for _, s := range exec.Output.Steps {
+				if s.Output.PlanFile == "" {
+					continue
+				}
+
+				plan := BundledPlan{
+					StepID:           s.ID,
+					Track:            t.Name,
+					Step:             s.Name,
+					RegionDeployType: exec.RegionDeployType.String(),
+					Region:           exec.Region,
+					File:             filepath.ToSlash(steps.BundledPlanName(t.Name, s.Name, exec.RegionDeployType, exec.Region)),
+				}
+
+				manifest.Plans = append(manifest.Plans, plan)
+				planFiles[plan.File] = s.Output.PlanFile
+			}
+		}
+	}
+
+	// keep the manifest stable for reviewers comparing bundles
+	sort.Slice(manifest.Plans, func(i, j int) bool { return manifest.Plans[i].File < manifest.Plans[j].File })
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := fs.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	configJSON, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeBundleFile(tw, PlanBundleConfigFile, configJSON); err != nil {
+		return err
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeBundleFile(tw, PlanBundleManifestFile, manifestJSON); err != nil {
+		return err
+	}
+
+	for _, plan := range manifest.Plans {
+		b, err := afero.ReadFile(fs, planFiles[plan.File])
+		if err != nil {
+			return fmt.Errorf("unable to read %s plan: %w", plan.StepID, err)
+		}
+
+		if err := writeBundleFile(tw, plan.File, b); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+func writeBundleFile(tw *tar.Writer, name string, b []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(b))}); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(b)
+	return err
+}
+
+// ExtractPlanBundle extracts the plan bundle at path to dir, returning the bundle's manifest
+func ExtractPlanBundle(fs afero.Fs, path string, dir string) (manifest PlanBundleManifest, err error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return manifest, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return manifest, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// guard against entries escaping the extraction directory
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return manifest, fmt.Errorf("plan bundle entry %s is outside of the bundle", header.Name)
+		}
+
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return manifest, err
+		}
+
+		if name == PlanBundleManifestFile {
+			if err := json.Unmarshal(b, &manifest); err != nil {
+				return manifest, fmt.Errorf("unable to read plan bundle manifest: %w", err)
+			}
+		}
+
+		file := filepath.Join(dir, name)
+		if err := fs.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return manifest, err
+		}
+
+		if err := afero.WriteFile(fs, file, b, 0644); err != nil {
+			return manifest, err
+		}
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,106 @@
+package tracks_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/steps"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePlanBundle_ShouldBundlePlansConfigAndManifest(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/vpcprimaryus-east-1tfplan", []byte("primary plan"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/regional-us-east-2/vpcregionalus-east-2tfplan", []byte("regional plan"), 0644)
+
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Region:           "us-east-1",
+							Output: tracks.ExecutionOutput{Steps: map[string]config.Step{
+								"vpc": {ID: "#project#network#vpc", Name: "vpc", Output: config.StepOutput{PlanFile: "tracks/network/step1_vpc/vpcprimaryus-east-1tfplan"}},
+								"dns": {ID: "#project#network#dns", Name: "dns", Output: config.StepOutput{Status: config.Skipped}},
+							}},
+						},
+						{
+							RegionDeployType: config.RegionalRegionDeployType,
+							Region:           "us-east-2",
+							Output: tracks.ExecutionOutput{Steps: map[string]config.Step{
+								"vpc": {ID: "#project#network#vpc", Name: "vpc", Output: config.StepOutput{PlanFile: "tracks/network/step1_vpc/regional-us-east-2/vpcregionalus-east-2tfplan"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfg := config.Config{Project: "project", PrimaryRegion: "us-east-1", DryRun: true}
+
+	// act
+	err := tracks.WritePlanBundle(stubFs, "out/bundle.tar.gz", cfg, stage)
+	require.NoError(t, err)
+
+	manifest, err := tracks.ExtractPlanBundle(stubFs, "out/bundle.tar.gz", "extracted")
+	require.NoError(t, err)
+
+	// assert
+	require.Equal(t, []tracks.BundledPlan{
+		{StepID: "#project#network#vpc", Track: "network", Step: "vpc", RegionDeployType: "primary", Region: "us-east-1", File: "plans/network/vpc/primary-us-east-1.tfplan"},
+		{StepID: "#project#network#vpc", Track: "network", Step: "vpc", RegionDeployType: "regional", Region: "us-east-2", File: "plans/network/vpc/regional-us-east-2.tfplan"},
+	}, manifest.Plans, "Only steps that planned should be bundled")
+
+	for file, expected := range map[string]string{
+		steps.BundledPlanName("network", "vpc", config.PrimaryRegionDeployType, "us-east-1"):  "primary plan",
+		steps.BundledPlanName("network", "vpc", config.RegionalRegionDeployType, "us-east-2"): "regional plan",
+	} {
+		plan, err := afero.ReadFile(stubFs, filepath.Join("extracted", file))
+		require.NoError(t, err, "Bundled plans should be extracted where executions apply them from")
+		require.Equal(t, expected, string(plan))
+	}
+
+	var bundledConfig config.Config
+	b, err := afero.ReadFile(stubFs, filepath.Join("extracted", tracks.PlanBundleConfigFile))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &bundledConfig))
+	require.Equal(t, cfg.Project, bundledConfig.Project)
+	require.Equal(t, cfg.PrimaryRegion, bundledConfig.PrimaryRegion)
+}
+
+func TestExtractPlanBundle_ShouldRejectEntriesOutsideOfTheBundle(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+
+	f, err := stubFs.Create("bundle.tar.gz")
+	require.NoError(t, err)
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../escaped.tfplan", Mode: 0644, Size: 4}))
+	_, err = tw.Write([]byte("plan"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	require.NoError(t, f.Close())
+
+	// act
+	_, err = tracks.ExtractPlanBundle(stubFs, "bundle.tar.gz", "extracted/bundle")
+
+	// assert
+	require.Error(t, err)
+
+	exists, _ := afero.Exists(stubFs, "extracted/escaped.tfplan")
+	require.False(t, exists, "Entries outside of the bundle should not be extracted")
+}
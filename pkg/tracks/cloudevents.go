@@ -0,0 +1,142 @@
+package tracks
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+const (
+	CloudEventsSpecVersion        = "1.0"
+	CloudEventsContentType        = "application/cloudevents+json"
+	DeploymentCompletedEventType  = "com.optum.runiac.deployment.completed"
+	deploymentCompletedDataFormat = "application/json"
+)
+
+var cloudEventsClient = &http.Client{Timeout: 10 * time.Second}
+
+// CloudEvent is a structured mode CloudEvents v1.0 event, see https://github.com/cloudevents/spec
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            DeploymentEvent `json:"data"`
+}
+
+// DeploymentEvent describes the overall result of a deployment
+type DeploymentEvent struct {
+	RunID            string `json:"runId"`
+	Project          string `json:"project"`
+	Environment      string `json:"environment"`
+	AccountID        string `json:"accountId"`
+	Result           string `json:"result"` // success or fail
+	TrackCount       int    `json:"trackCount"`
+	SkippedTracks    int    `json:"skippedTracks"`
+	FailedTracks     int    `json:"failedTracks"` // Tracks failing as a whole, e.g. fewer regions than min_successful_regions succeeded
+	ExecutedSteps    int    `json:"executedSteps"`
+	FailedSteps      int    `json:"failedSteps"`
+	SkippedSteps     int    `json:"skippedSteps"`
+	CancelledSteps   int    `json:"cancelledSteps"`
+	FailedTests      int    `json:"failedTests"`
+	RateLimitedSteps int    `json:"rateLimitedSteps"`
+	FailedDestroys   int    `json:"failedDestroys"`
+	DurationSeconds  int64  `json:"durationSeconds"`
+}
+
+// CloudEvent builds the deployment completed event for the stage
+func (s Stage) CloudEvent(cfg config.Config) CloudEvent {
+	data := DeploymentEvent{
+		RunID:           cfg.UniqueExternalExecutionID,
+		Project:         cfg.Project,
+		Environment:     cfg.Environment,
+		AccountID:       cfg.AccountID,
+		TrackCount:      len(s.Tracks),
+		DurationSeconds: int64(s.Duration.Seconds()),
+	}
+
+	for _, t := range s.Tracks {
+		if t.Skipped {
+			data.SkippedTracks++
+		}
+
+		if t.Output.Err != nil {
+			data.FailedTracks++
+		}
+
+		for _, exec := range t.Output.Executions {
+			data.ExecutedSteps += exec.Output.ExecutedCount
+			data.FailedTests += exec.Output.FailedTestCount
+			data.RateLimitedSteps += exec.Output.RateLimitedCount
+
+			for _, step := range exec.Output.Steps {
+				switch step.Output.Status {
+				case config.Fail:
+					data.FailedSteps++
+				case config.Skipped:
+					data.SkippedSteps++
+				case config.Cancelled:
+					data.CancelledSteps++
+				}
+			}
+		}
+
+		for _, exec := range t.DestroyOutput.Executions {
+			data.FailedDestroys += len(exec.Output.FailedSteps)
+		}
+	}
+
+	data.Result = "success"
+	if s.Err != nil || data.FailedTracks > 0 || data.FailedSteps > 0 || data.SkippedSteps > 0 || data.CancelledSteps > 0 || data.FailedDestroys > 0 {
+		data.Result = "fail"
+	}
+
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              newEventID(),
+		Source:          fmt.Sprintf("runiac/%s", cfg.Project),
+		Type:            DeploymentCompletedEventType,
+		Subject:         cfg.UniqueExternalExecutionID,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: deploymentCompletedDataFormat,
+		Data:            data,
+	}
+}
+
+// EmitCloudEvent posts the event to the sink in structured content mode
+func EmitCloudEvent(sink string, event CloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := cloudEventsClient.Post(sink, CloudEventsContentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("cloud events sink %s responded with %s", sink, resp.Status)
+	}
+
+	return nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,96 @@
+package tracks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitCloudEvent_ShouldPostStructuredDeploymentCompletedEvent(t *testing.T) {
+	// arrange
+	var contentType string
+	var received map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	stage := stubStageWithDurations()
+	stage.Tracks["track-b"].Output.Executions[0].Output.Steps["b1"] = config.Step{Name: "b1", Output: config.StepOutput{Status: config.Fail}}
+
+	cfg := config.Config{
+		UniqueExternalExecutionID: "run-123",
+		Project:                   "runiac",
+		Environment:               "nonprod",
+	}
+
+	// act
+	err := tracks.EmitCloudEvent(server.URL, stage.CloudEvent(cfg))
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, tracks.CloudEventsContentType, contentType)
+
+	// required context attributes
+	require.Equal(t, "1.0", received["specversion"])
+	require.NotEmpty(t, received["id"])
+	require.Equal(t, "runiac/runiac", received["source"])
+	require.Equal(t, tracks.DeploymentCompletedEventType, received["type"])
+	require.Equal(t, "application/json", received["datacontenttype"])
+	require.NotEmpty(t, received["time"])
+
+	data := received["data"].(map[string]interface{})
+	require.Equal(t, "run-123", data["runId"])
+	require.Equal(t, "fail", data["result"])
+	require.Equal(t, float64(2), data["trackCount"])
+	require.Equal(t, float64(1), data["failedSteps"])
+	require.Equal(t, float64(6), data["durationSeconds"])
+}
+
+func TestCloudEvent_ShouldReportAbortedDeploymentAsFailed(t *testing.T) {
+	// act
+	event := tracks.Stage{Err: errors.New("before all command failed")}.CloudEvent(config.Config{})
+
+	// assert
+	require.Equal(t, "fail", event.Data.Result)
+}
+
+func TestCloudEvent_ShouldReportFailedTrackAsFailed(t *testing.T) {
+	// arrange
+	stage := stubStageWithDurations()
+	track := stage.Tracks["track-a"]
+	track.Output.Err = errors.New("1 of 2 regional regions succeeded, track requires at least 2")
+	stage.Tracks["track-a"] = track
+
+	// act
+	event := stage.CloudEvent(config.Config{})
+
+	// assert
+	require.Equal(t, "fail", event.Data.Result, "Tracks failing as a whole should fail the deployment like its summary")
+	require.Equal(t, 1, event.Data.FailedTracks)
+}
+
+func TestEmitCloudEvent_ShouldErrorWhenSinkRejectsEvent(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	// act
+	err := tracks.EmitCloudEvent(server.URL, tracks.Stage{}.CloudEvent(config.Config{}))
+
+	// assert
+	require.Error(t, err)
+}
@@ -0,0 +1,169 @@
+package tracks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// DependencyGraph returns the steps each deployed step consumed output variables from, as determined by the
+// variables the step referenced rather than the variables available to it. K={track}/{step}, V=[{track}/{step}]
+func (s Stage) DependencyGraph() map[string][]string {
+	dependencies := map[string]map[string]bool{}
+
+	for _, t := range s.Tracks {
+		for _, exec := range t.Output.Executions {
+			for _, step := range exec.Output.Steps {
+				for upstream := range step.Output.ConsumedVariables {
+					key := fmt.Sprintf("%s/%s", t.Name, step.Name)
+					if dependencies[key] == nil {
+						dependencies[key] = map[string]bool{}
+					}
+
+					dependencies[key][upstreamStep(t.Name, upstream)] = true
+				}
+			}
+		}
+	}
+
+	graph := map[string][]string{}
+	for key, upstreams := range dependencies {
+		for upstream := range upstreams {
+			graph[key] = append(graph[key], upstream)
+		}
+
+		sort.Strings(graph[key])
+	}
+
+	return graph
+}
+
+// upstreamStep converts a step output variables key, e.g. pretrack-{step}, {step}-regional or regional-{step}, to {track}/{step}
+func upstreamStep(trackName string, key string) string {
+	if strings.HasPrefix(key, "pretrack-") {
+		trackName = PRE_TRACK_NAME
+		key = strings.TrimPrefix(key, "pretrack-")
+	}
+
+	key = strings.TrimSuffix(key, fmt.Sprintf("-%s", config.RegionalRegionDeployType.String()))
+	key = strings.TrimPrefix(key, fmt.Sprintf("%s-", config.RegionalRegionDeployType.String()))
+
+	return fmt.Sprintf("%s/%s", trackName, key)
+}
+
+// dependencyCycle returns a cycle in the tracks' depends_on, e.g. [a b a], or nil when the dependencies are acyclic.
+// Dependencies on tracks that are not executed are ignored
+func dependencyCycle(tracks []Track) []string {
+	dependsOn := map[string][]string{}
+	for _, t := range tracks {
+		dependsOn[t.Name] = t.Config.DependsOn
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dependency := range dependsOn[name] {
+			if _, ok := dependsOn[dependency]; !ok {
+				continue
+			}
+
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, t := range sortedTracks(tracks) {
+		if cycle := visit(t.Name); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// groupTracksByDependencies splits a stage into waves executed in order, each track executing in the wave after the
+// last of the stage's tracks it depends on. Dependencies on tracks outside the stage are ordered by the stages instead
+func groupTracksByDependencies(stage trackStage) (waves []trackStage) {
+	inStage := map[string]bool{}
+	for _, t := range stage.Tracks {
+		inStage[t.Name] = true
+	}
+
+	executed := map[string]bool{}
+	remaining := stage.Tracks
+
+	for len(remaining) > 0 {
+		wave := trackStage{Name: stage.Name}
+		var blocked []Track
+
+		for _, t := range remaining {
+			ready := true
+			for _, dependency := range t.Config.DependsOn {
+				if inStage[dependency] && !executed[dependency] {
+					ready = false
+				}
+			}
+
+			if ready {
+				wave.Tracks = append(wave.Tracks, t)
+			} else {
+				blocked = append(blocked, t)
+			}
+		}
+
+		// cycles are rejected before executing, guard against looping forever regardless
+		if len(wave.Tracks) == 0 {
+			wave.Tracks = blocked
+			blocked = nil
+		}
+
+		for _, t := range wave.Tracks {
+			executed[t.Name] = true
+		}
+
+		waves = append(waves, wave)
+		remaining = blocked
+	}
+
+	return waves
+}
+
+// failedDependencies returns the tracks t depends on that failed or were skipped
+func failedDependencies(output Stage, t Track) (failed []string) {
+	for _, dependency := range t.Config.DependsOn {
+		if d, ok := output.Tracks[dependency]; ok && (d.Skipped || hasFailedSteps(d.Output)) {
+			failed = append(failed, dependency)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,55 @@
+package tracks_test
+
+import (
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageDependencyGraph_ShouldOnlyIncludeConsumedStepOutputs(t *testing.T) {
+	// arrange
+	stepConsuming := func(name string, consumed map[string][]string) config.Step {
+		return config.Step{Name: name, Output: config.StepOutput{Status: config.Success, ConsumedVariables: consumed}}
+	}
+
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc":     stepConsuming("vpc", map[string][]string{"pretrack-project": {"project_id"}, "pretrack-regional-kms": {"key_arn"}}),
+									"subnets": stepConsuming("subnets", map[string][]string{"vpc": {"vpc_id"}}),
+									"dns":     stepConsuming("dns", map[string][]string{}),
+								},
+							},
+						},
+						{
+							RegionDeployType: config.RegionalRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"subnets": stepConsuming("subnets", map[string][]string{"vpc-regional": {"vpc_id"}, "dns": {"zone_id"}, "regional-flowlogs": {"bucket_arn"}}),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	graph := stage.DependencyGraph()
+
+	// assert
+	require.Equal(t, map[string][]string{
+		"network/vpc":     {"_pretrack/kms", "_pretrack/project"},
+		"network/subnets": {"network/dns", "network/flowlogs", "network/vpc"},
+	}, graph)
+}
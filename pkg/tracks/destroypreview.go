@@ -0,0 +1,78 @@
+package tracks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// StepDeletions are the resources a step execution's destroy plan deletes
+type StepDeletions struct {
+	TrackName        string
+	StepName         string
+	RegionDeployType config.RegionDeployType
+	Region           string
+	Resources        []string
+}
+
+func (s StepDeletions) String() string {
+	return fmt.Sprintf("%v/%v/%v/%v (%s)", s.TrackName, s.StepName, s.RegionDeployType, s.Region, strings.Join(s.Resources, ", "))
+}
+
+// DestroyPreview summarizes what destroying every track would delete, without deleting anything
+type DestroyPreview struct {
+	Steps         []StepDeletions // The step executions deleting resources, ordered by track, step, region deploy type and region
+	ResourceCount int             // The total number of resources deleted across all step executions
+}
+
+func (p DestroyPreview) String() string {
+	steps := make([]string, 0, len(p.Steps))
+	for _, s := range p.Steps {
+		steps = append(steps, s.String())
+	}
+
+	return fmt.Sprintf("Destroy would delete %d resource(s) across %d step execution(s): %s.", p.ResourceCount, len(p.Steps), strings.Join(steps, "; "))
+}
+
+// DestroyPreview aggregates the planned deletions of the stage's destroy executions
+func (s Stage) DestroyPreview() (preview DestroyPreview) {
+	for _, t := range s.Tracks {
+		for _, exec := range t.DestroyOutput.Executions {
+			for _, step := range exec.Output.Steps {
+				if len(step.Output.PlannedDeletions) == 0 {
+					continue
+				}
+
+				resources := append([]string{}, step.Output.PlannedDeletions...)
+				sort.Strings(resources)
+
+				preview.Steps = append(preview.Steps, StepDeletions{
+					TrackName:        t.Name,
+					StepName:         step.Name,
+					RegionDeployType: exec.RegionDeployType,
+					Region:           exec.Region,
+					Resources:        resources,
+				})
+				preview.ResourceCount += len(resources)
+			}
+		}
+	}
+
+	sort.Slice(preview.Steps, func(i, j int) bool {
+		a, b := preview.Steps[i], preview.Steps[j]
+		if a.TrackName != b.TrackName {
+			return a.TrackName < b.TrackName
+		}
+		if a.StepName != b.StepName {
+			return a.StepName < b.StepName
+		}
+		if a.RegionDeployType != b.RegionDeployType {
+			return a.RegionDeployType < b.RegionDeployType
+		}
+		return a.Region < b.Region
+	})
+
+	return
+}
@@ -0,0 +1,72 @@
+package tracks_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTracks_ShouldPreviewDestroyWithoutDeletingAnything(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/app/step1_service/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/app/step2_dns/main.tf", []byte(""), 0644)
+
+	var mutex sync.Mutex
+	var appliedSteps []string
+	var destroyPlannedSteps []string
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		id := fmt.Sprintf("%s/%s/%s/%s", s.TrackName, s.Name, regionDeployType, region)
+
+		mutex.Lock()
+		if !s.DeployConfig.DryRun {
+			appliedSteps = append(appliedSteps, id)
+		}
+		if destroy {
+			destroyPlannedSteps = append(destroyPlannedSteps, id)
+		}
+		mutex.Unlock()
+
+		s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, RegionDeployType: regionDeployType, Region: region}
+
+		// the service step has nothing left to delete
+		if destroy && s.Name != "service" {
+			s.Output.PlannedDeletions = []string{fmt.Sprintf("aws_%s.b_%s", s.Name, region), fmt.Sprintf("aws_%s.a_%s", s.Name, region)}
+		}
+
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	// act
+	stage := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:       true,
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2"},
+		DestroyPreview:  true,
+	})
+
+	preview := stage.DestroyPreview()
+
+	// assert
+	require.Empty(t, appliedSteps, "Steps should only be planned during a destroy preview")
+	require.ElementsMatch(t, []string{"network/vpc/regional/us-east-2", "network/vpc/primary/us-east-1", "app/service/primary/us-east-1", "app/dns/primary/us-east-1"}, destroyPlannedSteps, "Every track should be destroy planned")
+
+	require.Equal(t, 6, preview.ResourceCount)
+	require.Equal(t, []tracks.StepDeletions{
+		{TrackName: "app", StepName: "dns", RegionDeployType: config.PrimaryRegionDeployType, Region: "us-east-1", Resources: []string{"aws_dns.a_us-east-1", "aws_dns.b_us-east-1"}},
+		{TrackName: "network", StepName: "vpc", RegionDeployType: config.PrimaryRegionDeployType, Region: "us-east-1", Resources: []string{"aws_vpc.a_us-east-1", "aws_vpc.b_us-east-1"}},
+		{TrackName: "network", StepName: "vpc", RegionDeployType: config.RegionalRegionDeployType, Region: "us-east-2", Resources: []string{"aws_vpc.a_us-east-2", "aws_vpc.b_us-east-2"}},
+	}, preview.Steps)
+}
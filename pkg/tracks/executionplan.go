@@ -0,0 +1,237 @@
+package tracks
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/spf13/afero"
+)
+
+// Targeting decisions describe why a track's steps were included in the execution plan
+const (
+	// TargetedByAll tracks are included as every track is targeted
+	TargetedByAll = "all"
+	// TargetedByWhitelist tracks are included as some of their steps are in the step whitelist
+	TargetedByWhitelist = "whitelist"
+	// TargetedByAlwaysRun tracks are included in full as they are configured to always run
+	TargetedByAlwaysRun = "always_run"
+	// TargetedByDependency tracks are included in full as a targeted track depends on them
+	TargetedByDependency = "dependency"
+)
+
+// ExecutionPlan is the fully resolved plan of what a deployment executes, before anything is executed
+type ExecutionPlan struct {
+	PrimaryRegion   string         `json:"primaryRegion"`
+	RegionalRegions []string       `json:"regionalRegions"`
+	TargetAll       bool           `json:"targetAll"`
+	StepWhitelist   []string       `json:"stepWhitelist,omitempty"`
+	Tracks          []PlannedTrack `json:"tracks"` // In execution order, tracks sharing a group are executed in parallel
+}
+
+// PlannedTrack is a track of the execution plan
+type PlannedTrack struct {
+	Name                  string               `json:"name"`
+	Group                 int                  `json:"group"`           // The order the track is executed in, starting at 0
+	Stage                 string               `json:"stage,omitempty"` // The stage, or the track order entry, the track is executed in
+	PreTrack              bool                 `json:"preTrack,omitempty"`
+	PostTrack             bool                 `json:"postTrack,omitempty"`
+	DependsOn             []string             `json:"dependsOn,omitempty"`
+	TargetedBy            string               `json:"targetedBy"` // Why the track's steps are included, one of the TargetedBy values
+	Skipped               bool                 `json:"skipped,omitempty"`
+	SkipReason            SkipReason           `json:"skipReason,omitempty"`
+	PrimaryRegion         string               `json:"primaryRegion"`
+	RegionalRegions       []string             `json:"regionalRegions,omitempty"`       // Empty when the track is not deployed regionally
+	RegionalRegionsOutput string               `json:"regionalRegionsOutput,omitempty"` // Set when the regional regions are read from a primary step output instead
+	Progressions          []PlannedProgression `json:"progressions"`
+}
+
+// PlannedProgression is the steps of a track executed in parallel at a progression level
+type PlannedProgression struct {
+	Level int           `json:"level"`
+	Steps []PlannedStep `json:"steps"`
+}
+
+// PlannedStep is a step of the execution plan
+type PlannedStep struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Primary       bool   `json:"primary"`
+	Regional      bool   `json:"regional"`
+	Tests         bool   `json:"tests"`
+	RegionalTests bool   `json:"regionalTests"`
+}
+
+// NewExecutionPlan resolves the order the gathered tracks are executed in, mirroring ExecuteTracks
+func NewExecutionPlan(cfg config.Config, tracks []Track) ExecutionPlan {
+	plan := ExecutionPlan{
+		PrimaryRegion:   cfg.PrimaryRegion,
+		RegionalRegions: cfg.RegionalRegions,
+		TargetAll:       cfg.TargetAll,
+		StepWhitelist:   cfg.StepWhitelist,
+		Tracks:          []PlannedTrack{},
+	}
+
+	var preTrack, postTrack *Track
+	var parallelTracks []Track
+	for i := range tracks {
+		if tracks[i].IsPreTrack {
+			preTrack = &tracks[i]
+		} else if tracks[i].IsPostTrack {
+			postTrack = &tracks[i]
+		} else {
+			parallelTracks = append(parallelTracks, tracks[i])
+		}
+	}
+
+	group := 0
+	if preTrack != nil {
+		var independentTracks []Track
+		parallelTracks, independentTracks = splitPreTrackIndependentTracks(parallelTracks)
+
+		plan.Tracks = append(plan.Tracks, newPlannedTrack(cfg, *preTrack, group, ""))
+		for _, t := range sortedTracks(independentTracks) {
+			plan.Tracks = append(plan.Tracks, newPlannedTrack(cfg, t, group, ""))
+		}
+		group++
+	}
+
+	trackStages := groupTracksByStage(cfg, parallelTracks)
+
+	var excluded []Track
+	if len(cfg.TrackOrder) > 0 {
+		trackStages, excluded = groupTracksByOrder(cfg, parallelTracks)
+	}
+
+	for _, stage := range trackStages {
+		for _, wave := range groupTracksByDependencies(stage) {
+			for _, t := range sortedTracks(wave.Tracks) {
+				plan.Tracks = append(plan.Tracks, newPlannedTrack(cfg, t, group, wave.Name))
+			}
+			group++
+		}
+	}
+
+	if postTrack != nil {
+		plan.Tracks = append(plan.Tracks, newPlannedTrack(cfg, *postTrack, group, ""))
+		group++
+	}
+
+	for _, t := range sortedTracks(excluded) {
+		planned := newPlannedTrack(cfg, t, group, "")
+		planned.Skipped = true
+		planned.SkipReason = SkipReasonNotInTrackOrder
+		plan.Tracks = append(plan.Tracks, planned)
+	}
+
+	return plan
+}
+
+// newPlannedTrack resolves the regions and steps of a track executed in group
+func newPlannedTrack(cfg config.Config, t Track, group int, stage string) PlannedTrack {
+	planned := PlannedTrack{
+		Name:          t.Name,
+		Group:         group,
+		Stage:         stage,
+		PreTrack:      t.IsPreTrack,
+		PostTrack:     t.IsPostTrack,
+		DependsOn:     t.Config.DependsOn,
+		TargetedBy:    targetedBy(cfg, t),
+		PrimaryRegion: t.primaryRegion(cfg),
+		Progressions:  []PlannedProgression{},
+	}
+
+	if t.RegionalDeployment && t.Config.IncludesRegionDeployType(config.RegionalRegionDeployType) {
+		planned.RegionalRegions = t.regionalRegions(cfg)
+		planned.RegionalRegionsOutput = t.Config.RegionalRegionsOutput
+
+		if cfg.AdHocRegion != "" {
+			planned.RegionalRegions = []string{cfg.AdHocRegion}
+			planned.RegionalRegionsOutput = ""
+		}
+	}
+
+	for level := 1; level <= t.StepProgressionsCount; level++ {
+		if len(t.OrderedSteps[level]) == 0 {
+			continue
+		}
+
+		progression := PlannedProgression{Level: level}
+		for _, s := range t.OrderedSteps[level] {
+			progression.Steps = append(progression.Steps, PlannedStep{
+				ID:            s.ID,
+				Name:          s.Name,
+				Primary:       !s.RegionalOnly,
+				Regional:      s.RegionalResourcesExist,
+				Tests:         s.TestsExist,
+				RegionalTests: s.RegionalTestsExist,
+			})
+		}
+
+		sort.Slice(progression.Steps, func(i, j int) bool {
+			return progression.Steps[i].Name < progression.Steps[j].Name
+		})
+
+		planned.Progressions = append(planned.Progressions, progression)
+	}
+
+	return planned
+}
+
+// targetedBy determines why the track's steps were gathered
+func targetedBy(cfg config.Config, t Track) string {
+	if cfg.TargetAll {
+		return TargetedByAll
+	}
+
+	if t.Config.AlwaysRun {
+		return TargetedByAlwaysRun
+	}
+
+	// step ids are #{project}#{track}#{step}
+	for _, stepID := range cfg.StepWhitelist {
+		if names := config.ParseStepID(cfg.IDDelimiter(), stepID); len(names) == 3 && matchesWhitelistedTrack(names[1], t.Name) {
+			return TargetedByWhitelist
+		} else if len(names) == 2 && t.IsDefaultTrack {
+			return TargetedByWhitelist
+		}
+	}
+
+	// the track names of regular expression entries cannot be parsed, match the track's steps against them instead
+	for _, steps := range t.OrderedSteps {
+		for _, s := range steps {
+			if isWhitelisted(cfg.StepWhitelist, s.ID) {
+				return TargetedByWhitelist
+			}
+		}
+	}
+
+	return TargetedByDependency
+}
+
+// sortedTracks orders tracks executed in parallel by name
+func sortedTracks(tracks []Track) []Track {
+	sorted := append([]Track{}, tracks...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
+
+// WriteExecutionPlan writes the execution plan as JSON to path
+func WriteExecutionPlan(fs afero.Fs, path string, plan ExecutionPlan) error {
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return afero.WriteFile(fs, path, b, 0644)
+}
@@ -0,0 +1,136 @@
+package tracks_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTracks_ShouldEmitExecutionPlanBeforeExecuting(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/_pretrack/step1_init/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step2_subnets/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step2_subnets/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step2_routes/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/runiac.yaml", []byte("stage: bootstrap\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/app/step1_service/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/app/step1_service/tests/tests.test", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/app/runiac.yaml", []byte("depends_on:\n  - network\n"), 0644)
+
+	var executed []string
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, track tracks.Track, out chan<- tracks.Output) {
+		_, err := afero.ReadFile(stubFs, "out/plan.json")
+		require.NoError(t, err, "The plan should be emitted before tracks are executed")
+
+		executed = append(executed, track.Name)
+		out <- tracks.Output{Name: track.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	stubTracker.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:       true,
+		Project:         "core",
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2", "us-west-2"},
+		Stages:          []string{"bootstrap"},
+		EmitPlanJSON:    "out/plan.json",
+	})
+
+	// assert
+	b, err := afero.ReadFile(stubFs, "out/plan.json")
+	require.NoError(t, err)
+
+	var plan tracks.ExecutionPlan
+	require.NoError(t, json.Unmarshal(b, &plan))
+
+	require.Equal(t, tracks.ExecutionPlan{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2", "us-west-2"},
+		TargetAll:       true,
+		Tracks: []tracks.PlannedTrack{
+			{
+				Name:          "_pretrack",
+				Group:         0,
+				PreTrack:      true,
+				TargetedBy:    tracks.TargetedByAll,
+				PrimaryRegion: "us-east-1",
+				Progressions: []tracks.PlannedProgression{
+					{Level: 1, Steps: []tracks.PlannedStep{{ID: "#core#_pretrack#init", Name: "init", Primary: true}}},
+				},
+			},
+			{
+				Name:            "network",
+				Group:           1,
+				Stage:           "bootstrap",
+				TargetedBy:      tracks.TargetedByAll,
+				PrimaryRegion:   "us-east-1",
+				RegionalRegions: []string{"us-east-2", "us-west-2"},
+				Progressions: []tracks.PlannedProgression{
+					{Level: 1, Steps: []tracks.PlannedStep{{ID: "#core#network#vpc", Name: "vpc", Primary: true}}},
+					{Level: 2, Steps: []tracks.PlannedStep{
+						{ID: "#core#network#routes", Name: "routes", Primary: true},
+						{ID: "#core#network#subnets", Name: "subnets", Primary: true, Regional: true},
+					}},
+				},
+			},
+			{
+				Name:          "app",
+				Group:         2,
+				DependsOn:     []string{"network"},
+				TargetedBy:    tracks.TargetedByAll,
+				PrimaryRegion: "us-east-1",
+				Progressions: []tracks.PlannedProgression{
+					{Level: 1, Steps: []tracks.PlannedStep{{ID: "#core#app#service", Name: "service", Primary: true, Tests: true}}},
+				},
+			},
+		},
+	}, plan)
+	require.Equal(t, []string{"_pretrack", "network", "app"}, executed, "Tracks should execute in the planned order")
+}
+
+func TestNewExecutionPlan_ShouldRecordTargetingDecisions(t *testing.T) {
+	stubTracks := []tracks.Track{
+		{Name: "app"},
+		{Name: "network"},
+		{Name: "baseline", Config: config.TrackConfig{AlwaysRun: true}},
+		{Name: "unlisted"},
+	}
+
+	// act
+	plan := tracks.NewExecutionPlan(config.Config{
+		StepWhitelist:             []string{"#core#app#service"},
+		TrackOrder:                []string{"network", "app", "baseline"},
+		TrackOrderExcludeUnlisted: true,
+	}, stubTracks)
+
+	// assert
+	targeting := map[string]string{}
+	var order []string
+	for _, planned := range plan.Tracks {
+		targeting[planned.Name] = planned.TargetedBy
+		order = append(order, planned.Name)
+	}
+
+	require.Equal(t, []string{"network", "app", "baseline", "unlisted"}, order)
+	require.Equal(t, map[string]string{
+		"app":      tracks.TargetedByWhitelist,
+		"network":  tracks.TargetedByDependency,
+		"baseline": tracks.TargetedByAlwaysRun,
+		"unlisted": tracks.TargetedByDependency,
+	}, targeting)
+	require.True(t, plan.Tracks[3].Skipped)
+	require.Equal(t, tracks.SkipReasonNotInTrackOrder, plan.Tracks[3].SkipReason)
+}
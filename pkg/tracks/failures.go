@@ -0,0 +1,98 @@
+package tracks
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// Kinds of step failures
+const (
+	DeployFailure  = "deploy"
+	TestFailure    = "test"
+	DestroyFailure = "destroy"
+)
+
+// StepFailure is a step, or a step's tests, that failed in a region
+type StepFailure struct {
+	TrackName        string `json:"track"`
+	StepName         string `json:"step"`
+	StepID           string `json:"stepId"`
+	RegionDeployType string `json:"regionDeployType"`
+	Region           string `json:"region"`
+	Kind             string `json:"kind"` // One of the failure kinds, deploy, test or destroy
+	Err              error  `json:"-"`
+}
+
+func (f StepFailure) String() string {
+	return fmt.Sprintf("%v/%v/%v/%v %s (%v)", f.TrackName, f.StepName, f.RegionDeployType, f.Region, f.Kind, f.Err)
+}
+
+// Errors returns every failed step deploy, step test and step destroy in the stage, ordered by track, step, region deploy
+// type, region and kind. A stage without errors returns an empty slice
+func (s Stage) Errors() []StepFailure {
+	failures := []StepFailure{}
+
+	for _, t := range s.Tracks {
+		for _, exec := range t.Output.Executions {
+			for _, step := range exec.Output.FailedSteps {
+				// steps that deployed but failed their tests are reported as test failures below
+				if step.TestsFailedOnly {
+					continue
+				}
+
+				failures = append(failures, newStepFailure(t, exec, step, DeployFailure, step.Output.Err))
+			}
+
+			for _, step := range exec.Output.Steps {
+				if step.TestOutput.Err != nil {
+					failures = append(failures, newStepFailure(t, exec, step, TestFailure, step.TestOutput.Err))
+				}
+			}
+		}
+
+		for _, exec := range t.DestroyOutput.Executions {
+			for _, step := range exec.Output.FailedSteps {
+				failures = append(failures, newStepFailure(t, exec, step, DestroyFailure, step.Output.Err))
+			}
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		a, b := failures[i], failures[j]
+		if a.TrackName != b.TrackName {
+			return a.TrackName < b.TrackName
+		}
+		if a.StepName != b.StepName {
+			return a.StepName < b.StepName
+		}
+		if a.RegionDeployType != b.RegionDeployType {
+			return a.RegionDeployType < b.RegionDeployType
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.Kind < b.Kind
+	})
+
+	return failures
+}
+
+func newStepFailure(t Track, exec RegionExecution, step config.Step, kind string, err error) StepFailure {
+	// a runner may fail a step without describing why
+	if err == nil {
+		err = errors.New("step failed")
+	}
+
+	return StepFailure{
+		TrackName:        t.Name,
+		StepName:         step.Name,
+		StepID:           step.ID,
+		RegionDeployType: exec.RegionDeployType.String(),
+		Region:           exec.Region,
+		Kind:             kind,
+		Err:              err,
+	}
+}
@@ -0,0 +1,158 @@
+package tracks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageErrors_ShouldAggregateFailedStepsAndTests(t *testing.T) {
+	// arrange
+	failedStep := config.Step{ID: "#core#network#subnets", Name: "subnets", Output: config.StepOutput{Status: config.Fail, Err: errors.New("apply failed")}}
+	failedTests := config.Step{ID: "#core#network#vpc", Name: "vpc", Output: config.StepOutput{Status: config.Success}, TestOutput: config.StepTestOutput{Err: errors.New("tests failed")}}
+	silentlyFailedStep := config.Step{ID: "#core#app#api", Name: "api", Output: config.StepOutput{Status: config.Fail}}
+
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-2",
+							RegionDeployType: config.RegionalRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps:       map[string]config.Step{"subnets": failedStep, "vpc": failedTests},
+								FailedSteps: []config.Step{failedStep},
+							},
+						},
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{"vpc": {Name: "vpc", Output: config.StepOutput{Status: config.Success}}},
+							},
+						},
+					},
+				},
+				DestroyOutput: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								FailedSteps: []config.Step{{ID: "#core#network#vpc", Name: "vpc", Output: config.StepOutput{Err: errors.New("destroy failed")}}},
+							},
+						},
+					},
+				},
+			},
+			"app": {
+				Name: "app",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps:       map[string]config.Step{"api": silentlyFailedStep},
+								FailedSteps: []config.Step{silentlyFailedStep},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	failures := stage.Errors()
+
+	// assert
+	var summary []string
+	for _, f := range failures {
+		summary = append(summary, f.String())
+	}
+	require.Equal(t, []string{
+		"app/api/primary/us-east-1 deploy (step failed)",
+		"network/subnets/regional/us-east-2 deploy (apply failed)",
+		"network/vpc/primary/us-east-1 destroy (destroy failed)",
+		"network/vpc/regional/us-east-2 test (tests failed)",
+	}, summary, "Failures should be ordered by track, step, region deploy type and region")
+
+	require.Equal(t, "#core#network#subnets", failures[1].StepID)
+	require.Equal(t, tracks.DeployFailure, failures[1].Kind)
+	require.EqualError(t, failures[1].Err, "apply failed")
+}
+
+func TestStageErrors_ShouldReportTestOnlyFailedStepsOnceWhenFailedStepsIncludeTests(t *testing.T) {
+	// arrange
+	failedTests := config.Step{
+		ID:              "#core#network#vpc",
+		Name:            "vpc",
+		Output:          config.StepOutput{Status: config.Success},
+		TestOutput:      config.StepTestOutput{Err: errors.New("tests failed")},
+		DeployConfig:    config.Config{FailedStepsIncludeTests: true},
+		TestsFailedOnly: true,
+	}
+
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps:       map[string]config.Step{"vpc": failedTests},
+								FailedSteps: []config.Step{failedTests},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	failures := stage.Errors()
+
+	// assert
+	require.Len(t, failures, 1, "Steps that only failed their tests should not also be reported as failed deploys")
+	require.Equal(t, tracks.TestFailure, failures[0].Kind)
+	require.EqualError(t, failures[0].Err, "tests failed")
+}
+
+func TestStageErrors_ShouldBeEmptyWithoutFailures(t *testing.T) {
+	// arrange
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{"vpc": {Name: "vpc", Output: config.StepOutput{Status: config.Success}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	failures := stage.Errors()
+
+	// assert
+	require.NotNil(t, failures)
+	require.Empty(t, failures)
+}
@@ -0,0 +1,160 @@
+package tracks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// RenderGraph renders the gathered tracks as a Graphviz DOT digraph. Each track is a cluster of its steps grouped by
+// progression level, steps are annotated with the region deploy types they deploy to and whether they have tests. The
+// pretrack feeds into every track not independent of it, the posttrack is fed by every other track and tracks are fed
+// by the tracks they depend on
+func RenderGraph(tracks []Track) (string, error) {
+	sorted := sortedTracks(tracks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return graphTrackOrder(sorted[i]) < graphTrackOrder(sorted[j])
+	})
+
+	names := map[string]bool{}
+	for _, t := range sorted {
+		if names[t.Name] {
+			return "", fmt.Errorf("track %s is gathered more than once", t.Name)
+		}
+		names[t.Name] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph runiac {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, t := range sorted {
+		fmt.Fprintf(&b, "\n  subgraph %s {\n", dotQuote(fmt.Sprintf("cluster_%d", i)))
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(t.Name))
+		fmt.Fprintf(&b, "    %s [label=%s, shape=folder];\n", dotQuote(t.Name), dotQuote(t.Name))
+
+		var previous []config.Step
+		for level := 1; level <= t.StepProgressionsCount; level++ {
+			steps := append([]config.Step{}, t.OrderedSteps[level]...)
+			if len(steps) == 0 {
+				continue
+			}
+
+			sort.Slice(steps, func(i, j int) bool {
+				return steps[i].Name < steps[j].Name
+			})
+
+			fmt.Fprintf(&b, "    subgraph %s {\n", dotQuote(fmt.Sprintf("cluster_%d_%d", i, level)))
+			fmt.Fprintf(&b, "      label=%s;\n", dotQuote(fmt.Sprintf("progression %d", level)))
+			for _, s := range steps {
+				fmt.Fprintf(&b, "      %s [label=%s%s];\n", dotQuote(s.ID), dotQuote(graphStepLabel(s)), graphStepStyle(s))
+			}
+			b.WriteString("    }\n")
+
+			// steps are executed after every step of the previous progression level
+			if previous == nil {
+				for _, s := range steps {
+					fmt.Fprintf(&b, "    %s -> %s;\n", dotQuote(t.Name), dotQuote(s.ID))
+				}
+			} else {
+				for _, p := range previous {
+					for _, s := range steps {
+						fmt.Fprintf(&b, "    %s -> %s;\n", dotQuote(p.ID), dotQuote(s.ID))
+					}
+				}
+			}
+			previous = steps
+		}
+
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("\n")
+	for _, t := range sorted {
+		for _, upstream := range graphUpstreamTracks(sorted, t) {
+			fmt.Fprintf(&b, "  %s -> %s [style=bold];\n", dotQuote(upstream), dotQuote(t.Name))
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String(), nil
+}
+
+// graphTrackOrder orders the pretrack first and the posttrack last
+func graphTrackOrder(t Track) int {
+	switch {
+	case t.IsPreTrack:
+		return 0
+	case t.IsPostTrack:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// graphUpstreamTracks are the names of the tracks feeding into t, ordered by name
+func graphUpstreamTracks(tracks []Track, t Track) (upstream []string) {
+	for _, other := range tracks {
+		switch {
+		case other.Name == t.Name:
+		case other.IsPreTrack && !t.IsPostTrack && !t.Config.IndependentOfPreTrack:
+			upstream = append(upstream, other.Name)
+		case t.IsPostTrack && !other.IsPreTrack:
+			upstream = append(upstream, other.Name)
+		case contains(t.Config.DependsOn, other.Name):
+			upstream = append(upstream, other.Name)
+		}
+	}
+
+	sort.Strings(upstream)
+
+	return
+}
+
+// graphStepLabel is the step's name, followed by the region deploy types it deploys to and whether it has tests
+func graphStepLabel(s config.Step) string {
+	var deployTypes []string
+	if !s.RegionalOnly {
+		deployTypes = append(deployTypes, config.PrimaryRegionDeployType.String())
+	}
+	if s.RegionalResourcesExist {
+		deployTypes = append(deployTypes, config.RegionalRegionDeployType.String())
+	}
+
+	label := fmt.Sprintf("%s\\n%s", s.Name, strings.Join(deployTypes, ", "))
+
+	var tests []string
+	if s.TestsExist {
+		tests = append(tests, config.PrimaryRegionDeployType.String())
+	}
+	if s.RegionalTestsExist {
+		tests = append(tests, config.RegionalRegionDeployType.String())
+	}
+
+	if len(tests) > 0 {
+		label += fmt.Sprintf("\\ntests: %s", strings.Join(tests, ", "))
+	}
+
+	return label
+}
+
+// graphStepStyle distinguishes steps deployed regionally, dashed when only deployed regionally
+func graphStepStyle(s config.Step) string {
+	switch {
+	case s.RegionalOnly:
+		return ", style=dashed"
+	case s.RegionalResourcesExist:
+		return ", peripheries=2"
+	default:
+		return ""
+	}
+}
+
+// dotQuote quotes s as a DOT identifier, preserving escaped line breaks in labels
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
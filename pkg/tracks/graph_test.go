@@ -0,0 +1,74 @@
+package tracks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderGraph_ShouldRenderTracksStepsAndDependencies(t *testing.T) {
+	// arrange
+	gathered := []tracks.Track{
+		{
+			Name:                  "app",
+			StepProgressionsCount: 2,
+			OrderedSteps: map[int][]config.Step{
+				1: {{ID: "#proj#app#db", Name: "db", RegionalOnly: true, RegionalResourcesExist: true}},
+				2: {{ID: "#proj#app#api", Name: "api", TestsExist: true, RegionalResourcesExist: true, RegionalTestsExist: true}},
+			},
+			Config: config.TrackConfig{DependsOn: []string{"network"}},
+		},
+		{
+			Name:                  "network",
+			StepProgressionsCount: 1,
+			OrderedSteps: map[int][]config.Step{
+				1: {{ID: "#proj#network#vpc", Name: "vpc"}},
+			},
+		},
+		{
+			Name:                  "audit",
+			StepProgressionsCount: 1,
+			OrderedSteps: map[int][]config.Step{
+				1: {{ID: "#proj#audit#logs", Name: "logs"}},
+			},
+			Config: config.TrackConfig{IndependentOfPreTrack: true},
+		},
+		{
+			Name:                  tracks.PRE_TRACK_NAME,
+			IsPreTrack:            true,
+			StepProgressionsCount: 1,
+			OrderedSteps: map[int][]config.Step{
+				1: {{ID: "#proj#_pretrack#account", Name: "account"}},
+			},
+		},
+	}
+
+	// act
+	graph, err := tracks.RenderGraph(gathered)
+
+	// assert
+	require.NoError(t, err)
+	require.Contains(t, graph, `"#proj#app#db" [label="db\nregional", style=dashed];`, "Regional only steps should be dashed")
+	require.Contains(t, graph, `"#proj#app#api" [label="api\nprimary, regional\ntests: primary, regional", peripheries=2];`, "Steps should be annotated with their tests")
+	require.Contains(t, graph, `"#proj#network#vpc" [label="vpc\nprimary"];`)
+	require.Contains(t, graph, `label="progression 2";`)
+	require.Contains(t, graph, `"#proj#app#db" -> "#proj#app#api";`, "Steps should follow the steps of the previous progression level")
+
+	require.Contains(t, graph, `"_pretrack" -> "app" [style=bold];`, "The pretrack should feed into the other tracks")
+	require.Contains(t, graph, `"_pretrack" -> "network" [style=bold];`)
+	require.NotContains(t, graph, `"_pretrack" -> "audit"`, "Tracks independent of the pretrack should not be fed by it")
+	require.Contains(t, graph, `"network" -> "app" [style=bold];`, "Tracks should be fed by the tracks they depend on")
+
+	require.Less(t, strings.Index(graph, `label="_pretrack";`), strings.Index(graph, `label="app";`), "The pretrack should be rendered first")
+}
+
+func TestRenderGraph_ShouldErrorWhenTrackIsGatheredMoreThanOnce(t *testing.T) {
+	// act
+	_, err := tracks.RenderGraph([]tracks.Track{{Name: "network"}, {Name: "network"}})
+
+	// assert
+	require.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package tracks
+
+import (
+	"context"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// StepHook runs custom logic, e.g. notifying a chat channel or snapshotting state, before and after each step is deployed
+// or destroyed
+type StepHook interface {
+	// BeforeStep is called before the step is executed, returning an error fails the step without executing it
+	BeforeStep(step config.Step) error
+	// AfterStep is called once the step executed, or failed to initialize, with the step's output
+	AfterStep(step config.Step, out config.StepOutput)
+}
+
+type stepHooksKey struct{}
+
+// WithStepHooks returns a copy of ctx carrying the hooks called around each step executed with it
+func WithStepHooks(ctx context.Context, hooks ...StepHook) context.Context {
+	if len(hooks) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, stepHooksKey{}, append(stepHooks(ctx), hooks...))
+}
+
+// stepHooks are the hooks carried by ctx, in the order they were added
+func stepHooks(ctx context.Context) []StepHook {
+	hooks, _ := ctx.Value(stepHooksKey{}).([]StepHook)
+
+	return hooks[:len(hooks):len(hooks)]
+}
+
+// beforeStep calls each hook's BeforeStep, stopping at the first error
+func beforeStep(hooks []StepHook, s config.Step) error {
+	for _, hook := range hooks {
+		if err := hook.BeforeStep(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// afterStep calls each hook's AfterStep with the step's output
+func afterStep(hooks []StepHook, s config.Step) {
+	for _, hook := range hooks {
+		hook.AfterStep(s, s.Output)
+	}
+}
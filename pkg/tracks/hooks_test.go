@@ -0,0 +1,101 @@
+package tracks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/optum/runiac/mocks"
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingStepHook struct {
+	calls     []string
+	beforeErr error
+}
+
+func (h *recordingStepHook) BeforeStep(step config.Step) error {
+	h.calls = append(h.calls, fmt.Sprintf("before %s", step.Name))
+	return h.beforeErr
+}
+
+func (h *recordingStepHook) AfterStep(step config.Step, out config.StepOutput) {
+	h.calls = append(h.calls, fmt.Sprintf("after %s %s", step.Name, out.Status))
+}
+
+func TestExecuteStepImpl_ShouldCallStepHooksAroundDeployAndDestroy(t *testing.T) {
+	var test = map[string]struct {
+		destroy bool
+	}{
+		"deploy":  {destroy: false},
+		"destroy": {destroy: true},
+	}
+
+	for name, tc := range test {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			first, second := &recordingStepHook{}, &recordingStepHook{}
+
+			stubRunner := mocks.NewMockStepper(ctrl)
+			stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+				require.Equal(t, []string{"before step"}, first.calls, "Hooks should be called before the step executes")
+				return exec, nil
+			})
+			if tc.destroy {
+				stubRunner.EXPECT().ExecuteStepDestroy(gomock.Any()).Return(config.StepOutput{Status: config.Success})
+			} else {
+				stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{Status: config.Success})
+			}
+
+			out := make(chan config.Step, 1)
+
+			// act
+			tracks.ExecuteStepImpl(tracks.WithStepHooks(context.Background(), first, second), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+				Name:   "step",
+				Runner: stubRunner,
+			}, out, tc.destroy)
+
+			s := <-out
+
+			// assert
+			require.Equal(t, config.Success, s.Output.Status)
+			require.Equal(t, []string{"before step", "after step SUCCESS"}, first.calls)
+			require.Equal(t, first.calls, second.calls, "Every hook should be called")
+		})
+	}
+}
+
+func TestExecuteStepImpl_ShouldFailStepWithoutExecutingItWhenBeforeStepHookFails(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failing := &recordingStepHook{beforeErr: errors.New("change freeze")}
+	skipped := &recordingStepHook{}
+
+	// the runner has no expectations, executing the step fails the test
+	stubRunner := mocks.NewMockStepper(ctrl)
+
+	out := make(chan config.Step, 1)
+
+	// act
+	tracks.ExecuteStepImpl(tracks.WithStepHooks(context.Background(), failing, skipped), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:   "step",
+		Runner: stubRunner,
+	}, out, false)
+
+	s := <-out
+
+	// assert
+	require.Equal(t, config.Fail, s.Output.Status)
+	require.EqualError(t, s.Output.Err, "step hook failed before step step: change freeze")
+	require.Equal(t, []string{"before step"}, failing.calls, "After step hooks should not be called for steps that were not executed")
+	require.Empty(t, skipped.calls, "Hooks after the failing hook should not be called")
+}
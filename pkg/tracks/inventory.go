@@ -0,0 +1,55 @@
+package tracks
+
+import (
+	"sort"
+)
+
+// InventoryEntry is a resource and the step execution that manages it
+type InventoryEntry struct {
+	Resource         string `json:"resource"`
+	TrackName        string `json:"track"`
+	StepName         string `json:"step"`
+	StepID           string `json:"stepId"`
+	RegionDeployType string `json:"regionDeployType"`
+	Region           string `json:"region"`
+}
+
+// Inventory returns the resources managed by each deployed step execution in the stage, ordered by track, step,
+// region and resource
+func (s Stage) Inventory() (inventory []InventoryEntry) {
+	for _, t := range s.Tracks {
+		for _, exec := range t.Output.Executions {
+			for _, step := range exec.Output.Steps {
+				for _, resource := range step.Output.Resources {
+					inventory = append(inventory, InventoryEntry{
+						Resource:         resource,
+						TrackName:        t.Name,
+						StepName:         step.Name,
+						StepID:           step.ID,
+						RegionDeployType: exec.RegionDeployType.String(),
+						Region:           exec.Region,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(inventory, func(i, j int) bool {
+		a, b := inventory[i], inventory[j]
+		if a.TrackName != b.TrackName {
+			return a.TrackName < b.TrackName
+		}
+		if a.StepName != b.StepName {
+			return a.StepName < b.StepName
+		}
+		if a.RegionDeployType != b.RegionDeployType {
+			return a.RegionDeployType < b.RegionDeployType
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.Resource < b.Resource
+	})
+
+	return
+}
@@ -0,0 +1,57 @@
+package tracks_test
+
+import (
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageInventory_ShouldAggregateStepResources(t *testing.T) {
+	// arrange
+	stepWithResources := func(name string, resources ...string) config.Step {
+		return config.Step{ID: "#core#network#" + name, Name: name, Output: config.StepOutput{Status: config.Success, Resources: resources}}
+	}
+
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-2",
+							RegionDeployType: config.RegionalRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc": stepWithResources("vpc", "aws_vpc.main"),
+								},
+							},
+						},
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc":   stepWithResources("vpc", "aws_vpc.main", "aws_iam_role.flow_logs"),
+									"empty": stepWithResources("empty"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	inventory := stage.Inventory()
+
+	// assert
+	require.Equal(t, []tracks.InventoryEntry{
+		{Resource: "aws_iam_role.flow_logs", TrackName: "network", StepName: "vpc", StepID: "#core#network#vpc", RegionDeployType: "primary", Region: "us-east-1"},
+		{Resource: "aws_vpc.main", TrackName: "network", StepName: "vpc", StepID: "#core#network#vpc", RegionDeployType: "primary", Region: "us-east-1"},
+		{Resource: "aws_vpc.main", TrackName: "network", StepName: "vpc", StepID: "#core#network#vpc", RegionDeployType: "regional", Region: "us-east-2"},
+	}, inventory)
+}
@@ -0,0 +1,199 @@
+package tracks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
+	"github.com/spf13/afero"
+)
+
+// Manifest records the content of each step as of its last successful apply to each deployment target
+type Manifest struct {
+	Steps map[string]ManifestStep `json:"steps"` // K=ManifestKey
+}
+
+// ManifestStep is the content hash and output variables of a step as of its last successful apply to a deployment
+// target, the account, environment, namespace and region the step was applied to
+type ManifestStep struct {
+	ID               string            `json:"id"`
+	Hash             string            `json:"hash"`
+	LastSuccess      time.Time         `json:"lastSuccess"`
+	AccountID        string            `json:"accountId,omitempty"`
+	Environment      string            `json:"environment,omitempty"`
+	Namespace        string            `json:"namespace,omitempty"`
+	RegionDeployType string            `json:"regionDeployType"`
+	Region           string            `json:"region"`
+	OutputVariables  map[string]string `json:"outputVariables,omitempty"`
+}
+
+// ManifestKey is the key the step's apply to the region in its deployment target is recorded with
+func ManifestKey(s config.Step, regionDeployType config.RegionDeployType, region string) string {
+	return strings.Join([]string{s.ID, s.DeployConfig.AccountID, s.DeployConfig.Environment, s.DeployConfig.Namespace, regionDeployType.String(), region}, "/")
+}
+
+// ReadManifest reads the manifest at path, returning an empty manifest when none has been recorded yet
+func ReadManifest(fs afero.Fs, path string) (Manifest, error) {
+	m := Manifest{Steps: map[string]ManifestStep{}}
+
+	b, err := afero.ReadFile(fs, path)
+	if os.IsNotExist(err) {
+		return m, nil
+	} else if err != nil {
+		return m, err
+	}
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, err
+	}
+
+	if m.Steps == nil {
+		m.Steps = map[string]ManifestStep{}
+	}
+
+	return m, nil
+}
+
+// WriteManifest writes the manifest to path
+func WriteManifest(fs afero.Fs, path string, m Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return afero.WriteFile(fs, path, b, 0644)
+}
+
+// Unchanged returns the output variables of each region execution, K={regionDeployType}-{region}, whose last successful
+// apply in the step's deployment target was of the step's current content
+func (m Manifest) Unchanged(s config.Step) map[string]map[string]string {
+	unchanged := map[string]map[string]string{}
+
+	for _, recorded := range m.Steps {
+		if recorded.ID != s.ID || recorded.Hash != s.ContentHash || recorded.AccountID != s.DeployConfig.AccountID ||
+			recorded.Environment != s.DeployConfig.Environment || recorded.Namespace != s.DeployConfig.Namespace {
+			continue
+		}
+
+		vars := recorded.OutputVariables
+		if vars == nil {
+			vars = map[string]string{}
+		}
+
+		unchanged[fmt.Sprintf("%s-%s", recorded.RegionDeployType, recorded.Region)] = vars
+	}
+
+	return unchanged
+}
+
+// RecordSuccessfulSteps records the content hash and output variables of each step's successful region executions
+func (m Manifest) RecordSuccessfulSteps(stage Stage, at time.Time) {
+	for _, t := range stage.Tracks {
+		if t.Skipped {
+			continue
+		}
+
+		for _, exec := range t.Output.Executions {
+			for _, s := range exec.Output.Steps {
+				if s.Output.Status != config.Success || s.ContentHash == "" {
+					continue
+				}
+
+				vars := map[string]string{}
+				for name, value := range s.Output.OutputVariables {
+					vars[name] = terraform.OutputToString(value)
+				}
+
+				m.Steps[ManifestKey(s, exec.RegionDeployType, exec.Region)] = ManifestStep{
+					ID:               s.ID,
+					Hash:             s.ContentHash,
+					LastSuccess:      at,
+					AccountID:        s.DeployConfig.AccountID,
+					Environment:      s.DeployConfig.Environment,
+					Namespace:        s.DeployConfig.Namespace,
+					RegionDeployType: exec.RegionDeployType.String(),
+					Region:           exec.Region,
+					OutputVariables:  vars,
+				}
+			}
+		}
+	}
+}
+
+// HashStepDir hashes the contents of a step directory, ignoring files generated by executing the step
+func HashStepDir(fs afero.Fs, dir string) (string, error) {
+	h := sha256.New()
+
+	err := afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if isGeneratedStepFile(path, info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := fs.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, _ = io.WriteString(h, filepath.ToSlash(rel))
+		_, _ = h.Write([]byte{0})
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		_, _ = h.Write([]byte{0})
+
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isGeneratedStepFile matches terraform working files, plans and overrides copied from the override directory,
+// all of which are written into step directories during execution
+func isGeneratedStepFile(path string, info os.FileInfo) bool {
+	name := info.Name()
+
+	if !info.IsDir() && filepath.Base(filepath.Dir(path)) != "override" &&
+		(name == "override.tf" || strings.HasSuffix(name, "_override.tf")) {
+		return true
+	}
+
+	return name == ".terraform" ||
+		strings.HasPrefix(name, "terraform.tfstate") ||
+		strings.HasSuffix(name, "tfplan") ||
+		strings.HasSuffix(name, "tfplan.json")
+}
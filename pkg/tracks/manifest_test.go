@@ -0,0 +1,180 @@
+package tracks_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func gatheredStepNames(gathered []tracks.Track) (names []string) {
+	for _, t := range gathered {
+		for _, steps := range t.OrderedSteps {
+			for _, s := range steps {
+				names = append(names, s.Name)
+			}
+		}
+	}
+	return
+}
+
+func TestHashStepDir_ShouldIgnoreFilesGeneratedDuringExecution(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "step1_deploy/main.tf", []byte("resource {}"), 0644)
+	_ = afero.WriteFile(stubFs, "step1_deploy/override/override.tf", []byte("locals {}"), 0644)
+
+	before, err := tracks.HashStepDir(stubFs, "step1_deploy")
+	require.NoError(t, err)
+
+	// act
+	_ = afero.WriteFile(stubFs, "step1_deploy/.terraform/terraform.tfstate", []byte("{}"), 0644)
+	_ = afero.WriteFile(stubFs, "step1_deploy/deployprimaryus-east-1tfplan", []byte("plan"), 0644)
+	_ = afero.WriteFile(stubFs, "step1_deploy/deployprimaryus-east-1tfplan.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(stubFs, "step1_deploy/override.tf", []byte("locals {}"), 0644)
+
+	after, err := tracks.HashStepDir(stubFs, "step1_deploy")
+	require.NoError(t, err)
+
+	// assert
+	require.Equal(t, before, after, "Generated files should not change the step's hash")
+
+	_ = afero.WriteFile(stubFs, "step1_deploy/main.tf", []byte("resource { changed }"), 0644)
+	changed, err := tracks.HashStepDir(stubFs, "step1_deploy")
+	require.NoError(t, err)
+	require.NotEqual(t, before, changed, "Changed content should change the step's hash")
+}
+
+func TestGatherTracks_ShouldRecordUnchangedRegionsOfStepsSinceLastSuccess(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_unchanged/main.tf", []byte("resource {}"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_changed/main.tf", []byte("resource { changed }"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step2_new/main.tf", []byte("resource {}"), 0644)
+
+	unchangedHash, err := tracks.HashStepDir(stubFs, "tracks/track/step1_unchanged")
+	require.NoError(t, err)
+
+	require.NoError(t, tracks.WriteManifest(stubFs, "runiac-manifest.json", tracks.Manifest{
+		Steps: map[string]tracks.ManifestStep{
+			"unchanged-prod": {ID: "#core#track#unchanged", Hash: unchangedHash, AccountID: "1", Environment: "prod", RegionDeployType: "primary", Region: "us-east-1", OutputVariables: map[string]string{"bucket": "logs"}},
+			"unchanged-dev":  {ID: "#core#track#unchanged", Hash: unchangedHash, AccountID: "1", Environment: "dev", RegionDeployType: "regional", Region: "us-east-2"},
+			"unchanged-acct": {ID: "#core#track#unchanged", Hash: unchangedHash, AccountID: "2", Environment: "prod", RegionDeployType: "regional", Region: "us-west-2"},
+			"changed-prod":   {ID: "#core#track#changed", Hash: "stale", AccountID: "1", Environment: "prod", RegionDeployType: "primary", Region: "us-east-1"},
+		},
+	}))
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll:        true,
+		Project:          "core",
+		AccountID:        "1",
+		Environment:      "prod",
+		SinceLastSuccess: true,
+		ManifestFile:     "runiac-manifest.json",
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.ElementsMatch(t, []string{"unchanged", "changed", "new"}, gatheredStepNames(mockTracks), "Unchanged steps should be gathered to pass on their outputs")
+
+	for _, s := range append(mockTracks[0].OrderedSteps[1], mockTracks[0].OrderedSteps[2]...) {
+		switch s.Name {
+		case "unchanged":
+			require.Equal(t, map[string]map[string]string{"primary-us-east-1": {"bucket": "logs"}}, s.UnchangedOutputs, "Only the regions of the step's deployment target should be unchanged")
+		default:
+			require.Empty(t, s.UnchangedOutputs, "Changed or unrecorded steps should be executed in every region")
+		}
+	}
+}
+
+func TestExecuteTracks_ShouldRecordSuccessfulStepsSinceLastSuccess(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_succeeds/main.tf", []byte("resource {}"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_fails/main.tf", []byte("resource {}"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step2_consumer/main.tf", []byte("resource {}"), 0644)
+
+	var mutex sync.Mutex
+	var executed []string
+	var consumed map[string]map[string]string
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		executed = append(executed, s.Name)
+		if s.Name == "consumer" {
+			consumed = defaultStepOutputVariables
+		}
+		mutex.Unlock()
+
+		s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, RegionDeployType: regionDeployType, Region: region}
+		switch s.Name {
+		case "fails":
+			s.Output.Status = config.Fail
+		case "succeeds":
+			s.Output.OutputVariables = map[string]interface{}{"bucket": "logs"}
+		}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	cfg := config.Config{
+		TargetAll:        true,
+		Project:          "core",
+		AccountID:        "1",
+		PrimaryRegion:    "us-east-1",
+		SinceLastSuccess: true,
+		ManifestFile:     "state/runiac-manifest.json",
+		Environment:      "prod",
+		ContinueOnError:  true,
+	}
+
+	// act
+	start := time.Now()
+	stubTracker.ExecuteTracks(context.Background(), cfg)
+
+	// assert
+	manifest, err := tracks.ReadManifest(stubFs, cfg.ManifestFile)
+	require.NoError(t, err)
+
+	succeeds := tracks.ManifestKey(config.Step{ID: "#core#track#succeeds", DeployConfig: cfg}, config.PrimaryRegionDeployType, "us-east-1")
+	require.Contains(t, manifest.Steps, succeeds, "Successful step should be recorded")
+	require.NotContains(t, manifest.Steps, tracks.ManifestKey(config.Step{ID: "#core#track#fails", DeployConfig: cfg}, config.PrimaryRegionDeployType, "us-east-1"), "Failed step should not be recorded")
+	require.False(t, manifest.Steps[succeeds].LastSuccess.Before(start))
+	require.Equal(t, "prod", manifest.Steps[succeeds].Environment, "Recorded steps should be tagged with the environment")
+	require.Equal(t, map[string]string{"bucket": "logs"}, manifest.Steps[succeeds].OutputVariables, "Recorded steps should record their output variables")
+
+	// act
+	executed = nil
+	_ = afero.WriteFile(stubFs, "tracks/track/step2_consumer/main.tf", []byte("resource { changed }"), 0644)
+	stubTracker.ExecuteTracks(context.Background(), cfg)
+
+	// assert
+	require.ElementsMatch(t, []string{"fails", "consumer"}, executed, "Recorded steps should be skipped on the next run while changed steps run again")
+	require.Equal(t, "logs", consumed["succeeds"]["bucket"], "Changed steps should receive the recorded outputs of skipped steps")
+
+	// act
+	executed = nil
+	cfg.Environment = "dev"
+	stubTracker.ExecuteTracks(context.Background(), cfg)
+
+	// assert
+	require.ElementsMatch(t, []string{"succeeds", "fails", "consumer"}, executed, "Steps applied to a different deployment target should be executed")
+}
@@ -0,0 +1,52 @@
+package tracks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	plugins_mock "github.com/optum/runiac/plugins/mock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTracks_ShouldCompleteStepsWithFixtureOutputsWhenMockingProvider(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/network/step2_subnets/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "fixtures.json", []byte(`{
+		"network": {
+			"vpc": {"vpc_id": "vpc-123", "cidrs": ["10.0.0.0/16"]},
+			"subnets": {"subnet_ids": ["subnet-1", "subnet-2"]}
+		}
+	}`), 0644)
+
+	// act
+	stage := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:        true,
+		PrimaryRegion:    "us-east-1",
+		MockProvider:     true,
+		MockFixturesFile: "fixtures.json",
+	})
+
+	// assert
+	require.NoError(t, stage.Err)
+
+	track := stage.Tracks["network"]
+	for _, progression := range track.OrderedSteps {
+		for _, s := range progression {
+			require.IsType(t, plugins_mock.MockStepper{}, s.Runner, "Steps should be simulated rather than calling terraform")
+		}
+	}
+
+	require.Len(t, track.Output.Executions, 1)
+	exec := track.Output.Executions[0].Output
+	require.Equal(t, 2, exec.ExecutedCount)
+	require.Equal(t, 0, exec.FailureCount)
+	require.Equal(t, config.Success, exec.Steps["vpc"].Output.Status)
+	require.Equal(t, config.Success, exec.Steps["subnets"].Output.Status)
+	require.Equal(t, "vpc-123", exec.StepOutputVariables["vpc"]["vpc_id"], "Fixture outputs should be passed to later steps")
+	require.Equal(t, `["subnet-1","subnet-2"]`, exec.StepOutputVariables["subnets"]["subnet_ids"])
+}
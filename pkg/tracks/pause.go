@@ -0,0 +1,72 @@
+package tracks
+
+import (
+	"context"
+	"sync"
+)
+
+// PauseController gates the start of new tracks and steps, e.g. during a maintenance window. Tracks and steps already
+// executing continue while paused
+type PauseController interface {
+	// WaitUntilResumed blocks while the deployment is paused, returning ctx's error if it is cancelled first
+	WaitUntilResumed(ctx context.Context) error
+}
+
+// DeploymentPause is checked before each track and step is started
+var DeploymentPause PauseController = &Pauser{}
+
+// Pauser is a PauseController that is paused and resumed explicitly, e.g. on receiving a signal
+type Pauser struct {
+	mu      sync.Mutex
+	paused  bool
+	resumed chan struct{}
+}
+
+// Pause stops new tracks and steps from starting until Resume is called
+func (p *Pauser) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused {
+		p.paused = true
+		p.resumed = make(chan struct{})
+	}
+}
+
+// Resume releases the tracks and steps waiting to start
+func (p *Pauser) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		p.paused = false
+		close(p.resumed)
+	}
+}
+
+// Paused reports whether new tracks and steps are waiting to start
+func (p *Pauser) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.paused
+}
+
+// WaitUntilResumed blocks while paused, returning ctx's error if it is cancelled before resuming
+func (p *Pauser) WaitUntilResumed(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.paused {
+		p.mu.Unlock()
+		return nil
+	}
+
+	resumed := p.resumed
+	p.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,24 @@
+package tracks
+
+import (
+	"github.com/optum/runiac/pkg/config"
+)
+
+// PlanSummary aggregates the plan summaries of the stage's deployed steps into a single rollup, planned is false when
+// no step's runner produced a plan
+func (s Stage) PlanSummary() (summary config.PlanSummary, planned bool) {
+	for _, t := range s.Tracks {
+		for _, exec := range t.Output.Executions {
+			for _, step := range exec.Output.Steps {
+				if step.Output.PlanSummary == nil {
+					continue
+				}
+
+				summary = summary.Plus(*step.Output.PlanSummary)
+				planned = true
+			}
+		}
+	}
+
+	return
+}
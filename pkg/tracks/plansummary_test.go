@@ -0,0 +1,77 @@
+package tracks_test
+
+import (
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagePlanSummary_ShouldAggregateStepPlanSummaries(t *testing.T) {
+	// arrange
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc":     {Name: "vpc", Output: config.StepOutput{PlanSummary: &config.PlanSummary{Add: 2, Change: 1}}},
+									"subnets": {Name: "subnets", Output: config.StepOutput{PlanSummary: &config.PlanSummary{}}},
+									"script":  {Name: "script", Output: config.StepOutput{}},
+								},
+							},
+						},
+					},
+				},
+			},
+			"app": {
+				Name: "app",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"service": {Name: "service", Output: config.StepOutput{PlanSummary: &config.PlanSummary{Add: 1, Destroy: 1}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	summary, planned := stage.PlanSummary()
+
+	// assert
+	require.True(t, planned)
+	require.Equal(t, config.PlanSummary{Add: 3, Change: 1, Destroy: 1}, summary)
+	require.Equal(t, "will create 3, change 1, destroy 1", summary.String())
+}
+
+func TestStagePlanSummary_ShouldNotBePlannedWithoutPlanningSteps(t *testing.T) {
+	// arrange
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"scripts": {
+				Name: "scripts",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{Output: tracks.ExecutionOutput{Steps: map[string]config.Step{"script": {Name: "script"}}}},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	_, planned := stage.PlanSummary()
+
+	// assert
+	require.False(t, planned)
+}
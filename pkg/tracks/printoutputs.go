@@ -0,0 +1,113 @@
+package tracks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
+)
+
+// PrintedOutput is a requested output variable resolved from the outputs of the primary step executions
+type PrintedOutput struct {
+	Request   string // The requested output variable name, optionally qualified by track, e.g. app.endpoint_url
+	TrackName string
+	StepName  string
+	Value     string
+	Found     bool
+}
+
+func (o PrintedOutput) String() string {
+	if !o.Found {
+		return fmt.Sprintf("%s = (not found)", o.Request)
+	}
+
+	name := o.Request
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return fmt.Sprintf("%s/%s.%s = %s", o.TrackName, o.StepName, name, o.Value)
+}
+
+// ResolveOutputs resolves the requested output variable names, optionally qualified by track (e.g. app.endpoint_url), from
+// the outputs of the stage's primary step executions. A name exported by several steps resolves to each, ordered by track
+// and step, while a name no step exported is resolved as not found
+func (s Stage) ResolveOutputs(requests []string) (outputs []PrintedOutput) {
+	for _, request := range requests {
+		trackName, name := "", request
+		if i := strings.Index(request, "."); i >= 0 {
+			trackName, name = request[:i], request[i+1:]
+		}
+
+		var resolved []PrintedOutput
+		for _, t := range s.Tracks {
+			if trackName != "" && t.Name != trackName {
+				continue
+			}
+
+			for _, exec := range t.Output.Executions {
+				if exec.RegionDeployType != config.PrimaryRegionDeployType {
+					continue
+				}
+
+				for _, step := range exec.Output.Steps {
+					if value, ok := step.Output.OutputVariables[name]; ok {
+						resolved = append(resolved, PrintedOutput{
+							Request:   request,
+							TrackName: t.Name,
+							StepName:  step.Name,
+							Value:     terraform.OutputToString(value),
+							Found:     true,
+						})
+					}
+				}
+			}
+		}
+
+		if len(resolved) == 0 {
+			resolved = append(resolved, PrintedOutput{Request: request, TrackName: trackName})
+		}
+
+		sort.Slice(resolved, func(i, j int) bool {
+			if resolved[i].TrackName != resolved[j].TrackName {
+				return resolved[i].TrackName < resolved[j].TrackName
+			}
+			return resolved[i].StepName < resolved[j].StepName
+		})
+
+		outputs = append(outputs, resolved...)
+	}
+
+	return
+}
+
+// WriteOutputs writes the resolved outputs to w, one per line
+func WriteOutputs(w io.Writer, outputs []PrintedOutput) error {
+	if _, err := fmt.Fprintln(w, "Outputs:"); err != nil {
+		return err
+	}
+
+	for _, o := range outputs {
+		if _, err := fmt.Fprintf(w, "  %s\n", o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printOutputs prints the outputs requested by cfg.PrintOutputs once the deployment completes
+func (tracker DirectoryBasedTracker) printOutputs(cfg config.Config, output *Stage) {
+	w := tracker.Out
+	if w == nil {
+		w = os.Stdout
+	}
+
+	if err := WriteOutputs(w, output.ResolveOutputs(cfg.PrintOutputs)); err != nil {
+		tracker.Log.WithError(err).Error("Unable to print outputs")
+	}
+}
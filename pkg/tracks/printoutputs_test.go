@@ -0,0 +1,52 @@
+package tracks_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTracks_ShouldPrintRequestedOutputs(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/app/step1_service/main.tf", []byte(""), 0644)
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		s.Output = config.StepOutput{Status: config.Success, StepName: s.Name}
+
+		switch s.Name {
+		case "vpc":
+			s.Output.OutputVariables = map[string]interface{}{"vpc_id": "vpc-123", "region": region}
+		case "service":
+			s.Output.OutputVariables = map[string]interface{}{"endpoint_url": "https://app.example.com", "region": region}
+		}
+
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	var stdout bytes.Buffer
+
+	// act
+	tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger, Out: &stdout}.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		PrimaryRegion: "us-east-1",
+		PrintOutputs:  []string{"endpoint_url", "network.region", "app.vpc_id", "missing"},
+	})
+
+	// assert
+	require.Equal(t, `Outputs:
+  app/service.endpoint_url = https://app.example.com
+  network/vpc.region = us-east-1
+  app.vpc_id = (not found)
+  missing = (not found)
+`, stdout.String())
+}
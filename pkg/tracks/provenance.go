@@ -0,0 +1,82 @@
+package tracks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// OutputVariableCollision is an output variable set by a step that a different step already set
+type OutputVariableCollision struct {
+	Key          string // The key of the output variables in the track output variables, e.g. the step name
+	Name         string // The name of the output variable
+	Step         string // The step overwriting the output variable
+	PreviousStep string // The step that set the output variable, or its key when the variable was passed to the region execution
+}
+
+func (c OutputVariableCollision) Error() string {
+	return fmt.Sprintf("output variable %s-%s of step %s would be overwritten by step %s", c.Key, c.Name, c.PreviousStep, c.Step)
+}
+
+// outputVariableSources records the step that set each output variable, K={output key}, V={map[outputVarName: step name]}
+type outputVariableSources map[string]map[string]string
+
+// newOutputVariableSources attributes the output variables passed to a region execution to the step they are keyed by,
+// e.g. pretrack-{step} or {track}-{step}
+func newOutputVariableSources(outputVariables map[string]map[string]string) outputVariableSources {
+	sources := outputVariableSources{}
+	for key, vars := range outputVariables {
+		for name := range vars {
+			sources.set(key, name, key)
+		}
+	}
+
+	return sources
+}
+
+func (sources outputVariableSources) set(key string, name string, step string) {
+	if sources[key] == nil {
+		sources[key] = map[string]string{}
+	}
+
+	sources[key][name] = step
+}
+
+// collisions returns the output variables that adding the step's outputs with strategy would overwrite, that were set by a
+// different step, ordered by name
+func (sources outputVariableSources) collisions(step string, output config.StepOutput, strategy string) []OutputVariableCollision {
+	key, nested := trackOutputKey(output, strategy)
+
+	names := []string{}
+	if nested {
+		names = append(names, output.RegionDeployType.String())
+	} else {
+		for name := range output.OutputVariables {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var collisions []OutputVariableCollision
+	for _, name := range names {
+		if previous, ok := sources[key][name]; ok && previous != step {
+			collisions = append(collisions, OutputVariableCollision{Key: key, Name: name, Step: step, PreviousStep: previous})
+		}
+	}
+
+	return collisions
+}
+
+// record attributes the output variables the step's outputs are added with to the step
+func (sources outputVariableSources) record(step string, output config.StepOutput, strategy string) {
+	key, nested := trackOutputKey(output, strategy)
+	if nested {
+		sources.set(key, output.RegionDeployType.String(), step)
+		return
+	}
+
+	for name := range output.OutputVariables {
+		sources.set(key, name, step)
+	}
+}
@@ -0,0 +1,70 @@
+package tracks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/spf13/afero"
+)
+
+// RegionStatusInProgress is the status of a region execution that has been dispatched but not yet completed
+const RegionStatusInProgress = "IN_PROGRESS"
+
+// RegionStatus is the latest status of a track's region execution, written for external pollers
+type RegionStatus struct {
+	Status           string    `json:"status"`
+	RegionDeployType string    `json:"regionDeployType"`
+	Environment      string    `json:"environment,omitempty"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// regionExecutionStatus is FAIL when any of the region execution's steps failed, CANCELLED when any were cancelled,
+// otherwise SUCCESS
+func regionExecutionStatus(exec RegionExecution) string {
+	status := config.Success
+	for _, step := range exec.Output.Steps {
+		if step.Output.Status == config.Fail {
+			return config.Fail.String()
+		} else if step.Output.Status == config.Cancelled {
+			status = config.Cancelled
+		}
+	}
+
+	return status.String()
+}
+
+// WriteRegionStatusFile writes the status of a track's region execution to {dir}/{track}/{region}.status.
+// A region deploying both primary and regional steps reports its most recent region deploy type.
+func WriteRegionStatusFile(fs afero.Fs, dir string, environment string, track string, regionDeployType config.RegionDeployType, region string, status string) error {
+	trackDir := filepath.Join(dir, track)
+
+	if err := fs.MkdirAll(trackDir, 0755); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(RegionStatus{
+		Status:           status,
+		RegionDeployType: regionDeployType.String(),
+		Environment:      environment,
+		Timestamp:        time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, filepath.Join(trackDir, fmt.Sprintf("%s.status", region)), b, 0644)
+}
+
+// writeRegionStatus writes the region status file when RegionStatusDir is configured, logging any failure
+func writeRegionStatus(execution Execution, cfg config.Config, track string, regionDeployType config.RegionDeployType, region string, status string) {
+	if cfg.RegionStatusDir == "" {
+		return
+	}
+
+	if err := WriteRegionStatusFile(execution.Fs, cfg.RegionStatusDir, cfg.Environment, track, regionDeployType, region, status); err != nil {
+		execution.Logger.WithError(err).Errorf("Failed to write %s status file for region %s", track, region)
+	}
+}
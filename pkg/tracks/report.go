@@ -0,0 +1,124 @@
+package tracks
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// StepTiming represents how long a single step execution took within a region
+type StepTiming struct {
+	TrackName        string
+	StepName         string
+	RegionDeployType config.RegionDeployType
+	Region           string
+	Duration         time.Duration
+}
+
+func (s StepTiming) String() string {
+	return fmt.Sprintf("%v/%v/%v/%v (%s)", s.TrackName, s.StepName, s.RegionDeployType, s.Region, s.Duration.Round(time.Millisecond))
+}
+
+// TimingReport summarizes step durations across a Stage to help find optimization targets
+type TimingReport struct {
+	SlowestSteps   []StepTiming  // The slowest step executions, slowest first
+	P50            time.Duration // Median step duration
+	P90            time.Duration // 90th percentile step duration
+	P99            time.Duration // 99th percentile step duration
+	WallClock      time.Duration // The wall-clock time of the deployment
+	SummedStepTime time.Duration // The total time spent across all step executions
+}
+
+// Parallelism is the ratio of summed step time to wall-clock time, values above 1 indicate steps ran concurrently
+func (r TimingReport) Parallelism() float64 {
+	if r.WallClock <= 0 {
+		return 0
+	}
+
+	return float64(r.SummedStepTime) / float64(r.WallClock)
+}
+
+func (r TimingReport) String() string {
+	slowest := make([]string, 0, len(r.SlowestSteps))
+	for _, s := range r.SlowestSteps {
+		slowest = append(slowest, s.String())
+	}
+
+	return fmt.Sprintf("Wall-clock %s, summed step time %s (%.2fx parallelism). Step durations p50 %s, p90 %s, p99 %s. Slowest steps: %s.",
+		r.WallClock.Round(time.Millisecond), r.SummedStepTime.Round(time.Millisecond), r.Parallelism(),
+		r.P50.Round(time.Millisecond), r.P90.Round(time.Millisecond), r.P99.Round(time.Millisecond), strings.Join(slowest, ", "))
+}
+
+// StepTimings returns the timing of every step execution in the stage, including destroys
+func (s Stage) StepTimings() (timings []StepTiming) {
+	for _, t := range s.Tracks {
+		for _, output := range []Output{t.Output, t.DestroyOutput} {
+			for _, exec := range output.Executions {
+				for _, step := range exec.Output.Steps {
+					if step.Output.Duration <= 0 {
+						continue
+					}
+
+					timings = append(timings, StepTiming{
+						TrackName:        t.Name,
+						StepName:         step.Name,
+						RegionDeployType: exec.RegionDeployType,
+						Region:           exec.Region,
+						Duration:         step.Output.Duration,
+					})
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// TimingReport builds a TimingReport listing the top n slowest steps across the stage
+func (s Stage) TimingReport(n int) (report TimingReport) {
+	timings := s.StepTimings()
+
+	// ties are ordered by name to keep the report stable between runs
+	sort.SliceStable(timings, func(i, j int) bool {
+		if timings[i].Duration != timings[j].Duration {
+			return timings[i].Duration > timings[j].Duration
+		}
+
+		return timings[i].String() < timings[j].String()
+	})
+
+	report.WallClock = s.Duration
+
+	for _, t := range timings {
+		report.SummedStepTime += t.Duration
+	}
+
+	if n > len(timings) {
+		n = len(timings)
+	}
+
+	report.SlowestSteps = timings[:n]
+	report.P50 = percentile(timings, 50)
+	report.P90 = percentile(timings, 90)
+	report.P99 = percentile(timings, 99)
+
+	return
+}
+
+// percentile returns the nearest-rank percentile of timings sorted from slowest to fastest
+func percentile(timings []StepTiming, p float64) time.Duration {
+	if len(timings) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(timings))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return timings[len(timings)-rank].Duration
+}
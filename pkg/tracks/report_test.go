@@ -0,0 +1,95 @@
+package tracks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func stubStageWithDurations() tracks.Stage {
+	stepWithDuration := func(name string, d time.Duration) config.Step {
+		return config.Step{Name: name, Output: config.StepOutput{Status: config.Success, Duration: d}}
+	}
+
+	return tracks.Stage{
+		Duration: 6 * time.Second,
+		Tracks: map[string]tracks.Track{
+			"track-a": {
+				Name: "track-a",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"a1": stepWithDuration("a1", 1*time.Second),
+									"a2": stepWithDuration("a2", 5*time.Second),
+								},
+							},
+						},
+						{
+							Region:           "us-east-2",
+							RegionDeployType: config.RegionalRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"a1": stepWithDuration("a1", 3*time.Second),
+									"a2": {Name: "a2", Output: config.StepOutput{Status: config.Na}},
+								},
+							},
+						},
+					},
+				},
+			},
+			"track-b": {
+				Name: "track-b",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"b1": stepWithDuration("b1", 2*time.Second),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTimingReport_ShouldRankSlowestSteps(t *testing.T) {
+	// act
+	report := stubStageWithDurations().TimingReport(2)
+
+	// assert
+	require.Len(t, report.SlowestSteps, 2, "Only the top N steps should be reported")
+	require.Equal(t, tracks.StepTiming{TrackName: "track-a", StepName: "a2", RegionDeployType: config.PrimaryRegionDeployType, Region: "us-east-1", Duration: 5 * time.Second}, report.SlowestSteps[0])
+	require.Equal(t, tracks.StepTiming{TrackName: "track-a", StepName: "a1", RegionDeployType: config.RegionalRegionDeployType, Region: "us-east-2", Duration: 3 * time.Second}, report.SlowestSteps[1])
+
+	require.Equal(t, 11*time.Second, report.SummedStepTime, "Steps without durations should not be counted")
+	require.Equal(t, 6*time.Second, report.WallClock)
+	require.InDelta(t, 11.0/6.0, report.Parallelism(), 0.001)
+
+	require.Equal(t, 2*time.Second, report.P50)
+	require.Equal(t, 5*time.Second, report.P90)
+	require.Equal(t, 5*time.Second, report.P99)
+}
+
+func TestTimingReport_ShouldHandleFewerStepsThanRequested(t *testing.T) {
+	// act
+	report := stubStageWithDurations().TimingReport(10)
+	empty := tracks.Stage{}.TimingReport(10)
+
+	// assert
+	require.Len(t, report.SlowestSteps, 4)
+	require.Len(t, empty.SlowestSteps, 0)
+	require.Equal(t, time.Duration(0), empty.P50)
+	require.Equal(t, float64(0), empty.Parallelism())
+}
@@ -0,0 +1,239 @@
+package tracks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Streamed result types
+const (
+	StepResultType  = "step"
+	TrackResultType = "track"
+)
+
+// StreamedResult is the result of a step, in a region, or a track, published as soon as it completes
+type StreamedResult struct {
+	Type             string  `json:"type"`   // One of the result types, step or track
+	Action           string  `json:"action"` // deploy or destroy
+	Track            string  `json:"track"`
+	Step             string  `json:"step,omitempty"`
+	Region           string  `json:"region,omitempty"`
+	RegionDeployType string  `json:"regionDeployType,omitempty"`
+	Status           string  `json:"status"`
+	Error            string  `json:"error,omitempty"`
+	DurationSeconds  float64 `json:"durationSeconds,omitempty"`
+	Time             string  `json:"time"`
+}
+
+// ResultStreamer sends a batch of results to a backend, e.g. over HTTP or gRPC
+type ResultStreamer interface {
+	StreamResults(results []StreamedResult) error
+}
+
+// ResultPublisher is invoked as each step and track completes, publishing must not block the deployment
+type ResultPublisher interface {
+	Publish(result StreamedResult)
+}
+
+// Results publishes step and track results as they complete, results are discarded unless a publisher is configured
+var Results ResultPublisher = discardResults{}
+
+type discardResults struct{}
+
+func (discardResults) Publish(StreamedResult) {}
+
+// BufferedResultPublisher streams results in batches from a background goroutine. When the streamer falls behind
+// and the buffer is full, results are dropped rather than stalling the deployment
+type BufferedResultPublisher struct {
+	Logger        *logrus.Entry
+	Streamer      ResultStreamer
+	BatchSize     int           // The maximum number of results streamed at once
+	FlushInterval time.Duration // How long a partial batch waits for more results before being streamed
+
+	results chan StreamedResult
+	done    chan struct{}
+	dropped int64
+	mutex   sync.RWMutex
+	closed  bool
+}
+
+// NewBufferedResultPublisher starts streaming the results published, buffering up to bufferSize results
+func NewBufferedResultPublisher(logger *logrus.Entry, streamer ResultStreamer, bufferSize int, batchSize int, flushInterval time.Duration) *BufferedResultPublisher {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	p := &BufferedResultPublisher{
+		Logger:        logger,
+		Streamer:      streamer,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		results:       make(chan StreamedResult, bufferSize),
+		done:          make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Publish buffers the result to be streamed, dropping it when the buffer is full. Results published after Close, e.g.
+// by an abandoned track, are discarded
+func (p *BufferedResultPublisher) Publish(result StreamedResult) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.results <- result:
+	default:
+		if atomic.AddInt64(&p.dropped, 1) == 1 {
+			p.Logger.Warn("Result stream is falling behind, dropping results")
+		}
+	}
+}
+
+// Dropped is the number of results dropped as the buffer was full
+func (p *BufferedResultPublisher) Dropped() int {
+	return int(atomic.LoadInt64(&p.dropped))
+}
+
+// Close streams the buffered results and stops the publisher, results published afterwards are discarded
+func (p *BufferedResultPublisher) Close() {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return
+	}
+
+	p.closed = true
+	close(p.results)
+	p.mutex.Unlock()
+
+	<-p.done
+
+	if dropped := p.Dropped(); dropped > 0 {
+		p.Logger.Warnf("Dropped %d result(s) the result stream could not keep up with", dropped)
+	}
+}
+
+func (p *BufferedResultPublisher) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []StreamedResult
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := p.Streamer.StreamResults(batch); err != nil {
+			p.Logger.WithError(err).Warnf("Unable to stream %d result(s)", len(batch))
+		}
+
+		batch = nil
+	}
+
+	for {
+		select {
+		case result, ok := <-p.results:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, result)
+			if len(batch) >= p.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// HTTPResultStreamer posts each batch of results as a JSON array to URL
+type HTTPResultStreamer struct {
+	URL    string
+	Client *http.Client
+}
+
+// StreamResults posts the results, failing unless the backend responds with a 2xx status
+func (h HTTPResultStreamer) StreamResults(results []StreamedResult) error {
+	body, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(h.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("result stream %s responded with %s", h.URL, resp.Status)
+	}
+
+	return nil
+}
+
+// newStepResult is the result of a step executed in the region execution
+func newStepResult(action string, execution RegionExecution, s config.Step) StreamedResult {
+	result := StreamedResult{
+		Type:             StepResultType,
+		Action:           action,
+		Track:            execution.TrackName,
+		Step:             s.Name,
+		Region:           execution.Region,
+		RegionDeployType: execution.RegionDeployType.String(),
+		Status:           s.Output.Status.String(),
+		DurationSeconds:  s.Output.Duration.Seconds(),
+		Time:             time.Now().UTC().Format(time.RFC3339Nano),
+	}
+
+	if s.Output.Err != nil {
+		result.Error = s.Output.Err.Error()
+	}
+
+	return result
+}
+
+// newTrackResult is the result of the track's execution
+func newTrackResult(action string, output Output) StreamedResult {
+	result := StreamedResult{
+		Type:   TrackResultType,
+		Action: action,
+		Track:  output.Name,
+		Status: config.Success.String(),
+		Time:   time.Now().UTC().Format(time.RFC3339Nano),
+	}
+
+	if hasFailedSteps(output) {
+		result.Status = config.Fail.String()
+	}
+
+	if output.Err != nil {
+		result.Error = output.Err.Error()
+	}
+
+	return result
+}
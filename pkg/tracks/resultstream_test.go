@@ -0,0 +1,176 @@
+package tracks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResultStreamer records the streamed results, blocking each batch until released when stalled
+type fakeResultStreamer struct {
+	mutex   sync.Mutex
+	results []tracks.StreamedResult
+	stalled chan struct{}
+}
+
+func (f *fakeResultStreamer) StreamResults(results []tracks.StreamedResult) error {
+	if f.stalled != nil {
+		<-f.stalled
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.results = append(f.results, results...)
+
+	return nil
+}
+
+func TestBufferedResultPublisher_ShouldStreamStepResultsInOrder(t *testing.T) {
+	// arrange
+	streamer := &fakeResultStreamer{}
+	publisher := tracks.NewBufferedResultPublisher(logger, streamer, 10, 2, time.Hour)
+
+	defer func(previous tracks.ResultPublisher) { tracks.Results = previous }(tracks.Results)
+	tracks.Results = publisher
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		s.Output = config.StepOutput{Status: config.Success}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	// act
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		TrackName:                  "network",
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		Region:                     "us-east-1",
+		TrackStepProgressionsCount: 3,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "vpc"}},
+			2: {{Name: "subnets"}},
+			3: {{Name: "routes"}},
+		},
+	}
+	<-primaryOutChan
+	publisher.Close()
+
+	// assert
+	var steps []string
+	for _, result := range streamer.results {
+		require.Equal(t, tracks.StepResultType, result.Type)
+		require.Equal(t, "deploy", result.Action)
+		require.Equal(t, "network", result.Track)
+		require.Equal(t, "us-east-1", result.Region)
+		require.Equal(t, "primary", result.RegionDeployType)
+		require.Equal(t, "SUCCESS", result.Status)
+		steps = append(steps, result.Step)
+	}
+	require.Equal(t, []string{"vpc", "subnets", "routes"}, steps, "Step results should arrive in completion order")
+	require.Equal(t, 0, publisher.Dropped())
+}
+
+func TestBufferedResultPublisher_ShouldNotBlockOnStalledStreamerBeyondBuffer(t *testing.T) {
+	// arrange
+	streamer := &fakeResultStreamer{stalled: make(chan struct{})}
+	publisher := tracks.NewBufferedResultPublisher(logger, streamer, 2, 1, time.Hour)
+
+	published := make(chan struct{})
+
+	// act
+	go func() {
+		for i := 0; i < 10; i++ {
+			publisher.Publish(tracks.StreamedResult{Type: tracks.StepResultType, Step: "step"})
+		}
+		close(published)
+	}()
+
+	// assert
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Publishing should not block on a stalled streamer")
+	}
+
+	close(streamer.stalled)
+	publisher.Close()
+
+	require.Greater(t, publisher.Dropped(), 0, "Results beyond the buffer should be dropped")
+	require.Equal(t, 10, publisher.Dropped()+len(streamer.results), "Every result should either be streamed or dropped")
+}
+
+func TestBufferedResultPublisher_ShouldDiscardResultsPublishedAfterClose(t *testing.T) {
+	// arrange
+	streamer := &fakeResultStreamer{}
+	publisher := tracks.NewBufferedResultPublisher(logger, streamer, 10, 1, time.Hour)
+
+	publisher.Publish(tracks.StreamedResult{Type: tracks.StepResultType, Step: "before"})
+	publisher.Close()
+
+	// act
+	require.NotPanics(t, func() {
+		publisher.Publish(tracks.StreamedResult{Type: tracks.StepResultType, Step: "after"})
+	}, "Results published by abandoned tracks should not panic")
+
+	// assert
+	require.Len(t, streamer.results, 1)
+	require.Equal(t, "before", streamer.results[0].Step)
+}
+
+func TestHTTPResultStreamer_ShouldPostResultsAsJSON(t *testing.T) {
+	// arrange
+	var contentType string
+	var received []tracks.StreamedResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	results := []tracks.StreamedResult{
+		{Type: tracks.StepResultType, Action: "deploy", Track: "network", Step: "vpc", Status: "SUCCESS"},
+		{Type: tracks.TrackResultType, Action: "deploy", Track: "network", Status: "SUCCESS"},
+	}
+
+	// act
+	err := tracks.HTTPResultStreamer{URL: server.URL}.StreamResults(results)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, results, received)
+}
+
+func TestHTTPResultStreamer_ShouldErrorWhenBackendRejectsResults(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// act
+	err := tracks.HTTPResultStreamer{URL: server.URL}.StreamResults([]tracks.StreamedResult{{Type: tracks.TrackResultType}})
+
+	// assert
+	require.Error(t, err)
+}
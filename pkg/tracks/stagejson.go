@@ -0,0 +1,126 @@
+package tracks
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// StageResult is the stable JSON schema of a Stage, for tooling consuming the results of a deployment
+type StageResult struct {
+	Error           string        `json:"error,omitempty"`
+	DurationSeconds float64       `json:"durationSeconds"`
+	Tracks          []TrackResult `json:"tracks"` // Ordered by name
+}
+
+// TrackResult is the result of deploying, and destroying, a track
+type TrackResult struct {
+	Name              string                  `json:"name"`
+	Skipped           bool                    `json:"skipped"`
+	SkipReason        SkipReason              `json:"skipReason,omitempty"`
+	Partial           bool                    `json:"partial,omitempty"`
+	Error             string                  `json:"error,omitempty"`
+	Executions        []RegionExecutionResult `json:"executions"`                  // Ordered by region deploy type then region
+	DestroyExecutions []RegionExecutionResult `json:"destroyExecutions,omitempty"` // Empty unless the track was destroyed
+}
+
+// RegionExecutionResult is the result of a track's steps in a region
+type RegionExecutionResult struct {
+	RegionDeployType    string                       `json:"regionDeployType"`
+	Region              string                       `json:"region"`
+	ExecutedCount       int                          `json:"executedCount"`
+	SkippedCount        int                          `json:"skippedCount"`
+	FailureCount        int                          `json:"failureCount"`
+	CancelledCount      int                          `json:"cancelledCount"`
+	FailedTestCount     int                          `json:"failedTestCount"`
+	DurationSeconds     float64                      `json:"durationSeconds"`
+	StepOutputVariables map[string]map[string]string `json:"stepOutputVariables,omitempty"` // K={step name}, V={map[outputVarName: outputVarVal]}
+}
+
+// Result converts the stage to its JSON schema, leaving out loggers, filesystems and other fields that cannot be marshaled
+func (s Stage) Result() StageResult {
+	result := StageResult{
+		DurationSeconds: s.Duration.Seconds(),
+		Tracks:          []TrackResult{},
+	}
+
+	if s.Err != nil {
+		result.Error = s.Err.Error()
+	}
+
+	for _, t := range s.Tracks {
+		track := TrackResult{
+			Name:              t.Name,
+			Skipped:           t.Skipped,
+			SkipReason:        t.SkipReason,
+			Partial:           t.Output.Partial,
+			Executions:        newRegionExecutionResults(t.Output.Executions),
+			DestroyExecutions: newRegionExecutionResults(t.DestroyOutput.Executions),
+		}
+
+		if len(track.DestroyExecutions) == 0 {
+			track.DestroyExecutions = nil
+		}
+
+		if t.Output.Err != nil {
+			track.Error = t.Output.Err.Error()
+		}
+
+		result.Tracks = append(result.Tracks, track)
+	}
+
+	sort.Slice(result.Tracks, func(i, j int) bool {
+		return result.Tracks[i].Name < result.Tracks[j].Name
+	})
+
+	return result
+}
+
+// ToJSON marshals the stage's results as JSON
+func (s Stage) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(s.Result(), "", "  ")
+}
+
+// WriteStageJSON writes the stage's results as JSON to path
+func WriteStageJSON(fs afero.Fs, path string, s Stage) error {
+	b, err := s.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return afero.WriteFile(fs, path, b, 0644)
+}
+
+func newRegionExecutionResults(executions []RegionExecution) []RegionExecutionResult {
+	results := []RegionExecutionResult{}
+	for _, exec := range executions {
+		results = append(results, RegionExecutionResult{
+			RegionDeployType:    exec.RegionDeployType.String(),
+			Region:              exec.Region,
+			ExecutedCount:       exec.Output.ExecutedCount,
+			SkippedCount:        exec.Output.SkippedCount,
+			FailureCount:        exec.Output.FailureCount,
+			CancelledCount:      exec.Output.CancelledCount,
+			FailedTestCount:     exec.Output.FailedTestCount,
+			DurationSeconds:     exec.Output.Duration.Seconds(),
+			StepOutputVariables: exec.Output.StepOutputVariables,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].RegionDeployType != results[j].RegionDeployType {
+			return results[i].RegionDeployType < results[j].RegionDeployType
+		}
+		return results[i].Region < results[j].Region
+	})
+
+	return results
+}
@@ -0,0 +1,112 @@
+package tracks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageToJSON_ShouldEmitStableSchemaWithoutLoggers(t *testing.T) {
+	// arrange
+	stage := tracks.Stage{
+		Duration: 2 * time.Second,
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-2",
+							RegionDeployType: config.RegionalRegionDeployType,
+							Logger:           logger,
+							Fs:               fs,
+							Output:           tracks.ExecutionOutput{ExecutedCount: 1},
+						},
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Logger:           logger,
+							Fs:               fs,
+							Output: tracks.ExecutionOutput{
+								ExecutedCount:       2,
+								FailureCount:        1,
+								FailedTestCount:     1,
+								StepOutputVariables: map[string]map[string]string{"vpc": {"vpc_id": "vpc-123"}},
+							},
+						},
+					},
+					Err: errors.New("network failed"),
+				},
+			},
+			"app": {
+				Name:       "app",
+				Skipped:    true,
+				SkipReason: tracks.SkipReasonDependencyFailed,
+			},
+		},
+	}
+
+	// act
+	b, err := stage.ToJSON()
+
+	// assert
+	require.NoError(t, err)
+
+	var result tracks.StageResult
+	require.NoError(t, json.Unmarshal(b, &result))
+
+	require.Equal(t, tracks.StageResult{
+		DurationSeconds: 2,
+		Tracks: []tracks.TrackResult{
+			{
+				Name:       "app",
+				Skipped:    true,
+				SkipReason: tracks.SkipReasonDependencyFailed,
+				Executions: []tracks.RegionExecutionResult{},
+			},
+			{
+				Name:  "network",
+				Error: "network failed",
+				Executions: []tracks.RegionExecutionResult{
+					{
+						RegionDeployType:    "primary",
+						Region:              "us-east-1",
+						ExecutedCount:       2,
+						FailureCount:        1,
+						FailedTestCount:     1,
+						StepOutputVariables: map[string]map[string]string{"vpc": {"vpc_id": "vpc-123"}},
+					},
+					{
+						RegionDeployType: "regional",
+						Region:           "us-east-2",
+						ExecutedCount:    1,
+					},
+				},
+			},
+		},
+	}, result)
+}
+
+func TestWriteStageJSON_ShouldWriteResultsToPath(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	stage := tracks.Stage{Tracks: map[string]tracks.Track{"network": {Name: "network"}}}
+
+	// act
+	err := tracks.WriteStageJSON(stubFs, "output/results.json", stage)
+
+	// assert
+	require.NoError(t, err)
+
+	b, err := afero.ReadFile(stubFs, "output/results.json")
+	require.NoError(t, err)
+
+	expected, _ := stage.ToJSON()
+	require.Equal(t, string(expected), string(b))
+}
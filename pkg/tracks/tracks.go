@@ -1,34 +1,50 @@
 package tracks
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/optum/runiac/pkg/cloudaccountdeployment"
 	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/shell"
 	"github.com/optum/runiac/pkg/steps"
 	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
-	"github.com/otiai10/copy"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
 
 const (
-	PRE_TRACK_NAME     = "_pretrack" // The name of the directory for the pretrack
-	DEFAULT_TRACK_NAME = "default"   // The name of the default top-level track
+	PRE_TRACK_NAME     = "_pretrack"  // The name of the directory for the pretrack
+	POST_TRACK_NAME    = "_posttrack" // The name of the directory for the posttrack
+	DEFAULT_TRACK_NAME = "default"    // The name of the default top-level track
 )
 
+// DefaultMaxTrackDepth is used when cfg.MaxTrackDepth is unset
+const DefaultMaxTrackDepth = 8
+
+// DefaultTracksDir is used when cfg.TracksDir is unset
+const DefaultTracksDir = "./tracks"
+
 // ExecuteTrackFunc facilitates track executions across multiple regions and RegionDeployTypes (e.g. Primary us-east-1 and regional us-*)
-type ExecuteTrackFunc func(execution Execution, cfg config.Config, t Track, out chan<- Output)
+type ExecuteTrackFunc func(ctx context.Context, execution Execution, cfg config.Config, t Track, out chan<- Output)
 
 // ExecuteTrackRegionFunc executes a track within a single region and RegionDeployType (e.g. primary/us-east-1 or regional/us-east-2)
-type ExecuteTrackRegionFunc func(in <-chan RegionExecution, out chan<- RegionExecution)
+type ExecuteTrackRegionFunc func(ctx context.Context, in <-chan RegionExecution, out chan<- RegionExecution)
 
-type ExecuteStepFunc func(region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+// ExecuteStepFunc executes a step within a single region, cancelling ctx interrupts the step's runner
+type ExecuteStepFunc func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
 	s config.Step, out chan<- config.Step, destroy bool)
 
 var DeployTrackRegion ExecuteTrackRegionFunc = ExecuteDeployTrackRegion
@@ -39,16 +55,31 @@ var DestroyTrack ExecuteTrackFunc = ExecuteDestroyTrack
 
 var ExecuteStep ExecuteStepFunc = ExecuteStepImpl
 
+// RequestApprovalFunc blocks until the approval request is approved or denied
+type RequestApprovalFunc func(ctx context.Context, logger *logrus.Entry, cfg config.Config, request ApprovalRequest) (approved bool, err error)
+
+var RequestApproval RequestApprovalFunc = RequestApprovalImpl
+
+// RunDeploymentCommandFunc runs a deployment-level command such as the before all or after all command
+type RunDeploymentCommandFunc func(ctx context.Context, logger *logrus.Entry, cfg config.Config, command string) (string, error)
+
+var RunDeploymentCommand RunDeploymentCommandFunc = RunDeploymentCommandImpl
+
+// FailureClassifier categorizes failed steps, retryable failures are re-executed up to cfg.RetryableFailureRetries times
+var FailureClassifier config.FailureClassifier = steps.DefaultFailureClassifier{}
+
 // Tracker is an interface for working with tracks
 type Tracker interface {
-	GatherTracks(config config.Config) (tracks []Track)
-	ExecuteTracks(config config.Config) (output Stage)
+	GatherTracks(config config.Config) (tracks []Track, err error)
+	ExecuteTracks(ctx context.Context, config config.Config) (output Stage)
 }
 
 // DirectoryBasedTracker implements the Tracker interface
 type DirectoryBasedTracker struct {
-	Log *logrus.Entry
-	Fs  afero.Fs
+	Log   *logrus.Entry
+	Fs    afero.Fs
+	Out   io.Writer  // Where the outputs requested by cfg.PrintOutputs are printed, defaults to stdout
+	Hooks []StepHook // Called before and after each step is deployed or destroyed
 }
 
 // Track represents a delivery framework track (unit of functionality)
@@ -63,15 +94,51 @@ type Track struct {
 	OrderedSteps                map[int][]config.Step
 	Output                      Output
 	DestroyOutput               Output
-	IsPreTrack                  bool // If true, this is a PreTrack, meaning it should be run before all other tracks
-	IsDefaultTrack              bool // If true, this track represents steps contained in a standalone, top-level track
-	Skipped                     bool // Indicates that the track was skipped. This will be for non-pretrack tracks if the pretrack fails
+	IsPreTrack                  bool       // If true, this is a PreTrack, meaning it should be run before all other tracks
+	IsPostTrack                 bool       // If true, this is a PostTrack, meaning it should be run after all other tracks
+	IsDefaultTrack              bool       // If true, this track represents steps contained in a standalone, top-level track
+	Skipped                     bool       // Indicates that the track was skipped. This will be for non-pretrack tracks if the pretrack fails
+	SkipReason                  SkipReason // Why the track was skipped, empty unless Skipped
+	PrimaryRegion               string     // The track's primary region, cfg.PrimaryRegion unless overridden by the track's configuration
+	RegionalRegions             []string   // The track's regional regions, cfg.RegionalRegions unless overridden by the track's configuration
+	Config                      config.TrackConfig
 }
 
+// SkipReason describes why a track was not executed
+type SkipReason string
+
+const (
+	// SkipReasonBeforeAllCommandFailed tracks were not executed because the before all command failed
+	SkipReasonBeforeAllCommandFailed SkipReason = "before_all_command_failed"
+	// SkipReasonPreTrackFailed tracks were not executed because a step of the pretrack failed
+	SkipReasonPreTrackFailed SkipReason = "pretrack_failed"
+	// SkipReasonNotInTrackOrder tracks were excluded by cfg.TrackOrderExcludeUnlisted
+	SkipReasonNotInTrackOrder SkipReason = "not_in_track_order"
+	// SkipReasonPreviousStageFailed tracks were not executed because a track in an earlier stage failed
+	SkipReasonPreviousStageFailed SkipReason = "previous_stage_failed"
+	// SkipReasonDependencyFailed tracks were not executed because a track they depend on failed or was skipped
+	SkipReasonDependencyFailed SkipReason = "dependency_failed"
+	// SkipReasonDependencyCycle tracks were not executed because the tracks' dependencies form a cycle
+	SkipReasonDependencyCycle SkipReason = "dependency_cycle"
+	// SkipReasonPreTrackSkipped pretracks were not executed because cfg.SkipPreTrack is set
+	SkipReasonPreTrackSkipped SkipReason = "pretrack_skipped"
+	// SkipReasonUpstreamTrackFailed posttracks were not executed because another track failed or was skipped
+	SkipReasonUpstreamTrackFailed SkipReason = "upstream_track_failed"
+)
+
 type Output struct {
 	Name                       string
 	PrimaryStepOutputVariables map[string]map[string]string
 	Executions                 []RegionExecution
+	Partial                    bool  // Indicates the track only completed some of its phases (e.g. regional deployment was not approved)
+	Err                        error // Set when the track failed as a whole (e.g. fewer regions than min_successful_regions succeeded)
+}
+
+// ApprovalRequest describes a deployment phase waiting for manual verification
+type ApprovalRequest struct {
+	TrackName string
+	Phase     string // The phase waiting to be approved, e.g. regional
+	Regions   []string
 }
 
 type Execution struct {
@@ -80,6 +147,7 @@ type Execution struct {
 	Output                              ExecutionOutput
 	DefaultExecutionStepOutputVariables map[string]map[string]map[string]string
 	PreTrackOutput                      *Output
+	UpstreamTrackOutputs                map[string]Output // The outputs of every other track, only set for the posttrack. K=track name
 }
 
 type RegionExecution struct {
@@ -95,6 +163,15 @@ type RegionExecution struct {
 	RegionDeployType           config.RegionDeployType
 	PrimaryOutput              ExecutionOutput // This value is only set when regiondeploytype == regional
 	DefaultStepOutputVariables map[string]map[string]string
+	MaxStepProgression         int               // When greater than zero, steps in later progressions are skipped
+	ChannelBufferSize          int               // The buffer size of the step and test result channels
+	Cancelled                  <-chan struct{}   // When closed, steps that have not started are skipped
+	SkipTests                  bool              // When true, step tests are not executed
+	MaxParallelSteps           int               // When greater than zero, limits the steps executed concurrently within each progression level
+	ContinueOnError            bool              // When true, steps in later progressions are executed despite earlier step failures
+	StrictOutputVariables      bool              // When true, steps overwriting an output variable set by a different step fail
+	ExtraStepInputs            map[string]string // K={step}-{output variable}, V=the value every step receives as if the step output it
+	ExtraStepInputsAsDefaults  bool              // When true, step output variables take precedence over the extra step inputs naming them
 }
 
 // TrackOutput represents the output from a track execution
@@ -104,7 +181,10 @@ type ExecutionOutput struct {
 	ExecutedCount       int
 	SkippedCount        int
 	FailureCount        int
+	CancelledCount      int // Steps interrupted, or never started, as the deployment was cancelled or timed out
 	FailedTestCount     int
+	RateLimitedCount    int           // Steps that encountered provider API rate limiting, reported separately from failures
+	Duration            time.Duration // The wall-clock time of the track's steps in the region
 	Steps               map[string]config.Step
 	FailedSteps         []config.Step
 	StepOutputVariables map[string]map[string]string // Output variables across all steps in the track. A map where K={step name} and V={map[outputVarName: outputVarVal]}
@@ -112,65 +192,228 @@ type ExecutionOutput struct {
 
 // Stage represents the outputs of tracks
 type Stage struct {
-	Tracks map[string]Track
+	Tracks   map[string]Track
+	Err      error         // Set when the deployment was aborted before tracks were executed (e.g. the before all command failed)
+	Duration time.Duration // The wall-clock time of the whole deployment
 }
 
-// GatherTracks gets all tracks that should be executed based
-// on the directory structure
-func (tracker DirectoryBasedTracker) GatherTracks(config config.Config) (tracks []Track) {
-	defaultDir := "./"
-	tracksDir := "./tracks"
+// GatherTracks gets all tracks that should be executed based on the directory structure of each track root,
+// returning an error when a track can not be read or tracks in different roots share a name
+func (tracker DirectoryBasedTracker) GatherTracks(config config.Config) (tracks []Track, err error) {
+	defaultDir := defaultTrackDir(config)
 	defaultExists := false
+	trackRoots := map[string]string{} // K=track name, V=root the track was gathered from
+
+	roots := config.TrackRoots
+	if len(roots) == 0 {
+		roots = []string{"./"}
+	}
+
+	// the tracks that targeted tracks depend on are targeted in full
+	dependencies := map[string]bool{}
+	if config.IncludeDependencies && !config.TargetAll {
+		dependencies = tracker.dependencyTracks(config, roots)
+	}
 
-	// try to read steps from the default track and step at the top-level directory, if it exists
-	t, included, _ := tracker.readTrack(config, DEFAULT_TRACK_NAME, defaultDir)
-	if included && t.StepsCount > 0 {
-		defaultExists = true
-		tracker.Log.Println(fmt.Sprintf("Tracks: Adding default track"))
-		tracks = append(tracks, t)
+	if len(config.TrackRoots) == 0 {
+		// the default track is only supported when deploying from a single root
+		// try to read steps from the default track and step at the top-level directory, if it exists
+		t, included, err := tracker.readTrack(config, DEFAULT_TRACK_NAME, defaultDir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read track %s: %w", DEFAULT_TRACK_NAME, err)
+		}
+		if included && t.StepsCount > 0 {
+			defaultExists = true
+			tracker.Log.Println(fmt.Sprintf("Tracks: Adding default track"))
+			tracks = append(tracks, t)
+			trackRoots[t.Name] = roots[0]
+		}
 	}
 
-	// read tracks from the usual tracks directory
-	items, _ := afero.ReadDir(tracker.Fs, tracksDir)
-	for _, item := range items {
-		if item.IsDir() {
-			t, included, _ := tracker.readTrack(config, item.Name(), fmt.Sprintf("%s/%s", tracksDir, item.Name()))
-			if included && t.StepsCount > 0 {
-				tracker.Log.Println(fmt.Sprintf("Tracks: Adding %s", item.Name()))
-				tracks = append(tracks, t)
+	for _, root := range roots {
+		tracksDir := filepath.Join(root, tracksDir(config))
+
+		// read tracks from the usual tracks directory
+		items, _ := afero.ReadDir(tracker.Fs, tracksDir)
+		for _, item := range items {
+			if item.IsDir() {
+				trackConfig := config
+				if dependencies[item.Name()] {
+					tracker.Log.Infof("Tracks: Targeting %s as a dependency of a targeted track", item.Name())
+					trackConfig.TargetAll = true
+				}
+
+				t, included, err := tracker.readTrack(trackConfig, item.Name(), fmt.Sprintf("%s/%s", tracksDir, item.Name()))
+				if err != nil {
+					return nil, fmt.Errorf("unable to read track %s: %w", item.Name(), err)
+				}
+				if included && t.StepsCount > 0 {
+					if existingRoot, ok := trackRoots[t.Name]; ok && existingRoot != root {
+						return nil, fmt.Errorf("track %s exists in multiple track roots: %s and %s", t.Name, existingRoot, root)
+					}
+
+					tracker.Log.Println(fmt.Sprintf("Tracks: Adding %s", item.Name()))
+					tracks = append(tracks, t)
+					trackRoots[t.Name] = root
+				}
 			}
 		}
 	}
 
 	// best practice is for one or the other of the above two situations to be present
 	if defaultExists && len(tracks) > 1 {
-		tracker.Log.Warnf("Detected that a default track (%s) exists along with one or more explicit tracks (%s). Best practice is to migrate your default track to a named one instead.", defaultDir, tracksDir)
+		tracker.Log.Warnf("Detected that a default track (%s) exists along with one or more explicit tracks (%s). Best practice is to migrate your default track to a named one instead.", defaultDir, tracksDir(config))
 	}
 
 	return
 }
 
-func copyDefault(source, destination string) error {
-	var err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+// dependencyTracks returns the names of the tracks that the tracks targeted by cfg.StepWhitelist transitively depend on
+func (tracker DirectoryBasedTracker) dependencyTracks(cfg config.Config, roots []string) map[string]bool {
+	dependsOn := map[string][]string{}
+	for _, root := range roots {
+		tracksDir := filepath.Join(root, tracksDir(cfg))
+
+		items, _ := afero.ReadDir(tracker.Fs, tracksDir)
+		for _, item := range items {
+			if !item.IsDir() {
+				continue
+			}
+
+			trackConfig, err := config.ReadTrackConfig(tracker.Fs, filepath.Join(tracksDir, item.Name()))
+			if err != nil {
+				tracker.Log.WithError(err).Errorf("Tracks: Unable to read the dependencies of %s", item.Name())
+			}
+			dependsOn[item.Name()] = trackConfig.DependsOn
+		}
+	}
+
+	// step ids are #{project}#{track}#{step}, the track may be a glob pattern
+	var pending []string
+	for _, stepID := range cfg.StepWhitelist {
+		if names := config.ParseStepID(cfg.IDDelimiter(), stepID); len(names) == 3 {
+			for name := range dependsOn {
+				if matchesWhitelistedTrack(names[1], name) {
+					pending = append(pending, name)
+				}
+			}
+		}
+	}
+
+	dependencies := map[string]bool{}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+
+		for _, dependency := range dependsOn[name] {
+			if _, ok := dependsOn[dependency]; !ok {
+				tracker.Log.Warnf("Tracks: %s depends on %s which does not exist", name, dependency)
+				continue
+			}
+
+			if !dependencies[dependency] {
+				dependencies[dependency] = true
+				pending = append(pending, dependency)
+			}
+		}
+	}
+
+	return dependencies
+}
+
+// stepPrefix begins the name of each step folder, step{progressionLevel}_{stepName}
+const stepPrefix = "step"
+
+// parseStepFolderName parses the progression level from all the digits following the step prefix, and the step name
+// from the remainder after the separator, e.g. step10_vpc is the vpc step at progression level 10
+func parseStepFolderName(folderName string) (progressionLevel int, stepName string, err error) {
+	rest := strings.TrimPrefix(folderName, stepPrefix)
+
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+
+	if digits == 0 {
+		return 0, "", fmt.Errorf("step folder %s does not start with step{progressionLevel}_", folderName)
+	}
+
+	progressionLevel, err = strconv.Atoi(rest[:digits])
+	if err != nil {
+		return 0, "", fmt.Errorf("step folder %s has an invalid progression level: %w", folderName, err)
+	}
+
+	if progressionLevel < 1 {
+		return 0, "", fmt.Errorf("step folder %s has progression level %d, progression levels start at 1", folderName, progressionLevel)
+	}
+
+	if digits == len(rest) || rest[digits] != '_' || digits+1 == len(rest) {
+		return 0, "", fmt.Errorf("step folder %s does not follow step{progressionLevel}_{stepName}", folderName)
+	}
+
+	return progressionLevel, rest[digits+1:], nil
+}
+
+// tracksDir is the directory named tracks are read from, relative to each track root
+func tracksDir(cfg config.Config) string {
+	if cfg.TracksDir != "" {
+		return cfg.TracksDir
+	}
+
+	return DefaultTracksDir
+}
+
+// defaultTrackDir is the directory containing the tracks directory, where the steps of the default track are read from
+func defaultTrackDir(cfg config.Config) string {
+	dir := filepath.Dir(tracksDir(cfg))
+	if dir == "." {
+		return "./"
+	}
+
+	return dir
+}
+
+// copyDefault copies the default track's steps from source to destination, leaving out the tracks directory. Files that
+// already match their copy are skipped, so repeated runs do not rewrite them
+func (tracker DirectoryBasedTracker) copyDefault(source, destination, tracksDir string) error {
+	return afero.Walk(tracker.Fs, source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
 
-		if strings.HasPrefix(path, "tracks") {
+		if path == filepath.Clean(tracksDir) || strings.HasPrefix(path, filepath.Clean(tracksDir)+string(filepath.Separator)) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		var relPath = strings.Replace(path, source, "", 1)
-		if relPath == "" {
+		relPath, relErr := filepath.Rel(source, path)
+		if relErr != nil || relPath == "." || !strings.HasPrefix(relPath, "step") {
+			if info.IsDir() && relPath != "." {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		if strings.HasPrefix(relPath, "step") {
-			fmt.Println(relPath)
+		target := filepath.Join(destination, relPath)
+		if info.IsDir() {
+			return tracker.Fs.MkdirAll(target, info.Mode().Perm()|0700)
+		}
 
-			return copy.Copy(path, filepath.Join(destination, relPath))
-		} else {
+		b, err := afero.ReadFile(tracker.Fs, path)
+		if err != nil {
+			return err
+		}
+
+		if existing, err := afero.ReadFile(tracker.Fs, target); err == nil && bytes.Equal(existing, b) {
+			tracker.Log.WithField("file", relPath).Debug("Default track file is already copied")
 			return nil
 		}
+
+		tracker.Log.WithField("file", relPath).Debugf("Copying default track file to %s", destination)
+		return afero.WriteFile(tracker.Fs, target, b, info.Mode().Perm())
 	})
-	return err
 }
 
 func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, dir string) (Track, bool, error) {
@@ -180,19 +423,38 @@ func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, d
 		OrderedSteps: map[int][]config.Step{},
 	}
 
+	var manifest Manifest
+	if cfg.SinceLastSuccess {
+		var err error
+		if manifest, err = ReadManifest(tracker.Fs, cfg.ManifestFile); err != nil {
+			return t, false, fmt.Errorf("unable to read manifest %s: %w", cfg.ManifestFile, err)
+		}
+	}
+
 	if t.Name == PRE_TRACK_NAME {
 		tracker.Log.Debug("Pre-track found")
 		t.IsPreTrack = true
+	} else if t.Name == POST_TRACK_NAME {
+		tracker.Log.Debug("Post-track found")
+		t.IsPostTrack = true
 	} else if t.Name == DEFAULT_TRACK_NAME {
 		tracker.Log.Debug("Default track found")
 		t.IsDefaultTrack = true
 	}
 
 	if t.IsDefaultTrack {
-		matches, _ := afero.Glob(tracker.Fs, "*.tf") // TODO(plugin): shift this check to a plugin to support more than terraform
-		if len(matches) > 0 {
-			_ = tracker.Fs.MkdirAll("./tracks/default/", 0755)
-			err := copyDefault("./", "./tracks/default/")
+		runner, matches := steps.DetectRunner(tracker.Fs, dir)
+		if len(matches) > 0 && cfg.FailOnDefaultTrackCreation {
+			return t, false, fmt.Errorf("top-level %s files %v would create a default track, define explicit tracks in %s/{track}/step{n}_{name} instead", runner, matches, tracksDir(cfg))
+		} else if len(matches) > 0 && !cfg.CopyDefaultTrack {
+			tracker.Log.Debugf("Not copying the default track's steps to %s, copying the default track is disabled", filepath.Join(tracksDir(cfg), DEFAULT_TRACK_NAME))
+		} else if len(matches) > 0 {
+			defaultTrackCopyDir := filepath.Join(tracksDir(cfg), DEFAULT_TRACK_NAME)
+			_ = tracker.Fs.MkdirAll(defaultTrackCopyDir, 0755)
+			err := tracker.copyDefault(dir, defaultTrackCopyDir, tracksDir(cfg))
+			if err == nil {
+				err = steps.RestrictPermissions(defaultTrackCopyDir, cfg.FileMode, cfg.DirMode)
+			}
 			if err != nil {
 				tracker.Log.WithError(err).Error("Failed to set up default track step")
 				return t, false, err
@@ -200,6 +462,45 @@ func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, d
 		}
 	}
 
+	// the default track's directory is the project's directory, whose configuration file configures the deployment
+	if !t.IsDefaultTrack {
+		trackConfig, err := config.ReadTrackConfig(tracker.Fs, t.Dir)
+		if err != nil {
+			return t, false, err
+		}
+		t.Config = trackConfig
+	}
+
+	// the track's steps are deployed to the track's regions
+	t.PrimaryRegion, t.RegionalRegions = resolveTrackRegions(cfg, t.Config)
+	cfg.PrimaryRegion, cfg.RegionalRegions = t.PrimaryRegion, t.RegionalRegions
+
+	// always run tracks are targeted in full regardless of the whitelist
+	if t.Config.AlwaysRun && !cfg.TargetAll {
+		tracker.Log.Infof("Tracks: Targeting %s as it is configured to always run", t.Name)
+		cfg.TargetAll = true
+	}
+
+	if t.Config.Stage != "" && (t.IsPreTrack || t.IsPostTrack || !contains(cfg.Stages, t.Config.Stage)) {
+		return t, false, fmt.Errorf("track %s has stage %s which is not one of the configured stages %v", t.Name, t.Config.Stage, cfg.Stages)
+	}
+
+	// steps are guarded against resolving back to their track or tracks directory, the default track is the tracks directory
+	var ancestors []string
+	if !t.IsDefaultTrack {
+		realTracksDir, err := guardTrackDir(tracker.Fs, cfg, nil, filepath.Dir(t.Dir))
+		if err != nil {
+			return t, false, err
+		}
+		ancestors = append(ancestors, realTracksDir)
+	}
+
+	realTrackDir, err := guardTrackDir(tracker.Fs, cfg, ancestors, t.Dir)
+	if err != nil {
+		return t, false, err
+	}
+	ancestors = append(ancestors, realTrackDir)
+
 	// TODO(step:config)
 	//tConfig := viper.New()
 	//tConfig.SetConfigName("runiac")         // name of cfg file (without extension)
@@ -225,7 +526,6 @@ func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, d
 		return t, false, nil
 	} else {
 		tFolders, _ := afero.ReadDir(tracker.Fs, t.Dir)
-		stepPrefix := "step"
 		highestProgressionLevel := 0
 
 		for _, tFolder := range tFolders {
@@ -233,27 +533,45 @@ func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, d
 
 			// step folder convention is step{progressionLevel}_{stepName}
 			if strings.HasPrefix(tFolderName, stepPrefix) {
-				stepName := tFolderName[len(stepPrefix)+2:]
+				progressionLevel, stepName, err := parseStepFolderName(tFolderName)
+				if err != nil {
+					tracker.Log.WithError(err).Warnf("Ignoring %s in track %s", tFolderName, t.Name)
+					continue
+				}
 
-				// if the step belongs to the default track, exclude the name of the track from the identifier
-				stepID := ""
-				if t.IsDefaultTrack {
-					stepID = fmt.Sprintf("#%s#%s", cfg.Project, stepName)
+				// if the step belongs to the default track, exclude the name of the track from the identifier unless configured otherwise
+				var stepID string
+				if t.IsDefaultTrack && !cfg.DefaultTrackIDIncludesName {
+					stepID, err = config.NewStepID(cfg.IDDelimiter(), cfg.Project, stepName)
 				} else {
-					stepID = fmt.Sprintf("#%s#%s#%s", cfg.Project, t.Name, stepName)
+					stepID, err = config.NewStepID(cfg.IDDelimiter(), cfg.Project, t.Name, stepName)
+				}
+
+				if err != nil {
+					tracker.Log.WithError(err).Warnf("Ignoring %s in track %s", tFolderName, t.Name)
+					continue
 				}
 
 				// if step is not targeted, skip.
-				if !contains(cfg.StepWhitelist, stepID) && !cfg.TargetAll {
+				if !isWhitelisted(cfg.StepWhitelist, stepID) && !cfg.TargetAll {
 					tracker.Log.Warningf("Step %s disabled. Not present in whitelist.", stepID)
 					continue
 				}
 
-				parsedStringProgression := string(tFolderName[len(stepPrefix)])
-				progressionLevel, err := strconv.Atoi(parsedStringProgression)
+				stepDir := filepath.Join(t.Dir, tFolderName)
 
-				if err != nil {
-					tracker.Log.Error(err)
+				if _, err := guardTrackDir(tracker.Fs, cfg, ancestors, stepDir); err != nil {
+					return t, false, err
+				}
+
+				// placeholder step folders have nothing for a runner to execute
+				if !hasRunnableContent(tracker.Fs, stepDir) {
+					if cfg.FailOnEmptySteps {
+						return t, false, fmt.Errorf("step %s has no runnable content in %s", stepID, stepDir)
+					}
+
+					tracker.Log.Warningf("Step %s skipped. No runnable content found in %s.", stepID, stepDir)
+					continue
 				}
 
 				if progressionLevel > highestProgressionLevel {
@@ -263,18 +581,72 @@ func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, d
 				step := config.Step{
 					ProgressionLevel: progressionLevel,
 					Name:             stepName,
-					Dir:              filepath.Join(t.Dir, tFolderName),
+					Dir:              stepDir,
 					DeployConfig:     cfg,
 					TrackName:        t.Name,
 					ID:               stepID,
 				}
 
-				step.TestsExist = fileExists(tracker.Fs, filepath.Join(step.Dir, "tests/tests.test"))
+				// when deploying since the last success, steps are not executed in the regions their current content was
+				// last successfully applied to, passing on the output variables recorded then instead
+				if cfg.SinceLastSuccess {
+					step.ContentHash, err = HashStepDir(tracker.Fs, stepDir)
+					if err != nil {
+						return t, false, err
+					}
+
+					step.UnchangedOutputs = manifest.Unchanged(step)
+					if len(step.UnchangedOutputs) > 0 {
+						tracker.Log.Infof("Step %s is unchanged since its last successful apply to %d region(s), it will not be executed in them.", stepID, len(step.UnchangedOutputs))
+					}
+				}
+
+				stepConfig, err := config.ReadStepConfig(tracker.Fs, step.Dir)
+				if err != nil {
+					return t, false, err
+				}
+				step.Config = stepConfig.ForRegionGroup(cfg.RegionGroup)
+
+				if err := step.Config.Validate(); err != nil {
+					return t, false, fmt.Errorf("step %s has an invalid configuration: %w", stepID, err)
+				}
+
+				// steps targeting another cloud service provider are not deployed to the current one
+				step.CSP = step.Config.CSP
+				if step.CSP != "" && cfg.CSP != "" && !strings.EqualFold(step.CSP, cfg.CSP) {
+					tracker.Log.Infof("Step %s skipped. Targets CSP %s while deploying to %s.", stepID, step.CSP, cfg.CSP)
+					continue
+				}
+
+				// the step's retries override the deployment's
+				if step.Config.RetryableFailureRetries > 0 {
+					step.DeployConfig.RetryableFailureRetries = step.Config.RetryableFailureRetries
+				}
+				if step.Config.RetryBackoff > 0 {
+					step.DeployConfig.RetryBackoff = step.Config.RetryBackoff
+				}
+
+				step.TestsExist = testsExist(tracker.Fs, filepath.Join(step.Dir, "tests"), step.Config.HasTests) || (step.Config.HasTests == nil && step.Config.TestCommand != "")
 				step.RegionalResourcesExist = exists(tracker.Fs, filepath.Join(step.Dir, "regional"))
-				step.Runner = steps.DetermineRunner(step)
+
+				if step.RegionalResourcesExist && !t.Config.IncludesRegionDeployType(config.RegionalRegionDeployType) {
+					tracker.Log.Warningf("Ignoring regional resources for step %s. Track is not configured for regional deployments.", stepID)
+					step.RegionalResourcesExist = false
+				}
+
+				// steps without primary resources are only deployed and destroyed in the regional pass
+				if step.RegionalResourcesExist && !hasRunnerFiles(tracker.Fs, step.Dir) {
+					step.RegionalOnly = true
+					step.TestsExist = false
+				}
+
+				step.Runner, err = steps.DetermineRunner(tracker.Fs, step)
+				if err != nil {
+					return t, false, err
+				}
 
 				if step.RegionalResourcesExist {
-					step.RegionalTestsExist = fileExists(tracker.Fs, filepath.Join(step.Dir, "regional", "tests/tests.test"))
+					step.RegionalTestsExist = testsExist(tracker.Fs, filepath.Join(step.Dir, "regional", "tests"), step.Config.HasRegionalTests) || (step.Config.HasRegionalTests == nil && step.Config.TestCommand != "")
 				}
 
 				tracker.Log.Infof("Adding Step %s. Tests Exist: %v. Regional Resources Exist: %v. Regional Tests Exist: %v.", stepID, step.TestsExist, step.RegionalResourcesExist, step.RegionalTestsExist)
@@ -303,6 +675,58 @@ func (tracker DirectoryBasedTracker) readTrack(cfg config.Config, name string, d
 	return t, true, nil
 }
 
+// guardTrackDir protects gathering from runaway recursion, returning the real path of dir once it is resolved through
+// any symlinks. ancestors are the real paths of the directories dir was reached from, starting with the tracks directory
+// at depth zero. An error is returned when dir is deeper than cfg.MaxTrackDepth, or when it is part of a symlink loop or
+// resolves to one of its ancestors.
+func guardTrackDir(fs afero.Fs, cfg config.Config, ancestors []string, dir string) (string, error) {
+	maxDepth := cfg.MaxTrackDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxTrackDepth
+	}
+
+	if len(ancestors) > maxDepth {
+		return "", fmt.Errorf("%s exceeds the maximum track depth of %d", dir, maxDepth)
+	}
+
+	real := filepath.Clean(dir)
+
+	if supportsSymlinks(fs, dir) {
+		var err error
+		if real, err = filepath.EvalSymlinks(dir); err != nil {
+			if os.IsNotExist(err) {
+				return filepath.Abs(dir)
+			}
+
+			return "", fmt.Errorf("unable to resolve %s, it may be part of a symlink loop: %w", dir, err)
+		}
+	}
+
+	real, err := filepath.Abs(real)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ancestor := range ancestors {
+		if ancestor == real || strings.HasPrefix(ancestor, real+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s resolves to %s which contains it, this symlink cycle would be gathered endlessly", dir, real)
+		}
+	}
+
+	return real, nil
+}
+
+// supportsSymlinks reports whether dir may be a symlink, i.e. fs, or the filesystem it wraps, supports lstat
+func supportsSymlinks(fs afero.Fs, dir string) bool {
+	lstater, ok := fs.(afero.Lstater)
+	if !ok {
+		return false
+	}
+
+	_, lstatCalled, _ := lstater.LstatIfPossible(dir)
+	return lstatCalled
+}
+
 // fileExists checks if a file exists and is not a directory before we
 // try using it to prevent further errors.
 func fileExists(fs afero.Fs, filename string) bool {
@@ -313,6 +737,37 @@ func fileExists(fs afero.Fs, filename string) bool {
 	return !info.IsDir()
 }
 
+// testsExist checks if a tests directory contains a compiled tests.test binary, or test sources the runner compiles
+// or runs at execution (go test files or bats scripts), unless overridden by the step's configuration
+func testsExist(fs afero.Fs, testsDir string, override *bool) bool {
+	if override != nil {
+		return *override
+	}
+
+	if fileExists(fs, filepath.Join(testsDir, "tests.test")) {
+		return true
+	}
+
+	for _, pattern := range []string{"*_test.go", "*.bats"} {
+		if matches, _ := afero.Glob(fs, filepath.Join(testsDir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasRunnableContent checks if a step directory, or its regional directory, contains anything a runner can execute
+func hasRunnableContent(fs afero.Fs, stepDir string) bool {
+	return hasRunnerFiles(fs, stepDir) || hasRunnerFiles(fs, filepath.Join(stepDir, "regional"))
+}
+
+// hasRunnerFiles checks if a directory directly contains files a registered runner detects, e.g. terraform configuration
+func hasRunnerFiles(fs afero.Fs, dir string) bool {
+	_, files := steps.DetectRunner(fs, dir)
+	return len(files) > 0
+}
+
 // isEmpty checks if a file or dir exists and is not empty
 func exists(fs afero.Fs, filename string) bool {
 	info, err := afero.IsEmpty(fs, filename)
@@ -322,147 +777,282 @@ func exists(fs afero.Fs, filename string) bool {
 // ExecuteTracks executes all tracks in parallel.
 // If a _pretrack exists, this is executed before
 // all other tracks.
-func (tracker DirectoryBasedTracker) ExecuteTracks(cfg config.Config) (output Stage) {
+func (tracker DirectoryBasedTracker) ExecuteTracks(ctx context.Context, cfg config.Config) (output Stage) {
+	start := time.Now()
+	defer func() {
+		output.Duration = time.Since(start)
+	}()
+
 	output.Tracks = map[string]Track{}
-	var tracks = tracker.GatherTracks(cfg) // **All** tracks
-	var parallelTracks []Track             // Tracks that should be executed in parallel
+	var parallelTracks []Track // Tracks that should be executed in parallel
 
-	// Pre track
-	var preTrackExists bool
-	var preTrack Track
+	// the hooks reach each step through the context the step is executed with
+	ctx = WithStepHooks(ctx, tracker.Hooks...)
 
-	for _, t := range tracks {
-		output.Tracks[t.Name] = t
-		if t.IsPreTrack {
-			preTrackExists = true
-			preTrack = t
-		} else {
-			parallelTracks = append(parallelTracks, t)
-		}
+	// fail fast on typos in region names rather than failing each step against the provider
+	if invalid := invalidRegions(cfg, nil); len(invalid) > 0 {
+		output.Err = fmt.Errorf("regions %v are not allowed regions %v", invalid, cfg.AllowedRegions)
+		tracker.Log.WithError(output.Err).Error("Tracks: Invalid regions, no tracks will be executed")
+		return
 	}
 
-	// Execute _pretrack if it exists
-	if preTrackExists {
-		tracker.Log.Debug("Pre-track execution starting")
+	// a destroy preview plans the tracks to gather their outputs, then plans destroying them, without applying either
+	if cfg.DestroyPreview {
+		cfg.DryRun = true
+		cfg.SelfDestroy = true
+	}
 
-		preTrackChan := make(chan Output)
-		preTrackExecution := Execution{
-			Logger:                              tracker.Log,
-			Fs:                                  tracker.Fs,
-			Output:                              ExecutionOutput{},
-			DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{},
-		}
-		go DeployTrack(preTrackExecution, cfg, preTrack, preTrackChan)
-		// Wait for the track to contain an item,
-		// indicating the track has completed.
-		preTrackOutput := <-preTrackChan
-		preTrack.Output = preTrackOutput
-		output.Tracks[preTrack.Name] = preTrack
-		tracker.Log.Debug("Pre-track finished")
-		// If any of the pretrack's executions has a step failure,
-		// the pretrack is considered failed
-		// so we cannot continue with the other tracks
-		for _, exec := range preTrackOutput.Executions {
-			for _, step := range exec.Output.Steps {
-				if step.Output.Status == config.Fail {
-					tracker.Log.Error("Pre-track failed, subsequent tracks will not be executed")
-					// Mark all other tracks as skipped
-					for _, track := range output.Tracks {
-						if track.Name != PRE_TRACK_NAME {
-							track.Skipped = true
-							output.Tracks[track.Name] = track
-						}
-					}
-					return
-				}
-			}
-		}
+	tracks, err := tracker.GatherTracks(cfg) // **All** tracks
+	if err != nil {
+		tracker.Log.WithError(err).Error("Tracks: Unable to gather tracks, no tracks will be executed")
+		output.Err = err
+		return
 	}
 
-	// Execute non pre/post tracks in parallel
-	numParallelTracks := len(parallelTracks)
-	parallelTrackChan := make(chan Output)
+	// tracks may override the regions they are deployed to
+	if invalid := invalidRegions(cfg, tracks); len(invalid) > 0 {
+		output.Err = fmt.Errorf("regions %v are not allowed regions %v", invalid, cfg.AllowedRegions)
+		tracker.Log.WithError(output.Err).Error("Tracks: Invalid track regions, no tracks will be executed")
+		return
+	}
 
-	// execute all tracks concurrently
-	// within ExecuteDeployTrack, track result will be added to trackChan feeding next loop
-	for _, t := range parallelTracks {
-		execution := Execution{
-			Logger:                              tracker.Log,
-			Fs:                                  tracker.Fs,
-			Output:                              ExecutionOutput{},
-			DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{},
-		}
-		// If there is a pretrack, add its outputs
-		// to the execution so they are available.
-		if preTrackExists {
-			execution.PreTrackOutput = &preTrack.Output
+	if undeployed := undeployedDestroyRegions(cfg, tracks); len(undeployed) > 0 {
+		output.Err = fmt.Errorf("destroy regions %v are not regions the tracks are deployed to", undeployed)
+		tracker.Log.WithError(output.Err).Error("Tracks: Invalid destroy regions, no tracks will be executed")
+		return
+	}
+
+	// a cycle would deadlock the dependency waves, reject it before anything is executed
+	if cycle := dependencyCycle(tracks); cycle != nil {
+		output.Err = fmt.Errorf("track dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+		tracker.Log.WithError(output.Err).Error("Tracks: Invalid track dependencies, no tracks will be executed")
+
+		for _, t := range tracks {
+			t.Skipped = true
+			t.SkipReason = SkipReasonDependencyCycle
+			output.Tracks[t.Name] = t
 		}
-		go DeployTrack(execution, cfg, t, parallelTrackChan)
+		return
 	}
 
-	// wait for all executions to finish (this loop matches above range)
-	for tExecution := 0; tExecution < numParallelTracks; tExecution++ {
-		// waiting to append <-trackChan Track N times will inherently wait for all above executions to finish
-		tOutput := <-parallelTrackChan
-		if t, ok := output.Tracks[tOutput.Name]; ok {
-			// TODO: is it better to have a pointer for map value?
-			t.Output = tOutput
-			output.Tracks[tOutput.Name] = t
+	if cfg.EmitPlanJSON != "" {
+		if err := WriteExecutionPlan(tracker.Fs, cfg.EmitPlanJSON, NewExecutionPlan(cfg, tracks)); err != nil {
+			tracker.Log.WithError(err).Errorf("Unable to write the execution plan to %s", cfg.EmitPlanJSON)
 		}
 	}
 
-	// If SelfDestroy or Destroy is set (e.g. during PRs), destroy any resources created by the tracks
-	if cfg.SelfDestroy && !cfg.DryRun {
-		tracker.Log.Info("Executing destroy...")
-		trackDestroyChan := make(chan Output)
+	if cfg.SinceLastSuccess && !cfg.DryRun && !cfg.SelfDestroy && !cfg.DestroyOnly {
+		defer tracker.recordManifest(cfg, &output)
+	}
 
-		for _, t := range parallelTracks {
-			executionStepOutputVariables := map[string]map[string]map[string]string{}
+	if len(cfg.PrintOutputs) > 0 {
+		defer tracker.printOutputs(cfg, &output)
+	}
 
-			for _, exec := range output.Tracks[t.Name].Output.Executions {
-				executionStepOutputVariables[fmt.Sprintf("%s-%s", exec.RegionDeployType, exec.Region)] = exec.Output.StepOutputVariables
+	if cfg.AfterAllCommand != "" {
+		defer func() {
+			if resp, err := RunDeploymentCommand(ctx, tracker.Log, cfg, cfg.AfterAllCommand); err != nil {
+				tracker.Log.WithError(err).Errorf("After all command failed:\n%s", resp)
 			}
+		}()
+	}
 
-			if tracker.Log.Level == logrus.DebugLevel {
-				jsonBytes, _ := json.Marshal(executionStepOutputVariables)
-
-				tracker.Log.Debugf("OUTPUT VARS: %s", string(jsonBytes))
-			}
+	if cfg.BeforeAllCommand != "" {
+		if resp, err := RunDeploymentCommand(ctx, tracker.Log, cfg, cfg.BeforeAllCommand); err != nil {
+			tracker.Log.WithError(err).Errorf("Before all command failed, tracks will not be executed:\n%s", resp)
+			output.Err = fmt.Errorf("before all command failed: %w", err)
 
-			execution := Execution{
-				Logger:                              tracker.Log,
-				Fs:                                  tracker.Fs,
-				Output:                              ExecutionOutput{},
-				DefaultExecutionStepOutputVariables: executionStepOutputVariables,
-			}
-			// If there is a pretrack, add its outputs
-			// to the execution so they are available.
-			if preTrackExists {
-				execution.PreTrackOutput = &preTrack.Output
+			for _, t := range tracks {
+				t.Skipped = true
+				t.SkipReason = SkipReasonBeforeAllCommandFailed
+				output.Tracks[t.Name] = t
 			}
-			go DestroyTrack(execution, cfg, t, trackDestroyChan)
+			return
 		}
+	}
 
-		// wait for all executions to finish (this loop matches above range)
-		for range parallelTracks {
-			// waiting to append <-trackDestroyChan Track N times will inherently wait for all above executions to finish
-			tDestroyOutout := <-trackDestroyChan
+	// Pre and post tracks
+	var preTrackExists, postTrackExists bool
+	var preTrack, postTrack Track
 
-			if t, ok := output.Tracks[tDestroyOutout.Name]; ok {
-				// TODO: is it better to have a pointer for map value?
-				t.DestroyOutput = tDestroyOutout
-				output.Tracks[tDestroyOutout.Name] = t
-			}
+	for _, t := range tracks {
+		output.Tracks[t.Name] = t
+		if t.IsPreTrack {
+			preTrackExists = true
+			preTrack = t
+		} else if t.IsPostTrack {
+			postTrackExists = true
+			postTrack = t
+		} else {
+			parallelTracks = append(parallelTracks, t)
 		}
+	}
 
-		// Destroy _pretrack if it exists
-		if preTrackExists {
-			tracker.Log.Debug("Pre-track destroying")
-			executionStepOutputVariables := map[string]map[string]map[string]string{}
+	// The pretrack's outputs, available to the tracks executed after it
+	var preTrackOutput *Output
 
-			for _, exec := range output.Tracks[preTrack.Name].Output.Executions {
-				executionStepOutputVariables[fmt.Sprintf("%s-%s", exec.RegionDeployType, exec.Region)] = exec.Output.StepOutputVariables
-			}
+	// a skipped pretrack is neither deployed nor destroyed, the tracks after it use its persisted outputs when they exist
+	if preTrackExists && cfg.SkipPreTrack {
+		tracker.Log.Info("Skipping the pre-track")
+		preTrackOutput = tracker.readPersistedPreTrackOutput(cfg)
+
+		preTrack.Skipped = true
+		preTrack.SkipReason = SkipReasonPreTrackSkipped
+		output.Tracks[preTrack.Name] = preTrack
+		preTrackExists = false
+	}
+
+	var executedStages []trackStage
+
+	trackLimiter := newLimiter(cfg.MaxParallelTracks)
+
+	// tracks independent of the pretrack are executed alongside it rather than waiting for its outputs
+	var independentTracks []Track
+	var independentTrackChan chan Output
+	collectIndependentTracks := func() {
+		for range independentTracks {
+			tOutput := <-independentTrackChan
+			if t, ok := output.Tracks[tOutput.Name]; ok {
+				t.Output = tOutput
+				output.Tracks[tOutput.Name] = t
+			}
+		}
+
+		if len(independentTracks) > 0 {
+			// destroyed after the tracks depending on the pretrack, mirroring the deploy order
+			executedStages = append([]trackStage{{Tracks: independentTracks}}, executedStages...)
+		}
+	}
+
+	// Execute _posttrack if it exists, once every other track has finished
+	var postTrackExecuted bool
+	executePostTrack := func() {
+		if !postTrackExists {
+			return
+		}
+
+		upstreamTrackOutputs := map[string]Output{}
+		var upstreamFailed bool
+		for _, t := range output.Tracks {
+			if t.IsPreTrack || t.IsPostTrack {
+				continue
+			}
+
+			if hasFailedSteps(t.Output) || (t.Skipped && t.SkipReason != SkipReasonNotInTrackOrder) {
+				upstreamFailed = true
+			}
+			upstreamTrackOutputs[t.Name] = t.Output
+		}
+
+		if preTrackExists && hasFailedSteps(preTrack.Output) {
+			upstreamFailed = true
+		}
+
+		if upstreamFailed && !postTrack.Config.RunOnFailure {
+			tracker.Log.Error("Tracks failed, the post-track will not be executed")
+			postTrack.Skipped = true
+			postTrack.SkipReason = SkipReasonUpstreamTrackFailed
+			output.Tracks[postTrack.Name] = postTrack
+			return
+		}
+
+		tracker.Log.Debug("Post-track execution starting")
+
+		postTrackChan := make(chan Output)
+		postTrackExecution := Execution{
+			Logger:                              tracker.Log,
+			Fs:                                  tracker.Fs,
+			Output:                              ExecutionOutput{},
+			DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{},
+			UpstreamTrackOutputs:                upstreamTrackOutputs,
+		}
+		postTrackExecution.PreTrackOutput = preTrackOutput
+		trackLimiter.start(ctx, DeployTrack, postTrackExecution, cfg, postTrack, postTrackChan)
+		postTrack.Output = <-postTrackChan
+		output.Tracks[postTrack.Name] = postTrack
+		postTrackExecuted = true
+		tracker.Log.Debug("Post-track finished")
+	}
+
+	// Destroy the executed tracks in the reverse order they were executed in
+	destroyTracks := func() {
+		tracker.Log.Info("Executing destroy...")
+
+		// Destroy _posttrack first, it was executed after every other track
+		if postTrackExecuted {
+			tracker.Log.Debug("Post-track destroying")
+			executionStepOutputVariables := map[string]map[string]map[string]string{}
+
+			for _, exec := range output.Tracks[postTrack.Name].Output.Executions {
+				executionStepOutputVariables[fmt.Sprintf("%s-%s", exec.RegionDeployType, exec.Region)] = exec.Output.StepOutputVariables
+			}
+
+			destroyPostTrackChan := make(chan Output)
+			postTrackDestroyExecution := Execution{
+				Logger:                              tracker.Log,
+				Fs:                                  tracker.Fs,
+				Output:                              ExecutionOutput{},
+				DefaultExecutionStepOutputVariables: executionStepOutputVariables,
+			}
+			postTrackDestroyExecution.PreTrackOutput = preTrackOutput
+			trackLimiter.start(ctx, DestroyTrack, postTrackDestroyExecution, cfg, postTrack, destroyPostTrackChan)
+			postTrackDestroyOutput := <-destroyPostTrackChan
+			postTrack.DestroyOutput = postTrackDestroyOutput
+			output.Tracks[postTrack.Name] = postTrack
+			tracker.Log.Debug("Post-track destroy finished")
+		}
+
+		// destroy stages in reverse, tracks in later stages may depend on those in earlier stages
+		for i := len(executedStages) - 1; i >= 0; i-- {
+			trackDestroyChan := make(chan Output)
+			stageTracks := executedStages[i].Tracks
+
+			for _, t := range stageTracks {
+				executionStepOutputVariables := map[string]map[string]map[string]string{}
+
+				for _, exec := range output.Tracks[t.Name].Output.Executions {
+					executionStepOutputVariables[fmt.Sprintf("%s-%s", exec.RegionDeployType, exec.Region)] = exec.Output.StepOutputVariables
+				}
+
+				if tracker.Log.Level == logrus.DebugLevel {
+					jsonBytes, _ := json.Marshal(executionStepOutputVariables)
+
+					tracker.Log.Debugf("OUTPUT VARS: %s", string(jsonBytes))
+				}
+
+				execution := Execution{
+					Logger:                              tracker.Log,
+					Fs:                                  tracker.Fs,
+					Output:                              ExecutionOutput{},
+					DefaultExecutionStepOutputVariables: executionStepOutputVariables,
+				}
+				// If there is a pretrack, add its outputs
+				// to the execution so they are available.
+				execution.PreTrackOutput = preTrackOutput
+				trackLimiter.start(ctx, DestroyTrack, execution, cfg, t, trackDestroyChan)
+			}
+
+			// wait for all executions to finish (this loop matches above range)
+			for range stageTracks {
+				// waiting to append <-trackDestroyChan Track N times will inherently wait for all above executions to finish
+				tDestroyOutout := <-trackDestroyChan
+
+				if t, ok := output.Tracks[tDestroyOutout.Name]; ok {
+					// TODO: is it better to have a pointer for map value?
+					t.DestroyOutput = tDestroyOutout
+					output.Tracks[tDestroyOutout.Name] = t
+				}
+			}
+		}
+
+		// Destroy _pretrack if it exists
+		if preTrackExists {
+			tracker.Log.Debug("Pre-track destroying")
+			executionStepOutputVariables := map[string]map[string]map[string]string{}
+
+			for _, exec := range output.Tracks[preTrack.Name].Output.Executions {
+				executionStepOutputVariables[fmt.Sprintf("%s-%s", exec.RegionDeployType, exec.Region)] = exec.Output.StepOutputVariables
+			}
 
 			destroyPreTrackChan := make(chan Output)
 			preTrackDestroyExecution := Execution{
@@ -472,7 +1062,7 @@ func (tracker DirectoryBasedTracker) ExecuteTracks(cfg config.Config) (output St
 				DefaultExecutionStepOutputVariables: executionStepOutputVariables,
 				PreTrackOutput:                      &preTrack.Output,
 			}
-			go DestroyTrack(preTrackDestroyExecution, cfg, preTrack, destroyPreTrackChan)
+			trackLimiter.start(ctx, DestroyTrack, preTrackDestroyExecution, cfg, preTrack, destroyPreTrackChan)
 			// Wait for the track to contain an item,
 			// indicating the track has been destroyed.
 			preTrackDestroyOutput := <-destroyPreTrackChan
@@ -485,17 +1075,552 @@ func (tracker DirectoryBasedTracker) ExecuteTracks(cfg config.Config) (output St
 		}
 	}
 
+	// Destroy the tracks deployed by an earlier deployment without deploying them, using its persisted step outputs
+	if cfg.DestroyOnly {
+		tracker.Log.Infof("Destroying without deploying, using the step outputs persisted in %s", cfg.OutputVariablesDir)
+
+		for name, t := range output.Tracks {
+			if t.Skipped {
+				continue
+			}
+
+			persisted, err := ReadPersistedTrackOutput(tracker.Fs, cfg.OutputVariablesDir, t.Name)
+			if err != nil {
+				tracker.Log.WithError(err).Warnf("Unable to read the step outputs persisted for track %s, destroying it without them", t.Name)
+				continue
+			} else if persisted == nil {
+				tracker.Log.Warnf("No step outputs are persisted for track %s, destroying it without them", t.Name)
+				continue
+			}
+
+			t.Output = *persisted
+			output.Tracks[name] = t
+		}
+
+		if preTrackExists {
+			preTrack = output.Tracks[preTrack.Name]
+			preTrackOutput = &preTrack.Output
+			parallelTracks, independentTracks = splitPreTrackIndependentTracks(parallelTracks)
+		}
+
+		if postTrackExists {
+			postTrack = output.Tracks[postTrack.Name]
+			postTrackExecuted = true
+		}
+
+		// mirror the order the tracks are deployed in, so they are destroyed in reverse
+		trackStages := groupTracksByStage(cfg, parallelTracks)
+		if len(cfg.TrackOrder) > 0 {
+			var unlisted []Track
+			trackStages, unlisted = groupTracksByOrder(cfg, parallelTracks)
+
+			for _, t := range unlisted {
+				t.Skipped = true
+				t.SkipReason = SkipReasonNotInTrackOrder
+				output.Tracks[t.Name] = t
+			}
+		}
+
+		for _, stage := range trackStages {
+			for _, wave := range groupTracksByDependencies(stage) {
+				if len(wave.Tracks) > 0 {
+					executedStages = append(executedStages, trackStage{Name: wave.Name, Tracks: wave.Tracks})
+				}
+			}
+		}
+
+		if len(independentTracks) > 0 {
+			executedStages = append([]trackStage{{Tracks: independentTracks}}, executedStages...)
+		}
+
+		if !cfg.DryRun || cfg.DestroyPreview {
+			destroyTracks()
+		}
+
+		return
+	}
+
+	// Execute _pretrack if it exists
+	if preTrackExists {
+		parallelTracks, independentTracks = splitPreTrackIndependentTracks(parallelTracks)
+		// buffered so the independent tracks complete without waiting for the pretrack to be collected
+		independentTrackChan = make(chan Output, len(independentTracks))
+
+		for _, t := range independentTracks {
+			tracker.Log.Infof("Track %s is independent of the pretrack, executing alongside it", t.Name)
+
+			trackLimiter.start(ctx, DeployTrack, Execution{
+				Logger:                              tracker.Log,
+				Fs:                                  tracker.Fs,
+				Output:                              ExecutionOutput{},
+				DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{},
+			}, cfg, t, independentTrackChan)
+		}
+
+		tracker.Log.Debug("Pre-track execution starting")
+
+		preTrackChan := make(chan Output)
+		preTrackExecution := Execution{
+			Logger:                              tracker.Log,
+			Fs:                                  tracker.Fs,
+			Output:                              ExecutionOutput{},
+			DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{},
+		}
+		trackLimiter.start(ctx, DeployTrack, preTrackExecution, cfg, preTrack, preTrackChan)
+		// Wait for the track to contain an item,
+		// indicating the track has completed.
+		preTrack.Output = <-preTrackChan
+		preTrackOutput = &preTrack.Output
+		output.Tracks[preTrack.Name] = preTrack
+		tracker.Log.Debug("Pre-track finished")
+		// If any of the pretrack's executions has a step failure,
+		// the pretrack is considered failed
+		// so we cannot continue with the other tracks
+		if hasFailedSteps(preTrack.Output) {
+			tracker.Log.Error("Pre-track failed, subsequent tracks will not be executed")
+			collectIndependentTracks()
+
+			// Mark the tracks depending on the pretrack as skipped
+			for _, track := range parallelTracks {
+				track.Skipped = true
+				track.SkipReason = SkipReasonPreTrackFailed
+				output.Tracks[track.Name] = track
+			}
+
+			executePostTrack()
+			return
+		}
+	}
+
+	// Execute non pre/post tracks stage by stage, tracks within a stage are executed in parallel
+	trackStages := groupTracksByStage(cfg, parallelTracks)
+
+	if len(cfg.TrackOrder) > 0 {
+		var unlisted []Track
+		trackStages, unlisted = groupTracksByOrder(cfg, parallelTracks)
+
+		for _, t := range unlisted {
+			tracker.Log.Warnf("Track %s is not listed in the track order, skipping", t.Name)
+			t.Skipped = true
+			t.SkipReason = SkipReasonNotInTrackOrder
+			output.Tracks[t.Name] = t
+		}
+	}
+
+	for i, stage := range trackStages {
+		if stage.Name != "" {
+			tracker.Log.Infof("Stage %s execution starting", stage.Name)
+		}
+
+		// tracks depending on other tracks in the stage are executed in a later wave than those tracks
+		for _, wave := range groupTracksByDependencies(stage) {
+			var waveTracks []Track
+			for _, t := range wave.Tracks {
+				if failed := failedDependencies(output, t); len(failed) > 0 {
+					tracker.Log.Errorf("Track %s depends on failed tracks %v, skipping", t.Name, failed)
+					track := output.Tracks[t.Name]
+					track.Skipped = true
+					track.SkipReason = SkipReasonDependencyFailed
+					output.Tracks[t.Name] = track
+					continue
+				}
+
+				waveTracks = append(waveTracks, t)
+			}
+
+			numParallelTracks := len(waveTracks)
+			parallelTrackChan := make(chan Output)
+
+			// execute all tracks in the wave concurrently
+			// within ExecuteDeployTrack, track result will be added to trackChan feeding next loop
+			for _, t := range waveTracks {
+				execution := Execution{
+					Logger:                              tracker.Log,
+					Fs:                                  tracker.Fs,
+					Output:                              ExecutionOutput{},
+					DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{},
+				}
+				// If there is a pretrack, add its outputs
+				// to the execution so they are available.
+				execution.PreTrackOutput = preTrackOutput
+				trackLimiter.start(ctx, DeployTrack, execution, cfg, t, parallelTrackChan)
+			}
+
+			// wait for all executions to finish (this loop matches above range)
+			for tExecution := 0; tExecution < numParallelTracks; tExecution++ {
+				// waiting to append <-trackChan Track N times will inherently wait for all above executions to finish
+				tOutput := <-parallelTrackChan
+				if t, ok := output.Tracks[tOutput.Name]; ok {
+					// TODO: is it better to have a pointer for map value?
+					t.Output = tOutput
+					output.Tracks[tOutput.Name] = t
+				}
+			}
+
+			// waves are destroyed in reverse, after the tracks depending on them
+			if len(waveTracks) > 0 {
+				executedStages = append(executedStages, trackStage{Name: wave.Name, Tracks: waveTracks})
+			}
+		}
+
+		// later stages build on earlier ones, so a failed stage skips the remaining stages
+		stageFailed := false
+		for _, t := range stage.Tracks {
+			if hasFailedSteps(output.Tracks[t.Name].Output) {
+				stageFailed = true
+			}
+		}
+
+		if stageFailed && i < len(trackStages)-1 {
+			tracker.Log.Errorf("Stage %s failed, subsequent stages will not be executed", stage.Name)
+			for _, skippedStage := range trackStages[i+1:] {
+				for _, t := range skippedStage.Tracks {
+					track := output.Tracks[t.Name]
+					track.Skipped = true
+					track.SkipReason = SkipReasonPreviousStageFailed
+					output.Tracks[t.Name] = track
+				}
+			}
+			break
+		}
+	}
+
+	collectIndependentTracks()
+	executePostTrack()
+
+	// If SelfDestroy or Destroy is set (e.g. during PRs), destroy any resources created by the tracks
+	if cfg.SelfDestroy && (!cfg.DryRun || cfg.DestroyPreview) {
+		destroyTracks()
+	}
+
 	return
 }
 
+// limiter limits the number of tracks or steps executing concurrently, a nil limiter does not limit them
+type limiter chan struct{}
+
+// newLimiter limits the executions running concurrently to max, zero or less is unlimited
+func newLimiter(max int) limiter {
+	if max <= 0 {
+		return nil
+	}
+
+	return make(limiter, max)
+}
+
+// acquire blocks until fewer than the maximum executions are running
+func (l limiter) acquire() {
+	if l != nil {
+		l <- struct{}{}
+	}
+}
+
+// release allows another execution to run
+func (l limiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// start executes the track in the background once fewer than the maximum tracks are executing and the deployment is
+// not paused
+func (l limiter) start(ctx context.Context, execute ExecuteTrackFunc, execution Execution, cfg config.Config, t Track, out chan<- Output) {
+	go func() {
+		l.acquire()
+
+		// buffered so the slot is released before waiting on the output to be received
+		trackOut := make(chan Output, 1)
+
+		// a track cancelled while paused is still executed with the cancelled ctx, cancelling each of its steps
+		if err := DeploymentPause.WaitUntilResumed(ctx); err != nil {
+			execution.Logger.WithField("track", t.Name).WithError(err).Warn("Deployment was cancelled while paused, cancelling track")
+		}
+
+		if cfg.TrackTimeout <= 0 {
+			execute(ctx, execution, cfg, t, trackOut)
+		} else {
+			executeWithTimeout(ctx, execute, execution, cfg, t, trackOut)
+		}
+
+		l.release()
+
+		out <- <-trackOut
+	}()
+}
+
+// executeWithTimeout fails the track once it has executed for longer than cfg.TrackTimeout, cancelling its steps. A
+// cancelled track's executions are given cfg.ShutdownGracePeriod to stop before their results are abandoned
+func executeWithTimeout(ctx context.Context, execute ExecuteTrackFunc, execution Execution, cfg config.Config, t Track, out chan<- Output) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.TrackTimeout)
+	defer cancel()
+
+	// buffered so an abandoned execution can still complete
+	trackOut := make(chan Output, 1)
+	go execute(ctx, execution, cfg, t, trackOut)
+
+	select {
+	case output := <-trackOut:
+		out <- output
+	case <-ctx.Done():
+		err := fmt.Errorf("track %s timed out after %s", t.Name, cfg.TrackTimeout)
+		if ctx.Err() == context.Canceled {
+			err = fmt.Errorf("track %s was cancelled", t.Name)
+		}
+		logger := execution.Logger.WithField("track", t.Name)
+		logger.WithError(err).Error("Track did not complete, waiting for its executions to stop")
+
+		// runners are interrupted on cancellation, give them the chance to exit before the track's slot is released
+		output := Output{Name: t.Name}
+		select {
+		case output = <-trackOut:
+			// keep what the track completed before it stopped, e.g. its executions and output variables
+		case <-time.After(cfg.ShutdownGracePeriod):
+			logger.Warnf("Track did not stop within %s, abandoning its executions", cfg.ShutdownGracePeriod)
+		}
+
+		output.Err = err
+		out <- output
+	}
+}
+
+// splitPreTrackIndependentTracks separates the tracks configured as independent of the pretrack from those depending on it
+func splitPreTrackIndependentTracks(tracks []Track) (dependent []Track, independent []Track) {
+	for _, t := range tracks {
+		if t.Config.IndependentOfPreTrack {
+			independent = append(independent, t)
+		} else {
+			dependent = append(dependent, t)
+		}
+	}
+
+	return
+}
+
+// trackStage is a group of tracks executed in parallel, all stages are executed sequentially
+type trackStage struct {
+	Name   string
+	Tracks []Track
+}
+
+// groupTracksByStage groups tracks by their configured stage in the order of cfg.Stages, tracks without a stage are
+// executed in a final unnamed stage
+func groupTracksByStage(cfg config.Config, tracks []Track) (stages []trackStage) {
+	for _, name := range cfg.Stages {
+		stage := trackStage{Name: name}
+		for _, t := range tracks {
+			if strings.EqualFold(t.Config.Stage, name) {
+				stage.Tracks = append(stage.Tracks, t)
+			}
+		}
+
+		if len(stage.Tracks) > 0 {
+			stages = append(stages, stage)
+		}
+	}
+
+	unstaged := trackStage{}
+	for _, t := range tracks {
+		if t.Config.Stage == "" {
+			unstaged.Tracks = append(unstaged.Tracks, t)
+		}
+	}
+
+	if len(unstaged.Tracks) > 0 {
+		stages = append(stages, unstaged)
+	}
+
+	return
+}
+
+// groupTracksByOrder groups each track listed in cfg.TrackOrder into its own stage so tracks are executed one at a
+// time in the listed order. Unlisted tracks are executed in a final unnamed stage, or returned as excluded when
+// cfg.TrackOrderExcludeUnlisted is set
+func groupTracksByOrder(cfg config.Config, tracks []Track) (stages []trackStage, excluded []Track) {
+	listed := map[string]bool{}
+
+	for _, name := range cfg.TrackOrder {
+		for _, t := range tracks {
+			if strings.EqualFold(t.Name, name) && !listed[t.Name] {
+				listed[t.Name] = true
+				stages = append(stages, trackStage{Name: t.Name, Tracks: []Track{t}})
+			}
+		}
+	}
+
+	unlisted := trackStage{}
+	for _, t := range tracks {
+		if listed[t.Name] {
+			continue
+		}
+
+		if cfg.TrackOrderExcludeUnlisted {
+			excluded = append(excluded, t)
+		} else {
+			unlisted.Tracks = append(unlisted.Tracks, t)
+		}
+	}
+
+	if len(unlisted.Tracks) > 0 {
+		stages = append(stages, unlisted)
+	}
+
+	return
+}
+
+// hasFailedSteps reports whether the track failed or any of the track's executions has a step failure
+func hasFailedSteps(output Output) bool {
+	if output.Err != nil {
+		return true
+	}
+
+	for _, exec := range output.Executions {
+		for _, step := range exec.Output.Steps {
+			if step.Output.Status == config.Fail || step.Output.Status == config.Cancelled {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// regionSucceeded reports whether every step of the region execution deployed without failing or being skipped
+func regionSucceeded(exec RegionExecution) bool {
+	for _, step := range exec.Output.Steps {
+		if step.Output.Status == config.Fail || step.Output.Status == config.Skipped || step.Output.Status == config.Cancelled {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unchangedStepOutput is the output of a step not executed in the region as its content is unchanged since its last
+// successful apply there, with the output variables recorded then
+func unchangedStepOutput(execution RegionExecution, s config.Step, vars map[string]string) config.StepOutput {
+	outputVariables := map[string]interface{}{}
+	for name, value := range vars {
+		outputVariables[name] = value
+	}
+
+	return config.StepOutput{
+		Status:           config.Na,
+		RegionDeployType: execution.RegionDeployType,
+		Region:           execution.Region,
+		StepName:         s.Name,
+		OutputVariables:  outputVariables,
+	}
+}
+
+// isCancelled reports whether cancelled has been closed, a nil channel is never cancelled
+func isCancelled(cancelled <-chan struct{}) bool {
+	select {
+	case <-cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordManifest records the content hashes of steps that were successfully applied
+func (tracker DirectoryBasedTracker) recordManifest(cfg config.Config, output *Stage) {
+	manifest, err := ReadManifest(tracker.Fs, cfg.ManifestFile)
+	if err != nil {
+		tracker.Log.WithError(err).Errorf("Unable to read manifest %s, successful steps will not be recorded", cfg.ManifestFile)
+		return
+	}
+
+	manifest.RecordSuccessfulSteps(*output, time.Now())
+
+	if err := WriteManifest(tracker.Fs, cfg.ManifestFile, manifest); err != nil {
+		tracker.Log.WithError(err).Errorf("Unable to write manifest %s", cfg.ManifestFile)
+	}
+}
+
+// approveRegional pauses the track for approval of its regional deployments, primary failures are left to the
+// regional executions to skip rather than asking for approval
+func approveRegional(ctx context.Context, logger *logrus.Entry, cfg config.Config, t Track, primaryExecution RegionExecution, regions []string) bool {
+	if primaryExecution.Output.FailureCount > 0 {
+		return true
+	}
+
+	logger.Infof("Pausing for approval before regional deployments in %v.", regions)
+
+	approved, err := RequestApproval(ctx, logger, cfg, ApprovalRequest{
+		TrackName: t.Name,
+		Phase:     config.RegionalRegionDeployType.String(),
+		Regions:   regions,
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("Regional deployments were not approved, skipping regional deployments")
+		return false
+	}
+
+	if !approved {
+		logger.Warn("Regional deployments were denied, skipping regional deployments")
+		return false
+	}
+
+	logger.Info("Regional deployments approved")
+	return true
+}
+
+// RequestApprovalImpl runs cfg.ApprovalCommand, approving the request when it exits successfully and denying it otherwise.
+// The request is exposed to the command as RUNIAC_APPROVAL_TRACK, RUNIAC_APPROVAL_PHASE and RUNIAC_APPROVAL_REGIONS.
+// Cancelling ctx interrupts the command, denying the request.
+func RequestApprovalImpl(ctx context.Context, logger *logrus.Entry, cfg config.Config, request ApprovalRequest) (bool, error) {
+	if cfg.ApprovalCommand == "" {
+		return false, fmt.Errorf("track %s is paused before %s deployments but no approval command is configured", request.TrackName, request.Phase)
+	}
+
+	resp, err := shell.RunShellCommandAndGetOutput(shell.Command{
+		Command: "sh",
+		Args:    []string{"-c", strings.ReplaceAll(cfg.ApprovalCommand, "{run_id}", cfg.UniqueExternalExecutionID)},
+		Env: map[string]string{
+			"RUNIAC_RUN_ID":           cfg.UniqueExternalExecutionID,
+			"RUNIAC_ENVIRONMENT":      cfg.Environment,
+			"RUNIAC_APPROVAL_TRACK":   request.TrackName,
+			"RUNIAC_APPROVAL_PHASE":   request.Phase,
+			"RUNIAC_APPROVAL_REGIONS": strings.Join(request.Regions, ","),
+		},
+		Logger:              logger,
+		Context:             ctx,
+		NonInteractive:      true,
+		ShutdownGracePeriod: cfg.ShutdownGracePeriod,
+	})
+
+	if err != nil {
+		logger.WithError(err).Infof("Approval command denied the request:\n%s", resp)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// RunDeploymentCommandImpl runs a deployment-level command in a shell, replacing {run_id} with the
+// unique external execution id, which is also exposed as RUNIAC_RUN_ID alongside RUNIAC_ENVIRONMENT. Cancelling ctx
+// interrupts the command.
+func RunDeploymentCommandImpl(ctx context.Context, logger *logrus.Entry, cfg config.Config, command string) (string, error) {
+	return shell.RunShellCommandAndGetOutput(shell.Command{
+		Command:             "sh",
+		Args:                []string{"-c", strings.ReplaceAll(command, "{run_id}", cfg.UniqueExternalExecutionID)},
+		Env:                 map[string]string{"RUNIAC_RUN_ID": cfg.UniqueExternalExecutionID, "RUNIAC_ENVIRONMENT": cfg.Environment},
+		Logger:              logger,
+		Context:             ctx,
+		NonInteractive:      true,
+		ShutdownGracePeriod: cfg.ShutdownGracePeriod,
+	})
+}
+
 // Adds step outputs variables to the track output variables map
 // K = Step Name, V = map[StepOutputVarName: StepOutputVarValue]
-func AppendTrackOutput(trackOutputVariables map[string]map[string]string, output config.StepOutput) map[string]map[string]string {
-
-	key := output.StepName
+// Regional step outputs are keyed according to strategy, one of the config regional output key strategies
+func AppendTrackOutput(trackOutputVariables map[string]map[string]string, output config.StepOutput, strategy string) map[string]map[string]string {
 
-	if output.RegionDeployType == config.RegionalRegionDeployType {
-		key = fmt.Sprintf("%s-%s", key, output.RegionDeployType.String())
+	key, nested := trackOutputKey(output, strategy)
+	if nested {
+		return appendNestedTrackOutput(trackOutputVariables, output)
 	}
 
 	if trackOutputVariables[key] == nil {
@@ -509,21 +1634,166 @@ func AppendTrackOutput(trackOutputVariables map[string]map[string]string, output
 	return trackOutputVariables
 }
 
+// trackOutputKey is the key the step's output variables are added to the track output variables with, nested is true
+// when the regional outputs are nested in the step's primary outputs instead
+func trackOutputKey(output config.StepOutput, strategy string) (key string, nested bool) {
+	key = output.StepName
+
+	if output.RegionDeployType == config.RegionalRegionDeployType {
+		switch strategy {
+		case config.PrefixRegionalOutputKey:
+			key = fmt.Sprintf("%s-%s", output.RegionDeployType.String(), key)
+		case config.NestedRegionalOutputKey:
+			return key, true
+		default:
+			key = fmt.Sprintf("%s-%s", key, output.RegionDeployType.String())
+		}
+	}
+
+	return key, false
+}
+
+// mergeOutputVariables returns the track's output variables with the output variables of progression scoped steps added,
+// the track's output variables are returned unchanged when there are none to add
+func mergeOutputVariables(trackOutputVariables map[string]map[string]string, progressionOutputVariables map[string]map[string]string) map[string]map[string]string {
+	if len(progressionOutputVariables) == 0 {
+		return trackOutputVariables
+	}
+
+	merged := map[string]map[string]string{}
+	for _, vars := range []map[string]map[string]string{trackOutputVariables, progressionOutputVariables} {
+		for key, stepVars := range vars {
+			if merged[key] == nil {
+				merged[key] = map[string]string{}
+			}
+
+			for k, v := range stepVars {
+				merged[key][k] = v
+			}
+		}
+	}
+
+	return merged
+}
+
+// copyOutputVariables returns a copy of the output variables, which can be read while the original is updated
+func copyOutputVariables(outputVariables map[string]map[string]string) map[string]map[string]string {
+	copied := make(map[string]map[string]string, len(outputVariables))
+	for key, vars := range outputVariables {
+		copied[key] = make(map[string]string, len(vars))
+		for k, v := range vars {
+			copied[key][k] = v
+		}
+	}
+
+	return copied
+}
+
+// withExtraStepInputs merges the extra step inputs, keyed {step}-{output variable}, with the step output variables a
+// step receives. The extra step inputs override the step output variables they name, unless they are only defaults
+func withExtraStepInputs(stepOutputVariables map[string]map[string]string, extraStepInputs map[string]string, asDefaults bool) map[string]map[string]string {
+	if len(extraStepInputs) == 0 {
+		return stepOutputVariables
+	}
+
+	extra := map[string]map[string]string{}
+	for name, value := range extraStepInputs {
+		key, outVarName, ok := config.SplitExtraStepInput(name)
+		if !ok {
+			continue
+		}
+
+		appendOutputVariables(extra, key, map[string]string{outVarName: value})
+	}
+
+	if asDefaults {
+		return mergeOutputVariables(extra, stepOutputVariables)
+	}
+
+	return mergeOutputVariables(stepOutputVariables, extra)
+}
+
+// appendNestedTrackOutput adds a regional step's output variables as a single JSON encoded map, keyed as the
+// {regionDeployType} output of the step so steps receive it as {step}-regional
+func appendNestedTrackOutput(trackOutputVariables map[string]map[string]string, output config.StepOutput) map[string]map[string]string {
+	nested := map[string]string{}
+	for k, v := range output.OutputVariables {
+		nested[k] = terraform.OutputToString(v)
+	}
+
+	b, _ := json.Marshal(nested)
+
+	// the step's primary outputs are shared with the other regions, copy them rather than adding to them
+	vars := map[string]string{}
+	for k, v := range trackOutputVariables[output.StepName] {
+		vars[k] = v
+	}
+	vars[output.RegionDeployType.String()] = string(b)
+	trackOutputVariables[output.StepName] = vars
+
+	return trackOutputVariables
+}
+
+// LimitOutputValues truncates string output variable values larger than cfg.MaxOutputValueBytes, or drops them when
+// cfg.RejectOversizedOutputs is set, to protect against oversized values being copied to every region
+func LimitOutputValues(logger *logrus.Entry, cfg config.Config, outputVariables map[string]interface{}) map[string]interface{} {
+	if cfg.MaxOutputValueBytes <= 0 || outputVariables == nil {
+		return outputVariables
+	}
+
+	limited := make(map[string]interface{}, len(outputVariables))
+
+	for k, v := range outputVariables {
+		value := terraform.OutputToString(v)
+
+		if len(value) <= cfg.MaxOutputValueBytes {
+			limited[k] = v
+			continue
+		}
+
+		// truncating a structured value (list, map) would corrupt it, so it is always rejected
+		if _, isString := v.(string); cfg.RejectOversizedOutputs || !isString {
+			logger.Warnf("Rejecting output variable %s, its value is %d bytes which exceeds the %d byte limit", k, len(value), cfg.MaxOutputValueBytes)
+			continue
+		}
+
+		logger.Warnf("Truncating output variable %s, its value is %d bytes which exceeds the %d byte limit", k, len(value), cfg.MaxOutputValueBytes)
+		limited[k] = truncateUTF8(value, cfg.MaxOutputValueBytes)
+	}
+
+	return limited
+}
+
+// truncateUTF8 truncates s to at most max bytes without splitting a multi-byte character
+func truncateUTF8(s string, max int) string {
+	// back up to the start of the character that would be split
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+
+	return s[:max]
+}
+
+// AppendPreTrackOutputsToDefaultStepOutputVariables adds the step output variables of the pretrack's execution in the same
+// region to a track's default step output variables, namespaced as {pretrack}.{step}. They are also keyed as
+// pretrack-{step}, unless a different value is already set for the output variable
 func AppendPreTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariables map[string]map[string]string, preTrackOutput *Output, regionDeployType config.RegionDeployType, region string) map[string]map[string]string {
+	if defaultStepOutputVariables == nil {
+		defaultStepOutputVariables = map[string]map[string]string{}
+	}
+
 	for _, execution := range preTrackOutput.Executions {
 		if execution.RegionDeployType == regionDeployType && execution.Region == region {
 			for step, outputVarMap := range execution.Output.StepOutputVariables {
+				appendOutputVariables(defaultStepOutputVariables, NamespacedOutputKey(PRE_TRACK_NAME, step), outputVarMap)
+
+				key := fmt.Sprintf("pretrack-%s", step)
 				for outVarName, outVarVal := range outputVarMap {
-					key := fmt.Sprintf("pretrack-%s", step)
-
-					// Check if the key already exists
-					if _, ok := defaultStepOutputVariables[key]; ok {
-						defaultStepOutputVariables[key][outVarName] = outVarVal
-					} else {
-						defaultStepOutputVariables[key] = map[string]string{
-							outVarName: outVarVal,
-						}
+					if existing, ok := defaultStepOutputVariables[key][outVarName]; ok && existing != outVarVal {
+						continue
 					}
+
+					appendOutputVariables(defaultStepOutputVariables, key, map[string]string{outVarName: outVarVal})
 				}
 			}
 		}
@@ -532,8 +1802,272 @@ func AppendPreTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariable
 	return defaultStepOutputVariables
 }
 
+// AppendUpstreamTrackOutputsToDefaultStepOutputVariables adds the step output variables of the other tracks' executions in
+// the same region to the posttrack's default step output variables, namespaced as {track}.{step}. They are also keyed as
+// {track}-{step}, unless the steps of different tracks share the key, e.g. track a-b step c and track a step b-c
+func AppendUpstreamTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariables map[string]map[string]string, upstreamTrackOutputs map[string]Output, regionDeployType config.RegionDeployType, region string) map[string]map[string]string {
+	if defaultStepOutputVariables == nil {
+		defaultStepOutputVariables = map[string]map[string]string{}
+	}
+
+	// the tracks and steps sharing each un-namespaced key
+	keyed := map[string]map[string]bool{}
+	for trackName, trackOutput := range upstreamTrackOutputs {
+		for _, execution := range trackOutput.Executions {
+			if execution.RegionDeployType != regionDeployType || execution.Region != region {
+				continue
+			}
+
+			for step, outputVarMap := range execution.Output.StepOutputVariables {
+				key := fmt.Sprintf("%s-%s", trackName, step)
+				if keyed[key] == nil {
+					keyed[key] = map[string]bool{}
+				}
+				keyed[key][NamespacedOutputKey(trackName, step)] = true
+
+				appendOutputVariables(defaultStepOutputVariables, NamespacedOutputKey(trackName, step), outputVarMap)
+			}
+		}
+	}
+
+	for trackName, trackOutput := range upstreamTrackOutputs {
+		for _, execution := range trackOutput.Executions {
+			if execution.RegionDeployType != regionDeployType || execution.Region != region {
+				continue
+			}
+
+			for step, outputVarMap := range execution.Output.StepOutputVariables {
+				if key := fmt.Sprintf("%s-%s", trackName, step); len(keyed[key]) == 1 {
+					appendOutputVariables(defaultStepOutputVariables, key, outputVarMap)
+				}
+			}
+		}
+	}
+
+	return defaultStepOutputVariables
+}
+
+// NamespacedOutputKey is the key the output variables of another track's step are passed to a track with, {track}.{step}
+func NamespacedOutputKey(track string, step string) string {
+	return fmt.Sprintf("%s.%s", track, step)
+}
+
+// OutputVariable resolves a qualified output variable name, {track}.{step}.{name} for the steps of any track, including
+// the track's own, or {step}.{name} for the track's own steps
+func (o ExecutionOutput) OutputVariable(qualifiedName string) (string, bool) {
+	names := strings.SplitN(qualifiedName, ".", 3)
+
+	var key, name string
+	switch {
+	case len(names) == 2:
+		key, name = names[0], names[1]
+	case len(names) == 3 && names[0] == o.Name:
+		key, name = names[1], names[2]
+	case len(names) == 3:
+		key, name = NamespacedOutputKey(names[0], names[1]), names[2]
+	default:
+		return "", false
+	}
+
+	value, ok := o.StepOutputVariables[key][name]
+	return value, ok
+}
+
+// appendOutputVariables adds the output variables to the step output variables with key
+func appendOutputVariables(stepOutputVariables map[string]map[string]string, key string, outputVariables map[string]string) {
+	if stepOutputVariables[key] == nil {
+		stepOutputVariables[key] = map[string]string{}
+	}
+
+	for name, value := range outputVariables {
+		stepOutputVariables[key][name] = value
+	}
+}
+
+// regionsFromStepOutput reads the regional regions from the primary step output variable referenced by the track's
+// regional_regions_output. The value may be a JSON list, e.g. ["us-east-1","us-west-2"], or a comma separated list.
+func regionsFromStepOutput(trackConfig config.TrackConfig, primaryStepOutputVariables map[string]map[string]string) ([]string, error) {
+	step, name, err := trackConfig.RegionalRegionsOutputKey()
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := primaryStepOutputVariables[step][name]
+	if !ok {
+		return nil, fmt.Errorf("primary step %s did not produce output variable %s", step, name)
+	}
+
+	value = strings.TrimSpace(value)
+
+	var regions []string
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &regions); err != nil {
+			return nil, fmt.Errorf("output variable %s is not a list of regions: %w", trackConfig.RegionalRegionsOutput, err)
+		}
+	} else {
+		regions = strings.Split(value, ",")
+	}
+
+	targetRegions := []string{}
+	for _, region := range regions {
+		if region = strings.TrimSpace(region); region != "" {
+			targetRegions = append(targetRegions, region)
+		}
+	}
+
+	return targetRegions, nil
+}
+
+// deployedRegionalRegions are the regional regions a deployment recorded step outputs for, keyed as
+// {regionDeployType}-{region}
+func deployedRegionalRegions(executionStepOutputVariables map[string]map[string]map[string]string) []string {
+	prefix := fmt.Sprintf("%s-", config.RegionalRegionDeployType)
+
+	regions := []string{}
+	for key := range executionStepOutputVariables {
+		if strings.HasPrefix(key, prefix) {
+			regions = append(regions, strings.TrimPrefix(key, prefix))
+		}
+	}
+	sort.Strings(regions)
+
+	return regions
+}
+
+// sortExecutions orders region executions by completion independent criteria, primary first and then by region, keeping
+// results stable between deployments
+func sortExecutions(executions []RegionExecution) {
+	sort.SliceStable(executions, func(i, j int) bool {
+		a, b := executions[i], executions[j]
+		if a.RegionDeployType != b.RegionDeployType {
+			return a.RegionDeployType == config.PrimaryRegionDeployType
+		}
+		return a.Region < b.Region
+	})
+}
+
+// sortFailedSteps orders failed steps by name rather than completion, keeping results stable between deployments
+func sortFailedSteps(steps []config.Step) {
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Name < steps[j].Name })
+}
+
+// ReadOutputVariableFile reads the step output variables of a track's region execution written by
+// WriteOutputVariableFiles
+func ReadOutputVariableFile(fs afero.Fs, dir string, trackName string, regionDeployType config.RegionDeployType, region string) (map[string]map[string]string, error) {
+	bytes, err := afero.ReadFile(fs, filepath.Join(dir, trackName, fmt.Sprintf("%s-%s.json", regionDeployType, region)))
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]map[string]string{}
+	if err := json.Unmarshal(bytes, &vars); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// ReadPersistedTrackOutput reads the step output variables of each of the track's region executions written by
+// WriteOutputVariableFiles, returning nil when none were persisted
+func ReadPersistedTrackOutput(fs afero.Fs, dir string, trackName string) (*Output, error) {
+	files, err := afero.Glob(fs, filepath.Join(dir, trackName, "*.json"))
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	output := &Output{
+		Name:                       trackName,
+		Executions:                 []RegionExecution{},
+		PrimaryStepOutputVariables: map[string]map[string]string{},
+	}
+
+	for _, file := range files {
+		// files are named {regionDeployType}-{region}.json
+		parts := strings.SplitN(strings.TrimSuffix(filepath.Base(file), ".json"), "-", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		regionDeployType, err := config.StringToRegionDeployType(parts[0])
+		if err != nil {
+			continue
+		}
+
+		vars, err := ReadOutputVariableFile(fs, dir, trackName, regionDeployType, parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if regionDeployType == config.PrimaryRegionDeployType {
+			output.PrimaryStepOutputVariables = vars
+		}
+
+		output.Executions = append(output.Executions, RegionExecution{
+			TrackName:        trackName,
+			Region:           parts[1],
+			RegionDeployType: regionDeployType,
+			Output:           ExecutionOutput{Name: trackName, StepOutputVariables: vars},
+		})
+	}
+
+	return output, nil
+}
+
+// readPersistedPreTrackOutput reads the pretrack's outputs persisted to cfg.OutputVariablesDir by an earlier deployment,
+// returning nil when none exist
+func (tracker DirectoryBasedTracker) readPersistedPreTrackOutput(cfg config.Config) *Output {
+	if cfg.OutputVariablesDir == "" {
+		tracker.Log.Warn("No output variables directory is configured, tracks will not receive the skipped pre-track's outputs")
+		return nil
+	}
+
+	preTrackOutput, err := ReadPersistedTrackOutput(tracker.Fs, cfg.OutputVariablesDir, PRE_TRACK_NAME)
+	if err != nil {
+		tracker.Log.WithError(err).Warnf("Unable to read the pre-track's outputs persisted in %s", cfg.OutputVariablesDir)
+		return nil
+	} else if preTrackOutput == nil {
+		tracker.Log.Warnf("No pre-track outputs are persisted in %s, tracks will not receive the skipped pre-track's outputs", cfg.OutputVariablesDir)
+		return nil
+	}
+
+	tracker.Log.Infof("Using the pre-track outputs persisted in %s", cfg.OutputVariablesDir)
+
+	return preTrackOutput
+}
+
+// WriteOutputVariableFiles writes the step output variables of each of the track's region executions
+// to {dir}/{track}/{regionDeployType}-{region}.json
+func WriteOutputVariableFiles(fs afero.Fs, dir string, output Output) error {
+	trackDir := filepath.Join(dir, output.Name)
+
+	if err := fs.MkdirAll(trackDir, 0755); err != nil {
+		return err
+	}
+
+	for _, exec := range output.Executions {
+		vars := exec.Output.StepOutputVariables
+		if vars == nil {
+			vars = map[string]map[string]string{}
+		}
+
+		// encoding/json sorts map keys, keeping the files stable and easy to diff
+		bytes, err := json.MarshalIndent(vars, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		file := filepath.Join(trackDir, fmt.Sprintf("%s-%s.json", exec.RegionDeployType, exec.Region))
+
+		if err := afero.WriteFile(fs, file, bytes, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ExecuteDeployTrack is for executing a single track across regions
-func ExecuteDeployTrack(execution Execution, cfg config.Config, t Track, out chan<- Output) {
+func ExecuteDeployTrack(ctx context.Context, execution Execution, cfg config.Config, t Track, out chan<- Output) {
 	logger := execution.Logger.WithFields(logrus.Fields{
 		"track":  t.Name,
 		"action": "deploy",
@@ -548,7 +2082,7 @@ func ExecuteDeployTrack(execution Execution, cfg config.Config, t Track, out cha
 	primaryOutChan := make(chan RegionExecution, 1)
 	primaryInChan := make(chan RegionExecution, 1)
 
-	region := cfg.PrimaryRegion // TODO(cfg:region): allow this to be overridden
+	region := t.primaryRegion(cfg)
 
 	primaryRegionExecution := RegionExecution{
 		TrackName:                  t.Name,
@@ -561,48 +2095,124 @@ func ExecuteDeployTrack(execution Execution, cfg config.Config, t Track, out cha
 		Output:                     ExecutionOutput{},
 		Region:                     region,
 		RegionDeployType:           config.PrimaryRegionDeployType,
+		ChannelBufferSize:          cfg.ChannelBufferSize,
+		MaxParallelSteps:           cfg.MaxParallelStepsPerProgression,
+		ContinueOnError:            cfg.ContinueOnError,
+		StrictOutputVariables:      cfg.StrictOutputVariables,
+		ExtraStepInputs:            cfg.ExtraStepInputs,
+		ExtraStepInputsAsDefaults:  cfg.ExtraStepInputsAsDefaults,
 		DefaultStepOutputVariables: map[string]map[string]string{},
 	}
 
-	if val, ok := execution.DefaultExecutionStepOutputVariables[fmt.Sprintf("%s-%s", primaryRegionExecution.RegionDeployType, primaryRegionExecution.Region)]; ok {
-		primaryRegionExecution.DefaultStepOutputVariables = val
-	}
+	// a smoke deploy is a quick sanity check of each track's first progression
+	if cfg.SmokeDeploy {
+		primaryRegionExecution.MaxStepProgression = 1
+	}
+
+	if val, ok := execution.DefaultExecutionStepOutputVariables[fmt.Sprintf("%s-%s", primaryRegionExecution.RegionDeployType, primaryRegionExecution.Region)]; ok {
+		primaryRegionExecution.DefaultStepOutputVariables = val
+	}
+
+	// Add step outputs for primary steps
+	// from the pretrack
+	if execution.PreTrackOutput != nil {
+		primaryRegionExecution.DefaultStepOutputVariables = AppendPreTrackOutputsToDefaultStepOutputVariables(primaryRegionExecution.DefaultStepOutputVariables, execution.PreTrackOutput, primaryRegionExecution.RegionDeployType, primaryRegionExecution.Region)
+	}
+	if execution.UpstreamTrackOutputs != nil {
+		primaryRegionExecution.DefaultStepOutputVariables = AppendUpstreamTrackOutputsToDefaultStepOutputVariables(primaryRegionExecution.DefaultStepOutputVariables, execution.UpstreamTrackOutputs, primaryRegionExecution.RegionDeployType, primaryRegionExecution.Region)
+	}
+
+	var primaryTrackExecution RegionExecution
+
+	if cfg.RegionalOnly {
+		if !t.RegionalDeployment || !t.Config.IncludesRegionDeployType(config.RegionalRegionDeployType) {
+			logger.Info("Track has no regional resources, skipping track as only regional deployments are executed.")
+			completeTrack(logger, execution, cfg, output, out)
+			return
+		}
+
+		// the primary region was deployed by an earlier run, its persisted step outputs are passed to the regional regions
+		vars, err := ReadOutputVariableFile(execution.Fs, cfg.OutputVariablesDir, t.Name, config.PrimaryRegionDeployType, region)
+		if err != nil {
+			output.Err = fmt.Errorf("unable to read primary step outputs for a regional only deployment: %w", err)
+			logger.WithError(output.Err).Error("Skipping regional deployments")
+			completeTrack(logger, execution, cfg, output, out)
+			return
+		}
+
+		logger.Infof("Skipping primary region, using the primary step outputs persisted in %s", cfg.OutputVariablesDir)
 
-	// Add step outputs for primary steps
-	// from the pretrack
-	if execution.PreTrackOutput != nil {
-		primaryRegionExecution.DefaultStepOutputVariables = AppendPreTrackOutputsToDefaultStepOutputVariables(primaryRegionExecution.DefaultStepOutputVariables, execution.PreTrackOutput, primaryRegionExecution.RegionDeployType, primaryRegionExecution.Region)
-	}
+		primaryTrackExecution = primaryRegionExecution
+		primaryTrackExecution.Output = ExecutionOutput{
+			Name:                t.Name,
+			Dir:                 t.Dir,
+			Steps:               map[string]config.Step{},
+			StepOutputVariables: vars,
+		}
+	} else {
+		writeRegionStatus(execution, cfg, t.Name, primaryRegionExecution.RegionDeployType, region, RegionStatusInProgress)
 
-	go DeployTrackRegion(primaryInChan, primaryOutChan)
-	primaryInChan <- primaryRegionExecution
+		go DeployTrackRegion(ctx, primaryInChan, primaryOutChan)
+		primaryInChan <- primaryRegionExecution
+
+		primaryTrackExecution = <-primaryOutChan
+		writeRegionStatus(execution, cfg, t.Name, primaryTrackExecution.RegionDeployType, region, regionExecutionStatus(primaryTrackExecution))
+		output.Executions = append(output.Executions, primaryTrackExecution)
+	}
 
-	primaryTrackExecution := <-primaryOutChan
-	output.Executions = append(output.Executions, primaryTrackExecution)
 	output.PrimaryStepOutputVariables = primaryTrackExecution.Output.StepOutputVariables
 
 	// end early if track has no regional step resources
-	if !t.RegionalDeployment {
-		logger.Info("Track has no regional resources, completing track.")
-		_, err := cloudaccountdeployment.FlushTrack(logger, t.Name)
+	if !t.RegionalDeployment || !t.Config.IncludesRegionDeployType(config.RegionalRegionDeployType) || cfg.SmokeDeploy {
+		if cfg.SmokeDeploy {
+			logger.Info("Smoke deploy, skipping regional deployments and completing track.")
+		} else {
+			logger.Info("Track has no regional resources, completing track.")
+		}
 
+		completeTrack(logger, execution, cfg, output, out)
+		return
+	}
+
+	targetRegions := t.regionalRegions(cfg)
+
+	if cfg.AdHocRegion != "" {
+		logger.Infof("Deploying regionally to ad-hoc region %s only", cfg.AdHocRegion)
+		targetRegions = []string{cfg.AdHocRegion}
+	} else if t.Config.RegionalRegionsOutput != "" {
+		regions, err := regionsFromStepOutput(t.Config, primaryTrackExecution.Output.StepOutputVariables)
 		if err != nil {
-			logger.WithError(err).Error(err)
+			output.Err = fmt.Errorf("unable to determine regional regions from primary step outputs: %w", err)
+			logger.WithError(output.Err).Error("Skipping regional deployments")
+
+			completeTrack(logger, execution, cfg, output, out)
+			return
 		}
 
-		out <- output
-		return
+		logger.Infof("Using regional regions %v from primary step output %s", regions, t.Config.RegionalRegionsOutput)
+		targetRegions = regions
+	}
+
+	if t.Config.PauseBeforeRegional {
+		if !approveRegional(ctx, logger, cfg, t, primaryTrackExecution, targetRegions) {
+			output.Partial = true
+
+			completeTrack(logger, execution, cfg, output, out)
+			return
+		}
 	}
 
-	targetRegions := cfg.RegionalRegions // TODO(cfg:region): allow this to be overridden
 	targetRegionsCount := len(targetRegions)
 	regionOutChan := make(chan RegionExecution, targetRegionsCount)
 	regionInChan := make(chan RegionExecution, targetRegionsCount)
 
 	logger.Infof("Primary region successfully completed, executing regional deployments in %v.", targetRegions)
 
+	// regional deployments are cancelled when too many regions fail
+	cancelled := make(chan struct{})
+
 	for i := 0; i < targetRegionsCount; i++ {
-		go DeployTrackRegion(regionInChan, regionOutChan)
+		go DeployTrackRegion(ctx, regionInChan, regionOutChan)
 	}
 
 	for _, reg := range targetRegions {
@@ -626,8 +2236,16 @@ func ExecuteDeployTrack(execution Execution, cfg config.Config, t Track, out cha
 			Output:                     ExecutionOutput{},
 			Region:                     reg,
 			RegionDeployType:           config.RegionalRegionDeployType,
+			ChannelBufferSize:          cfg.ChannelBufferSize,
+			MaxParallelSteps:           cfg.MaxParallelStepsPerProgression,
+			ContinueOnError:            cfg.ContinueOnError,
+			StrictOutputVariables:      cfg.StrictOutputVariables,
+			ExtraStepInputs:            cfg.ExtraStepInputs,
+			ExtraStepInputsAsDefaults:  cfg.ExtraStepInputsAsDefaults,
 			DefaultStepOutputVariables: outputVars,
 			PrimaryOutput:              primaryTrackExecution.Output,
+			Cancelled:                  cancelled,
+			SkipTests:                  len(cfg.RegionalTestRegions) > 0 && !contains(cfg.RegionalTestRegions, reg),
 		}
 
 		// Add step outputs for regional steps
@@ -635,16 +2253,49 @@ func ExecuteDeployTrack(execution Execution, cfg config.Config, t Track, out cha
 		if execution.PreTrackOutput != nil {
 			regionalRegionExecution.DefaultStepOutputVariables = AppendPreTrackOutputsToDefaultStepOutputVariables(regionalRegionExecution.DefaultStepOutputVariables, execution.PreTrackOutput, regionalRegionExecution.RegionDeployType, regionalRegionExecution.Region)
 		}
+		if execution.UpstreamTrackOutputs != nil {
+			regionalRegionExecution.DefaultStepOutputVariables = AppendUpstreamTrackOutputsToDefaultStepOutputVariables(regionalRegionExecution.DefaultStepOutputVariables, execution.UpstreamTrackOutputs, regionalRegionExecution.RegionDeployType, regionalRegionExecution.Region)
+		}
 
+		writeRegionStatus(execution, cfg, t.Name, regionalRegionExecution.RegionDeployType, reg, RegionStatusInProgress)
 		regionInChan <- regionalRegionExecution
 	}
 
+	successfulRegionsCount := 0
+	failedRegionsCount := 0
 	for i := 0; i < targetRegionsCount; i++ {
 		regionTrackOutput := <-regionOutChan
 		output.Executions = append(output.Executions, regionTrackOutput)
+
+		status := regionExecutionStatus(regionTrackOutput)
+		writeRegionStatus(execution, cfg, t.Name, regionTrackOutput.RegionDeployType, regionTrackOutput.Region, status)
+
+		if regionSucceeded(regionTrackOutput) {
+			successfulRegionsCount++
+		} else if status == config.Fail.String() {
+			failedRegionsCount++
+
+			// stop deploying to the remaining regions rather than paying for more of the same failure
+			if t.Config.MaxRegionalFailures > 0 && failedRegionsCount == t.Config.MaxRegionalFailures+1 {
+				logger.Warnf("%d regional regions failed, exceeding the maximum of %d, cancelling the remaining regional deployments", failedRegionsCount, t.Config.MaxRegionalFailures)
+				close(cancelled)
+				output.Partial = true
+			}
+		}
 	}
 
-	stepExecutions, err := cloudaccountdeployment.FlushTrack(logger, t.Name)
+	if successfulRegionsCount < t.Config.MinSuccessfulRegions {
+		output.Err = fmt.Errorf("%d of %d regional regions succeeded, track requires at least %d", successfulRegionsCount, targetRegionsCount, t.Config.MinSuccessfulRegions)
+		logger.WithError(output.Err).Error("Track did not succeed in the minimum number of regions")
+	}
+
+	completeTrack(logger, execution, cfg, output, out)
+}
+
+// completeTrack flushes the deployed track's recorded steps, persists its output variables and publishes its result
+// before sending its output
+func completeTrack(logger *logrus.Entry, execution Execution, cfg config.Config, output Output, out chan<- Output) {
+	stepExecutions, err := cloudaccountdeployment.FlushTrack(logger, output.Name)
 
 	if err != nil {
 		logger.WithError(err).Error(err)
@@ -656,11 +2307,20 @@ func ExecuteDeployTrack(execution Execution, cfg config.Config, t Track, out cha
 		logger.Debug(string(json))
 	}
 
+	if cfg.OutputVariablesDir != "" {
+		if err := WriteOutputVariableFiles(execution.Fs, cfg.OutputVariablesDir, output); err != nil {
+			logger.WithError(err).Error("Failed to write output variable files")
+		}
+	}
+
+	sortExecutions(output.Executions)
+
+	Results.Publish(newTrackResult("deploy", output))
 	out <- output
 }
 
 // ExecuteDestroyTrack is a helper function for destroying a track
-func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out chan<- Output) {
+func ExecuteDestroyTrack(ctx context.Context, execution Execution, cfg config.Config, t Track, out chan<- Output) {
 	trackLogger := execution.Logger.WithFields(logrus.Fields{
 		"track":  t.Name,
 		"action": "destroy",
@@ -671,18 +2331,39 @@ func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out ch
 		Executions: []RegionExecution{},
 	}
 
-	// TODO(high): need to gather previous step variables before attempting to destroy!
+	// the step outputs of the deployment being destroyed are in execution.DefaultExecutionStepOutputVariables, read from
+	// cfg.OutputVariablesDir when destroying without deploying first
 
 	// start with regional if existing
 	if t.RegionalDeployment {
 		regionOutChan := make(chan RegionExecution)
 		regionInChan := make(chan RegionExecution)
 
-		targetRegions := cfg.RegionalRegions
-		targetRegionsCount := len(cfg.RegionalRegions)
+		targetRegions := t.regionalRegions(cfg)
+		if cfg.AdHocRegion != "" {
+			targetRegions = []string{cfg.AdHocRegion}
+		} else if t.Config.RegionalRegionsOutput != "" {
+			// the regions were read from a primary step output when deployed, the deployment recorded each region's
+			// step outputs, persisted to cfg.OutputVariablesDir when destroying without deploying first
+			targetRegions = deployedRegionalRegions(execution.DefaultExecutionStepOutputVariables)
+			trackLogger.Infof("Destroying regional regions %v deployed to from primary step output %s", targetRegions, t.Config.RegionalRegionsOutput)
+		}
+
+		if len(cfg.DestroyRegions) > 0 {
+			var destroyRegions []string
+			for _, reg := range targetRegions {
+				if contains(cfg.DestroyRegions, reg) {
+					destroyRegions = append(destroyRegions, reg)
+				} else {
+					trackLogger.WithField("region", reg).Info("Not destroying regional steps, the region is not a destroy region")
+				}
+			}
+			targetRegions = destroyRegions
+		}
+		targetRegionsCount := len(targetRegions)
 
 		for i := 0; i < targetRegionsCount; i++ {
-			go DestroyTrackRegion(regionInChan, regionOutChan)
+			go DestroyTrackRegion(ctx, regionInChan, regionOutChan)
 		}
 
 		for _, reg := range targetRegions {
@@ -696,6 +2377,10 @@ func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out ch
 				Output:                     ExecutionOutput{},
 				Region:                     reg,
 				RegionDeployType:           config.RegionalRegionDeployType,
+				ChannelBufferSize:          cfg.ChannelBufferSize,
+				MaxParallelSteps:           cfg.MaxParallelStepsPerProgression,
+				ExtraStepInputs:            cfg.ExtraStepInputs,
+				ExtraStepInputsAsDefaults:  cfg.ExtraStepInputsAsDefaults,
 				DefaultStepOutputVariables: execution.DefaultExecutionStepOutputVariables[fmt.Sprintf("%s-%s", config.RegionalRegionDeployType, reg)],
 			}
 
@@ -704,6 +2389,9 @@ func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out ch
 			if execution.PreTrackOutput != nil {
 				regionExecution.DefaultStepOutputVariables = AppendPreTrackOutputsToDefaultStepOutputVariables(regionExecution.DefaultStepOutputVariables, execution.PreTrackOutput, regionExecution.RegionDeployType, regionExecution.Region)
 			}
+			if execution.UpstreamTrackOutputs != nil {
+				regionExecution.DefaultStepOutputVariables = AppendUpstreamTrackOutputsToDefaultStepOutputVariables(regionExecution.DefaultStepOutputVariables, execution.UpstreamTrackOutputs, regionExecution.RegionDeployType, regionExecution.Region)
+			}
 
 			regionInChan <- regionExecution
 		}
@@ -714,12 +2402,22 @@ func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out ch
 		}
 	}
 
+	region := t.primaryRegion(cfg)
+
+	// the primary region is kept unless it is one of the destroy regions
+	if len(cfg.DestroyRegions) > 0 && !contains(cfg.DestroyRegions, region) {
+		trackLogger.WithField("region", region).Info("Not destroying primary steps, the region is not a destroy region")
+
+		sortExecutions(output.Executions)
+		Results.Publish(newTrackResult("destroy", output))
+		out <- output
+		return
+	}
+
 	// clean up primary
 	primaryOutChan := make(chan RegionExecution, 1)
 	primaryInChan := make(chan RegionExecution, 1)
 
-	region := cfg.PrimaryRegion // TODO(cfg:region): allow this to be overridden
-
 	primaryExecution := RegionExecution{
 		TrackName:                  t.Name,
 		TrackDir:                   t.Dir,
@@ -730,6 +2428,10 @@ func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out ch
 		Output:                     ExecutionOutput{},
 		Region:                     region,
 		RegionDeployType:           config.PrimaryRegionDeployType,
+		ChannelBufferSize:          cfg.ChannelBufferSize,
+		MaxParallelSteps:           cfg.MaxParallelStepsPerProgression,
+		ExtraStepInputs:            cfg.ExtraStepInputs,
+		ExtraStepInputsAsDefaults:  cfg.ExtraStepInputsAsDefaults,
 		DefaultStepOutputVariables: execution.DefaultExecutionStepOutputVariables[fmt.Sprintf("%s-%s", config.PrimaryRegionDeployType, region)],
 	}
 
@@ -738,18 +2440,25 @@ func ExecuteDestroyTrack(execution Execution, cfg config.Config, t Track, out ch
 	if execution.PreTrackOutput != nil {
 		primaryExecution.DefaultStepOutputVariables = AppendPreTrackOutputsToDefaultStepOutputVariables(primaryExecution.DefaultStepOutputVariables, execution.PreTrackOutput, primaryExecution.RegionDeployType, primaryExecution.Region)
 	}
+	if execution.UpstreamTrackOutputs != nil {
+		primaryExecution.DefaultStepOutputVariables = AppendUpstreamTrackOutputsToDefaultStepOutputVariables(primaryExecution.DefaultStepOutputVariables, execution.UpstreamTrackOutputs, primaryExecution.RegionDeployType, primaryExecution.Region)
+	}
 
-	go DestroyTrackRegion(primaryInChan, primaryOutChan)
+	go DestroyTrackRegion(ctx, primaryInChan, primaryOutChan)
 	primaryInChan <- primaryExecution
 
 	primaryTrackOutput := <-primaryOutChan
 	output.Executions = append(output.Executions, primaryTrackOutput)
 
+	sortExecutions(output.Executions)
+
+	Results.Publish(newTrackResult("destroy", output))
 	out <- output
 }
 
-func ExecuteDeployTrackRegion(in <-chan RegionExecution, out chan<- RegionExecution) {
+func ExecuteDeployTrackRegion(ctx context.Context, in <-chan RegionExecution, out chan<- RegionExecution) {
 	execution := <-in
+	start := time.Now()
 	logger := execution.Logger.WithFields(logrus.Fields{
 		"region":           execution.Region,
 		"regionDeployType": execution.RegionDeployType.String(),
@@ -766,27 +2475,74 @@ func ExecuteDeployTrackRegion(in <-chan RegionExecution, out chan<- RegionExecut
 		execution.Output.StepOutputVariables = map[string]map[string]string{}
 	}
 
+	// no tests are triggered, so none are collected
+	if execution.SkipTests {
+		logger.Info("Skipping step tests in this region")
+		execution.TrackStepsWithTestsCount = 0
+	}
+
 	// define test channel outside of stepProgression loop to allow tests to run in background while steps proceed through progressions
-	testOutChan := make(chan config.StepTestOutput)
-	testInChan := make(chan config.Step)
+	testOutChan := make(chan config.StepTestOutput, execution.ChannelBufferSize)
+	testInChan := make(chan stepTest)
 
 	// Create testing goroutines.
 	for testExecution := 0; testExecution < execution.TrackStepsWithTestsCount; testExecution++ {
-		go executeStepTest(logger, execution.Fs, execution.Region, execution.RegionDeployType, execution.Output.StepOutputVariables, testInChan, testOutChan)
+		go executeStepTest(ctx, logger, execution.Fs, execution.Region, execution.RegionDeployType, testInChan, testOutChan)
 	}
 
+	stepLimiter := newLimiter(execution.MaxParallelSteps)
+
+	// the step that set each output variable, to detect steps overwriting the output variables of a different step
+	outputSources := newOutputVariableSources(execution.Output.StepOutputVariables)
+
+	// output variables of progression scoped steps, only passed to the steps in the next progression
+	var progressionOutputVariables map[string]map[string]string
+
 	for progressionLevel := 1; progressionLevel <= execution.TrackStepProgressionsCount; progressionLevel++ {
-		sChan := make(chan config.Step)
+		stepOutputVariables := mergeOutputVariables(execution.Output.StepOutputVariables, progressionOutputVariables)
+		stepOutputVariables = withExtraStepInputs(stepOutputVariables, execution.ExtraStepInputs, execution.ExtraStepInputsAsDefaults)
+		nextProgressionOutputVariables := map[string]map[string]string{}
+
+		sChan := make(chan config.Step, execution.ChannelBufferSize)
 		for _, s := range execution.TrackOrderedSteps[progressionLevel] {
 
-			// regional resources do not exist
-			if execution.RegionDeployType == config.RegionalRegionDeployType && !s.RegionalResourcesExist {
+			// regional resources do not exist, or primary resources do not exist
+			if (execution.RegionDeployType == config.RegionalRegionDeployType && !s.RegionalResourcesExist) ||
+				(execution.RegionDeployType == config.PrimaryRegionDeployType && s.RegionalOnly) {
 				go func(s config.Step) {
 					s.Output.Status = config.Na
 					sChan <- s
 				}(s)
-				// if any previous failures, skip
-			} else if progressionLevel > 1 && execution.Output.FailureCount > 0 {
+			} else if vars, ok := s.UnchangedOutputs[fmt.Sprintf("%s-%s", execution.RegionDeployType, execution.Region)]; ok {
+				go func(s config.Step, logger *logrus.Entry) {
+					logger.WithField("step", s.Name).Info("Skipping step, unchanged since its last successful apply")
+
+					s.Output = unchangedStepOutput(execution, s, vars)
+					sChan <- s
+				}(s, logger)
+			} else if ctx.Err() != nil {
+				go func(s config.Step, logger *logrus.Entry) {
+					logger.WithField("step", s.Name).Warn("Cancelling step, the deployment was cancelled")
+
+					s.Output.Status = config.Cancelled
+					sChan <- s
+				}(s, logger)
+			} else if isCancelled(execution.Cancelled) {
+				go func(s config.Step, logger *logrus.Entry) {
+					logger.WithField("step", s.Name).Warn("Skipping step, the region execution was cancelled")
+
+					s.Output.Status = config.Skipped
+					sChan <- s
+				}(s, logger)
+			} else if execution.MaxStepProgression > 0 && progressionLevel > execution.MaxStepProgression {
+				go func(s config.Step, logger *logrus.Entry) {
+					logger.WithField("step", s.Name).Info("Skipping step beyond the maximum step progression")
+
+					s.Output.Status = config.Skipped
+					sChan <- s
+				}(s, logger)
+				// if any previous failures, skip unless continuing on errors
+			} else if progressionLevel > 1 && execution.Output.FailureCount > 0 && !execution.ContinueOnError {
 				go func(s config.Step, logger *logrus.Entry) {
 					slogger := logger.WithFields(logrus.Fields{
 						"step": s.Name,
@@ -809,7 +2565,20 @@ func ExecuteDeployTrackRegion(in <-chan RegionExecution, out chan<- RegionExecut
 					sChan <- s
 				}(s, logger)
 			} else {
-				go ExecuteStep(execution.Region, execution.RegionDeployType, logger, execution.Fs, execution.Output.StepOutputVariables, progressionLevel, s, sChan, false)
+				go func(s config.Step, progressionLevel int) {
+					stepLimiter.acquire()
+					defer stepLimiter.release()
+
+					if err := DeploymentPause.WaitUntilResumed(ctx); err != nil {
+						logger.WithField("step", s.Name).Warn("Cancelling step, the deployment was cancelled while paused")
+
+						s.Output.Status = config.Cancelled
+						sChan <- s
+						return
+					}
+
+					ExecuteStep(ctx, execution.Region, execution.RegionDeployType, logger, execution.Fs, stepOutputVariables, progressionLevel, s, sChan, false)
+				}(s, progressionLevel)
 			}
 		}
 
@@ -818,27 +2587,73 @@ func ExecuteDeployTrackRegion(in <-chan RegionExecution, out chan<- RegionExecut
 			s := <-sChan
 			if s.Output.Status == config.Skipped {
 				execution.Output.SkippedCount++
+			} else if s.Output.Status == config.Cancelled {
+				execution.Output.CancelledCount++
 			} else {
 				execution.Output.ExecutedCount++
 			}
+			s.Output.OutputVariables = LimitOutputValues(logger.WithField("step", s.Name), s.DeployConfig, s.Output.OutputVariables)
+
+			rejectedOutputs := false
+			if s.Config.OutputScope != config.OutputScopeNone {
+				for _, collision := range outputSources.collisions(s.Name, s.Output, s.DeployConfig.RegionalOutputKeyStrategy) {
+					if execution.StrictOutputVariables {
+						logger.WithField("step", s.Name).WithError(collision).Error("Failing step, it overwrites an output variable of a different step")
+
+						s.Output.Status = config.Fail
+						s.Output.Err = collision
+						rejectedOutputs = true
+						break
+					}
+
+					logger.WithField("step", s.Name).Warn(collision.Error())
+				}
+
+				if !rejectedOutputs {
+					outputSources.record(s.Name, s.Output, s.DeployConfig.RegionalOutputKeyStrategy)
+				}
+			}
+
 			execution.Output.Steps[s.Name] = s
-			execution.Output.StepOutputVariables = AppendTrackOutput(execution.Output.StepOutputVariables, s.Output)
 
-			if s.Output.Err != nil || s.Output.Status == config.Fail {
+			if s.Output.Status != config.Na {
+				Results.Publish(newStepResult("deploy", execution, s))
+			}
+
+			switch {
+			case s.Config.OutputScope == config.OutputScopeNone:
+				logger.WithField("step", s.Name).Debug("Withholding step output variables from later steps")
+			case rejectedOutputs:
+				logger.WithField("step", s.Name).Debug("Withholding step output variables overwriting those of a different step")
+			case s.Config.OutputScope == config.OutputScopeProgression:
+				nextProgressionOutputVariables = AppendTrackOutput(nextProgressionOutputVariables, s.Output, s.DeployConfig.RegionalOutputKeyStrategy)
+			default:
+				execution.Output.StepOutputVariables = AppendTrackOutput(execution.Output.StepOutputVariables, s.Output, s.DeployConfig.RegionalOutputKeyStrategy)
+			}
+
+			if s.Output.RateLimited {
+				execution.Output.RateLimitedCount++
+			}
+
+			if s.Output.Status == config.Fail || (s.Output.Err != nil && s.Output.Status != config.Cancelled) {
 				execution.Output.FailureCount++
 				execution.Output.FailedSteps = append(execution.Output.FailedSteps, s)
 			}
 
 			// trigger tests if exist, this number needs to match testing goroutines triggered above
 			// further filtering happens after trigger
-			if execution.RegionDeployType == config.RegionalRegionDeployType && s.RegionalTestsExist {
+			if execution.SkipTests {
+				logger.WithField("step", s.Name).Debug("Not triggering tests, tests are skipped in this region")
+			} else if execution.RegionDeployType == config.RegionalRegionDeployType && s.RegionalTestsExist {
 				logger.Debug("Triggering tests")
-				testInChan <- s
+				testInChan <- stepTest{step: s, stepOutputVariables: copyOutputVariables(execution.Output.StepOutputVariables)}
 			} else if execution.RegionDeployType == config.PrimaryRegionDeployType && s.TestsExist {
 				logger.Debug("Triggering tests")
-				testInChan <- s
+				testInChan <- stepTest{step: s, stepOutputVariables: copyOutputVariables(execution.Output.StepOutputVariables)}
 			}
 		}
+
+		progressionOutputVariables = nextProgressionOutputVariables
 	}
 
 	for testExecution := 0; testExecution < execution.TrackStepsWithTestsCount; testExecution++ {
@@ -852,22 +2667,35 @@ func ExecuteDeployTrackRegion(in <-chan RegionExecution, out chan<- RegionExecut
 		}
 
 		// TODO: avoid this loop with FailedSteps
+		deployFailed := false
 		for i := range execution.Output.FailedSteps {
 			if execution.Output.FailedSteps[i].Name == s.StepName {
 				execution.Output.FailedSteps[i].TestOutput = s
+				deployFailed = true
 			}
 		}
 
 		if s.Err != nil {
 			execution.Output.FailedTestCount++
+
+			// steps that deployed but failed their tests are optionally reported alongside failed deploys
+			if val, ok := execution.Output.Steps[s.StepName]; ok && !deployFailed && val.DeployConfig.FailedStepsIncludeTests {
+				val.TestsFailedOnly = true
+				execution.Output.FailedSteps = append(execution.Output.FailedSteps, val)
+			}
 		}
 	}
 
+	sortFailedSteps(execution.Output.FailedSteps)
+	execution.Output.Duration = time.Since(start)
+	cleanupWorkdirs(logger, execution)
+
 	out <- execution
 }
 
-func ExecuteDestroyTrackRegion(in <-chan RegionExecution, out chan<- RegionExecution) {
+func ExecuteDestroyTrackRegion(ctx context.Context, in <-chan RegionExecution, out chan<- RegionExecution) {
 	execution := <-in
+	start := time.Now()
 
 	logger := execution.Logger.WithFields(logrus.Fields{
 		"region":           execution.Region,
@@ -881,17 +2709,47 @@ func ExecuteDestroyTrackRegion(in <-chan RegionExecution, out chan<- RegionExecu
 		StepOutputVariables: execution.DefaultStepOutputVariables,
 	}
 
+	stepLimiter := newLimiter(execution.MaxParallelSteps)
+
 	for i := execution.TrackStepProgressionsCount; i >= 1; i-- {
-		sChan := make(chan config.Step)
-		for progressionLevel, s := range execution.TrackOrderedSteps[i] {
-			// if any previous failures, skip
-			if (progressionLevel > 1 && execution.Output.FailureCount > 0) || (execution.RegionDeployType == config.RegionalRegionDeployType && !s.RegionalResourcesExist) {
+		stepOutputVariables := withExtraStepInputs(execution.Output.StepOutputVariables, execution.ExtraStepInputs, execution.ExtraStepInputsAsDefaults)
+
+		sChan := make(chan config.Step, execution.ChannelBufferSize)
+		for _, s := range execution.TrackOrderedSteps[i] {
+			// if any failures in a later progression, skip
+			if (i < execution.TrackStepProgressionsCount && execution.Output.FailureCount > 0) || (execution.RegionDeployType == config.RegionalRegionDeployType && !s.RegionalResourcesExist) {
 				go func(s config.Step) {
 					s.Output.Status = config.Skipped
 					sChan <- s
 				}(s)
+			} else if execution.RegionDeployType == config.PrimaryRegionDeployType && s.RegionalOnly {
+				// regional only steps are destroyed in the regional pass
+				go func(s config.Step) {
+					s.Output.Status = config.Na
+					sChan <- s
+				}(s)
+			} else if ctx.Err() != nil {
+				go func(s config.Step, logger *logrus.Entry) {
+					logger.WithField("step", s.Name).Warn("Cancelling step, the destroy was cancelled")
+
+					s.Output.Status = config.Cancelled
+					sChan <- s
+				}(s, logger)
 			} else {
-				go ExecuteStep(execution.Region, execution.RegionDeployType, logger, execution.Fs, execution.Output.StepOutputVariables, i, s, sChan, true)
+				go func(s config.Step, progressionLevel int) {
+					stepLimiter.acquire()
+					defer stepLimiter.release()
+
+					if err := DeploymentPause.WaitUntilResumed(ctx); err != nil {
+						logger.WithField("step", s.Name).Warn("Cancelling step, the destroy was cancelled while paused")
+
+						s.Output.Status = config.Cancelled
+						sChan <- s
+						return
+					}
+
+					ExecuteStep(ctx, execution.Region, execution.RegionDeployType, logger, execution.Fs, stepOutputVariables, progressionLevel, s, sChan, true)
+				}(s, i)
 			}
 		}
 		N := len(execution.TrackOrderedSteps[i])
@@ -899,27 +2757,225 @@ func ExecuteDestroyTrackRegion(in <-chan RegionExecution, out chan<- RegionExecu
 			s := <-sChan
 			if s.Output.Status == config.Skipped {
 				execution.Output.SkippedCount++
+			} else if s.Output.Status == config.Cancelled {
+				execution.Output.CancelledCount++
 			} else {
 				execution.Output.ExecutedCount++
 			}
 			execution.Output.Steps[s.Name] = s
 
-			if s.Output.Err != nil {
+			if s.Output.Status != config.Na {
+				Results.Publish(newStepResult("destroy", execution, s))
+			}
+
+			if s.Output.RateLimited {
+				execution.Output.RateLimitedCount++
+			}
+
+			if s.Output.Err != nil && s.Output.Status != config.Cancelled {
 				execution.Output.FailureCount++
 				execution.Output.FailedSteps = append(execution.Output.FailedSteps, s)
 			}
 		}
 	}
 
+	sortFailedSteps(execution.Output.FailedSteps)
+	execution.Output.Duration = time.Since(start)
+	cleanupWorkdirs(logger, execution)
+
 	out <- execution
 	return
 }
 
-func ExecuteStepImpl(region string, regionDeployType config.RegionDeployType,
+// resolveTrackRegions resolves the primary and regional regions of a track, falling back to cfg's regions when the track
+// does not override them. Regional region patterns select the matching regions of cfg.RegionalRegions
+func resolveTrackRegions(cfg config.Config, trackConfig config.TrackConfig) (string, []string) {
+	primaryRegion := cfg.PrimaryRegion
+	if trackConfig.PrimaryRegion != "" {
+		primaryRegion = trackConfig.PrimaryRegion
+	}
+
+	if len(trackConfig.RegionalRegions) == 0 {
+		return primaryRegion, cfg.RegionalRegions
+	}
+
+	regionalRegions := []string{}
+	seen := map[string]bool{}
+	for _, region := range trackConfig.RegionalRegions {
+		candidates := []string{region}
+		if config.IsRegionPattern(region) {
+			candidates = nil
+			for _, r := range cfg.RegionalRegions {
+				if matched, _ := path.Match(region, r); matched {
+					candidates = append(candidates, r)
+				}
+			}
+		}
+
+		for _, r := range candidates {
+			if !seen[r] {
+				seen[r] = true
+				regionalRegions = append(regionalRegions, r)
+			}
+		}
+	}
+
+	return primaryRegion, regionalRegions
+}
+
+// primaryRegion is the region the track is deployed to first, cfg.PrimaryRegion unless overridden
+func (t Track) primaryRegion(cfg config.Config) string {
+	if t.PrimaryRegion != "" {
+		return t.PrimaryRegion
+	}
+
+	return cfg.PrimaryRegion
+}
+
+// regionalRegions are the regions the track is deployed to regionally, cfg.RegionalRegions unless overridden
+func (t Track) regionalRegions(cfg config.Config) []string {
+	if t.RegionalRegions != nil {
+		return t.RegionalRegions
+	}
+
+	return cfg.RegionalRegions
+}
+
+// invalidRegions returns the configured primary, regional and ad hoc regions, along with the regions the tracks override
+// them with, that are not allowed regions. None are invalid when no allowed regions are configured
+func invalidRegions(cfg config.Config, tracks []Track) (invalid []string) {
+	if len(cfg.AllowedRegions) == 0 {
+		return nil
+	}
+
+	allowed := map[string]bool{}
+	for _, region := range cfg.AllowedRegions {
+		allowed[region] = true
+	}
+
+	regions := append([]string{cfg.PrimaryRegion, cfg.AdHocRegion}, cfg.RegionalRegions...)
+	for _, t := range tracks {
+		regions = append(append(regions, t.PrimaryRegion), t.RegionalRegions...)
+	}
+
+	seen := map[string]bool{}
+	for _, region := range regions {
+		if region == "" || allowed[region] || seen[region] {
+			continue
+		}
+
+		seen[region] = true
+		invalid = append(invalid, region)
+	}
+
+	return invalid
+}
+
+// undeployedDestroyRegions returns the configured destroy regions that none of the tracks are deployed to
+func undeployedDestroyRegions(cfg config.Config, tracks []Track) (undeployed []string) {
+	deployed := map[string]bool{cfg.AdHocRegion: cfg.AdHocRegion != ""}
+	for _, t := range tracks {
+		deployed[t.primaryRegion(cfg)] = true
+
+		if t.RegionalDeployment {
+			for _, region := range t.regionalRegions(cfg) {
+				deployed[region] = true
+			}
+		}
+	}
+
+	for _, region := range cfg.DestroyRegions {
+		if !deployed[region] && !contains(undeployed, region) {
+			undeployed = append(undeployed, region)
+		}
+	}
+
+	return undeployed
+}
+
+// cleanupWorkdirs removes the regional working directories of the region execution's steps that are not retained by
+// the configured WorkdirRetention
+func cleanupWorkdirs(logger *logrus.Entry, execution RegionExecution) {
+	if execution.RegionDeployType != config.RegionalRegionDeployType {
+		return
+	}
+
+	for _, s := range execution.Output.Steps {
+		// the working directory is only created for steps that were executed
+		if s.Output.Status == config.Na || s.Output.Status == config.Skipped {
+			continue
+		}
+
+		failed := s.Output.Err != nil || s.Output.Status == config.Fail || s.TestOutput.Err != nil
+
+		switch s.DeployConfig.WorkdirRetention {
+		case config.AlwaysRetainWorkdirs:
+			continue
+		case config.NeverRetainWorkdirs:
+		default:
+			if failed {
+				continue
+			}
+		}
+
+		dir := steps.RegionalWorkdir(s, execution.Region)
+		if err := execution.Fs.RemoveAll(dir); err != nil {
+			logger.WithError(err).WithField("step", s.Name).Warnf("Unable to remove working directory %s", dir)
+		}
+	}
+}
+
+func ExecuteStepImpl(ctx context.Context, region string, regionDeployType config.RegionDeployType,
 	logger *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
 	s config.Step, out chan<- config.Step, destroy bool) {
 
-	exec, err := steps.InitExecution(s, logger, fs, regionDeployType, region, defaultStepOutputVariables)
+	// the deployment was cancelled while the step waited to start
+	if ctx.Err() != nil {
+		s.Output = config.StepOutput{
+			Status:           config.Cancelled,
+			RegionDeployType: regionDeployType,
+			Region:           region,
+			StepName:         s.Name,
+		}
+		out <- s
+		return
+	}
+
+	// a step without a runner cannot be executed, fail it instead of the whole deployment
+	if s.Runner == nil {
+		err := fmt.Errorf("no runner for step %s", s.Name)
+		logger.WithError(err).Error("Unable to execute step")
+
+		s.Output = config.StepOutput{
+			Status:           config.Fail,
+			RegionDeployType: regionDeployType,
+			Region:           region,
+			StepName:         s.Name,
+			Err:              err,
+		}
+		recordStepFail(logger, s, regionDeployType, region, destroy, err)
+		out <- s
+		return
+	}
+
+	hooks := stepHooks(ctx)
+	if err := beforeStep(hooks, s); err != nil {
+		err = fmt.Errorf("step hook failed before step %s: %w", s.Name, err)
+		logger.WithError(err).Error("Unable to execute step")
+
+		s.Output = config.StepOutput{
+			Status:           config.Fail,
+			RegionDeployType: regionDeployType,
+			Region:           region,
+			StepName:         s.Name,
+			Err:              err,
+		}
+		recordStepFail(logger, s, regionDeployType, region, destroy, err)
+		out <- s
+		return
+	}
+
+	exec, err := steps.InitExecution(ctx, s, logger, fs, regionDeployType, region, defaultStepOutputVariables)
 
 	// if error initializing, short circuit
 	if err != nil {
@@ -931,7 +2987,10 @@ func ExecuteStepImpl(region string, regionDeployType config.RegionDeployType,
 			StreamOutput:     "",
 			Err:              err,
 			OutputVariables:  nil,
+			FailureCategory:  config.InitFailure,
 		}
+		recordStepFail(logger, s, regionDeployType, region, destroy, err)
+		afterStep(hooks, s)
 		out <- s
 		return
 	}
@@ -940,20 +2999,102 @@ func ExecuteStepImpl(region string, regionDeployType config.RegionDeployType,
 
 	exec2, _ := s.Runner.PreExecute(exec)
 
-	if destroy {
-		output = steps.ExecuteStepDestroy(s.Runner, exec2)
-	} else {
-		output = steps.ExecuteStep(s.Runner, exec2)
+	start := time.Now()
+
+	attempt := 0
+	for ; ; attempt++ {
+		if destroy {
+			output = steps.ExecuteStepDestroy(s.Runner, exec2)
+		} else {
+			output = steps.ExecuteStep(s.Runner, exec2)
+		}
+
+		// an interrupted runner fails, but the step was cancelled rather than failing
+		if output.Status == config.Fail && ctx.Err() != nil {
+			output.Status = config.Cancelled
+			break
+		}
+
+		if output.Status != config.Fail {
+			break
+		}
+
+		output.FailureCategory = FailureClassifier.Classify(output)
+
+		if matchesRetryablePattern(s.DeployConfig.RetryablePattern, output) {
+			output.FailureCategory = config.RetryableFailure
+		}
+
+		if output.FailureCategory != config.RetryableFailure || attempt >= s.DeployConfig.RetryableFailureRetries {
+			break
+		}
+
+		// back off exponentially, giving eventually consistent changes (e.g. IAM propagation) time to settle
+		wait := s.DeployConfig.RetryBackoff << uint(attempt)
+		exec2.Logger.WithError(output.Err).Warnf("Step failure classified as %s, retrying in %v. Retry Count: %v.", output.FailureCategory, wait, attempt)
+
+		// cancelling the deployment while backing off cancels the step rather than retrying it
+		select {
+		case <-ctx.Done():
+			output.Status = config.Cancelled
+		case <-time.After(wait):
+		}
+
+		if output.Status == config.Cancelled {
+			break
+		}
 	}
 
+	output.StartTime = start
+	output.EndTime = time.Now()
+	output.Duration = output.EndTime.Sub(start)
+	output.Attempts = attempt + 1
+
 	s.Output = output
 
+	afterStep(hooks, s)
 	out <- s
 	return
 }
 
-func executeStepTest(incomingLogger *logrus.Entry, fs afero.Fs, region string, regionDeployType config.RegionDeployType, defaultStepOutputVariables map[string]map[string]string, in <-chan config.Step, out chan<- config.StepTestOutput) {
-	s := <-in
+// recordStepFail records a deployed step that failed before its runner executed it, the runner records the steps it
+// executed
+func recordStepFail(logger *logrus.Entry, s config.Step, regionDeployType config.RegionDeployType, region string, destroy bool, err error) {
+	if destroy {
+		return
+	}
+
+	cloudaccountdeployment.RecordStepFail(logger, "", s.TrackName, s.Name, regionDeployType.String(), region, s.DeployConfig.UniqueExternalExecutionID, s.DeployConfig.Project, s.DeployConfig.RegionalRegions, err)
+}
+
+// matchesRetryablePattern determines whether a failed step's error or stream output matches the configured retryable
+// pattern, nothing matches an empty pattern
+func matchesRetryablePattern(pattern string, output config.StepOutput) bool {
+	if pattern == "" {
+		return false
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+
+	if output.Err != nil && re.MatchString(output.Err.Error()) {
+		return true
+	}
+
+	return re.MatchString(output.StreamOutput)
+}
+
+// stepTest is a deployed step whose tests are triggered, with the step output variables when they were triggered
+type stepTest struct {
+	step                config.Step
+	stepOutputVariables map[string]map[string]string
+}
+
+func executeStepTest(ctx context.Context, incomingLogger *logrus.Entry, fs afero.Fs, region string, regionDeployType config.RegionDeployType, in <-chan stepTest, out chan<- config.StepTestOutput) {
+	test := <-in
+	s := test.step
 	tOutput := config.StepTestOutput{}
 
 	logger := incomingLogger.WithFields(logrus.Fields{
@@ -967,13 +3108,13 @@ func executeStepTest(incomingLogger *logrus.Entry, fs afero.Fs, region string, r
 	// only run step tests when they exist and deployment was error free
 	if s.Output.Err != nil || s.Output.Status == config.Fail {
 		logger.Warn("Skipping Tests Due to Deployment Error")
-	} else if s.DeployConfig.DryRun {
+	} else if s.DeployConfig.DryRun && !s.DeployConfig.TestAgainstPlan {
 		logger.Info("Skipping Tests for Dry Run")
-	} else if s.Output.Status == config.Skipped {
+	} else if s.Output.Status == config.Skipped || s.Output.Status == config.Cancelled {
 		logger.Warn("Skipping Tests because step was also skipped")
 	} else {
 		logger.Info("Triggering Step Tests")
-		exec, err := steps.InitExecution(s, logger, fs, regionDeployType, region, defaultStepOutputVariables)
+		exec, err := steps.InitExecution(ctx, s, logger, fs, regionDeployType, region, test.stepOutputVariables)
 
 		// if err initializing, short circuit
 		if err != nil {
@@ -994,6 +3135,9 @@ func executeStepTest(incomingLogger *logrus.Entry, fs afero.Fs, region string, r
 		}
 	}
 
+	// attribute results to the step regardless of the runner or test framework that produced them
+	tOutput.StepName = s.Name
+
 	out <- tOutput
 	return
 }
@@ -1,18 +1,30 @@
 package tracks_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/optum/runiac/mocks"
+	"github.com/optum/runiac/pkg/cloudaccountdeployment"
 	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/steps"
+	"github.com/optum/runiac/pkg/testrunner"
 	"github.com/optum/runiac/pkg/tracks"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var fs afero.Fs
@@ -126,6 +138,9 @@ func TestMain(m *testing.M) {
 
 				stepDir := fmt.Sprintf("%s/step%v_%v", track.Dir, progression, stubStep.Name)
 				fs.MkdirAll(stepDir, 0755)
+				_ = afero.WriteFile(fs, fmt.Sprintf("%s/main.tf", stepDir), []byte(`
+				faketerraform
+				`), 0644)
 
 				track.StepsCount++
 
@@ -161,9 +176,10 @@ func TestMain(m *testing.M) {
 
 func TestGetTracksWithTargetAll_ShouldReturnCorrectTracks(t *testing.T) {
 	// act
-	mockTracks := sut.GatherTracks(config.Config{
+	mockTracks, err := sut.GatherTracks(config.Config{
 		TargetAll: true,
 	})
+	require.NoError(t, err)
 
 	// assert
 	require.Equal(t, stubTrackCount, len(mockTracks), "Three tracks should have been gathered")
@@ -228,10 +244,11 @@ func TestGetTracksWithTargetAll_ShouldReturnCorrectTracks(t *testing.T) {
 func TestGetTracksWithStepTarget_ShouldReturnCorrectTracks(t *testing.T) {
 	stubStepWhitelist := []string{fmt.Sprintf("#core#%s#%s", stubTrackNameA, stubStepWithTests.Name), fmt.Sprintf("#core#%s#%s", stubTrackNameB, "b11")}
 	// act
-	mockTracks := sut.GatherTracks(config.Config{
+	mockTracks, err := sut.GatherTracks(config.Config{
 		StepWhitelist: stubStepWhitelist,
 		Project:       "core",
 	})
+	require.NoError(t, err)
 
 	// assert
 	assert.Equal(t, 2, len(mockTracks), "Two tracks should have been gathered")
@@ -249,6 +266,583 @@ func TestGetTracksWithStepTarget_ShouldReturnCorrectTracks(t *testing.T) {
 	require.Equal(t, len(stubStepWhitelist), stepCount, "Track steps count should match total steps in defined in whitelist")
 }
 
+func TestGatherTracks_ShouldSkipStepsWithoutRunnableContent(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = stubFs.MkdirAll("tracks/track/step1_placeholder", 0755)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_placeholder/README.md", []byte("coming soon"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step2_regional/regional/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+		Project:   "core",
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1, "Track should still be gathered")
+
+	var stepNames []string
+	for _, steps := range mockTracks[0].OrderedSteps {
+		for _, step := range steps {
+			stepNames = append(stepNames, step.Name)
+		}
+	}
+
+	require.ElementsMatch(t, []string{"deploy", "regional"}, stepNames, "Empty step should be skipped")
+	require.Equal(t, 2, mockTracks[0].StepsCount, "Empty step should not be counted")
+}
+
+func TestGatherTracks_ShouldParseMultiDigitProgressionLevels(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	for _, folder := range []string{"step1_vpc", "step2_subnets", "step10_routes", "step0_zero", "stepX_foo", "step3", "step4_", "steps"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/track/%s/main.tf", folder), []byte(""), 0644)
+	}
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+		Project:   "core",
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+
+	stepNames := map[int][]string{}
+	for level, steps := range mockTracks[0].OrderedSteps {
+		for _, step := range steps {
+			require.Equal(t, level, step.ProgressionLevel)
+			stepNames[level] = append(stepNames[level], step.Name)
+		}
+	}
+
+	require.Equal(t, map[int][]string{
+		1:  {"vpc"},
+		2:  {"subnets"},
+		10: {"routes"},
+	}, stepNames, "Malformed step folders and progression level 0 should be ignored")
+	require.Equal(t, 10, mockTracks[0].StepProgressionsCount)
+	require.Equal(t, 3, mockTracks[0].StepsCount)
+}
+
+func TestGatherTracks_ShouldUseCustomStepIDDelimiter(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	for _, folder := range []string{"step1_vpc", "step1_dns", "step2_sub|nets"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/network/%s/main.tf", folder), []byte(""), 0644)
+	}
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		Project:         "core#1",
+		StepIDDelimiter: "|",
+		StepWhitelist:   []string{"|core#1|network|vpc", "|core#1|network|sub|nets"},
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+	require.Equal(t, 1, mockTracks[0].StepsCount, "Only the whitelisted step should be gathered, names containing the delimiter should be rejected")
+
+	step := mockTracks[0].OrderedSteps[1][0]
+	require.Equal(t, "|core#1|network|vpc", step.ID)
+	require.Equal(t, []string{"core#1", "network", "vpc"}, config.ParseStepID("|", step.ID), "Step ids should round-trip with the custom delimiter")
+
+	_, err = config.NewStepID("|", "core", "network", "sub|nets")
+	require.Error(t, err, "Names containing the delimiter should be rejected")
+}
+
+func TestGatherTracks_ShouldFailOnTrackWithEmptyStepWhenFailOnEmptySteps(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = stubFs.MkdirAll("tracks/track-a/step1_placeholder", 0755)
+	_ = afero.WriteFile(stubFs, "tracks/track-a/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track-b/step1_deploy/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll:        true,
+		Project:          "core",
+		FailOnEmptySteps: true,
+	})
+
+	// assert
+	require.Error(t, err, "A track with an empty step should fail gathering rather than be excluded")
+	require.Contains(t, err.Error(), "track-a")
+	require.Empty(t, mockTracks)
+}
+
+func TestGatherTracks_ShouldIgnoreRegionalResourcesForPrimaryOnlyTrack(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/global/runiac.yaml", []byte("region_deploy_types:\n  - primary\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/global/step1_iam/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/global/step1_iam/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/global/step1_iam/regional/tests/tests.test", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+	require.Equal(t, []string{"primary"}, mockTracks[0].Config.RegionDeployTypes, "Track configuration should be read")
+	require.False(t, mockTracks[0].RegionalDeployment, "Primary only track should not deploy regionally")
+	require.Equal(t, 0, mockTracks[0].StepsWithRegionalTestsCount)
+
+	step := mockTracks[0].OrderedSteps[1][0]
+	require.False(t, step.RegionalResourcesExist, "Regional resources should be ignored for a primary only track")
+	require.False(t, step.RegionalTestsExist, "Regional tests should be ignored for a primary only track")
+}
+
+func TestGatherTracks_ShouldDetectTestsWithoutPrebuiltBinary(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_binary/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_binary/tests/tests.test", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_gosource/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_gosource/tests/step_test.go", []byte("package tests"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_bats/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_bats/tests/step.bats", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_override/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_override/runiac.yaml", []byte("has_tests: true\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_disabled/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_disabled/tests/tests.test", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_disabled/runiac.yaml", []byte("has_tests: false\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_none/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_none/tests/README.md", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+
+	testsExist := map[string]bool{}
+	for _, step := range mockTracks[0].OrderedSteps[1] {
+		testsExist[step.Name] = step.TestsExist
+	}
+
+	require.Equal(t, map[string]bool{
+		"binary":   true,
+		"gosource": true,
+		"bats":     true,
+		"override": true,
+		"disabled": false,
+		"none":     false,
+	}, testsExist)
+	require.Equal(t, 4, mockTracks[0].StepsWithTestsCount, "Steps with tests should be counted without a prebuilt binary")
+}
+
+func TestGatherTracks_ShouldDetectRegionalTestsFromOverride(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_deploy/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_deploy/runiac.yaml", []byte("has_regional_tests: true\n"), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+	require.True(t, mockTracks[0].OrderedSteps[1][0].RegionalTestsExist)
+	require.False(t, mockTracks[0].OrderedSteps[1][0].TestsExist)
+	require.Equal(t, 1, mockTracks[0].StepsWithRegionalTestsCount)
+}
+
+func TestGatherTracks_ShouldDetectRegionalTestsFromTestCommand(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_command/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_command/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_command/runiac.yaml", []byte("test_command: make test\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_disabled/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_disabled/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_disabled/runiac.yaml", []byte("test_command: make test\nhas_regional_tests: false\n"), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+
+	regionalTestsExist := map[string]bool{}
+	for _, step := range mockTracks[0].OrderedSteps[1] {
+		regionalTestsExist[step.Name] = step.RegionalTestsExist
+	}
+
+	require.Equal(t, map[string]bool{"command": true, "disabled": false}, regionalTestsExist, "A test command should run the regional tests unless disabled")
+	require.Equal(t, 1, mockTracks[0].StepsWithRegionalTestsCount)
+}
+
+func TestGatherTracks_ShouldDetectSymlinkLoopsRatherThanHang(t *testing.T) {
+	tests := map[string]struct {
+		fs afero.Fs
+	}{
+		"OsFs": {
+			fs: afero.NewOsFs(),
+		},
+		"PermissionsFs": {
+			fs: steps.NewPermissionsFs(afero.NewOsFs(), 0640, 0750),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			root := t.TempDir()
+			trackDir := filepath.Join(root, "tracks", "track")
+			require.NoError(t, os.MkdirAll(filepath.Join(trackDir, "step1_deploy"), 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(trackDir, "step1_deploy", "main.tf"), []byte(""), 0644))
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "tracks", "other", "step1_deploy"), 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(root, "tracks", "other", "step1_deploy", "main.tf"), []byte(""), 0644))
+
+			// a step linking back to its own track, and a pair of links pointing at each other
+			if err := os.Symlink(trackDir, filepath.Join(trackDir, "step2_cycle")); err != nil {
+				t.Skipf("symlinks are not supported: %v", err)
+			}
+			otherDir := filepath.Join(root, "tracks", "other")
+			require.NoError(t, os.Symlink(filepath.Join(otherDir, "step2_b"), filepath.Join(otherDir, "step2_a")))
+			require.NoError(t, os.Symlink(filepath.Join(otherDir, "step2_a"), filepath.Join(otherDir, "step2_b")))
+
+			stubTracker := tracks.DirectoryBasedTracker{
+				Fs:  test.fs,
+				Log: logger,
+			}
+
+			// act
+			done := make(chan error, 1)
+			go func() {
+				_, err := stubTracker.GatherTracks(config.Config{
+					TargetAll:  true,
+					TrackRoots: []string{root},
+				})
+				done <- err
+			}()
+
+			// assert
+			select {
+			case err := <-done:
+				require.Error(t, err, "Tracks containing symlink loops should fail gathering")
+				require.Contains(t, err.Error(), "symlink")
+			case <-time.After(10 * time.Second):
+				require.FailNow(t, "Gathering tracks with symlink loops did not complete")
+			}
+		})
+	}
+}
+
+func TestGatherTracks_ShouldFailOnStepsBeyondMaxTrackDepth(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_deploy/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	shallowTracks, shallowErr := stubTracker.GatherTracks(config.Config{TargetAll: true, MaxTrackDepth: 1})
+	deepTracks, deepErr := stubTracker.GatherTracks(config.Config{TargetAll: true, MaxTrackDepth: 2})
+
+	// assert
+	require.Error(t, shallowErr, "Steps deeper than the max track depth should fail gathering")
+	require.Contains(t, shallowErr.Error(), "maximum track depth")
+	require.Empty(t, shallowTracks)
+	require.NoError(t, deepErr)
+	require.Len(t, deepTracks, 1)
+}
+
+func TestGatherTracks_ShouldFailOnTrackWithInvalidRegionDeployTypes(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/invalid/runiac.yml", []byte("region_deploy_types:\n  - global\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/invalid/step1_iam/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/regional-only/runiac.yml", []byte("region_deploy_types:\n  - regional\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/regional-only/step1_iam/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+
+	// assert
+	require.Error(t, err, "Tracks with invalid region_deploy_types should fail gathering")
+	require.Contains(t, err.Error(), "invalid")
+	require.Empty(t, mockTracks)
+}
+
+func TestGatherTracks_ShouldReadStepRunnerOverride(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track-a/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track-a/step1_deploy/runiac.yaml", []byte("runner: terraform\n"), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+	require.Equal(t, "track-a", mockTracks[0].Name)
+	require.Equal(t, "terraform", mockTracks[0].OrderedSteps[1][0].Config.Runner, "Step configuration should be read")
+	require.NotNil(t, mockTracks[0].OrderedSteps[1][0].Runner)
+}
+
+func TestGatherTracks_ShouldFailOnUnknownStepRunner(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track-a/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track-b/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track-b/step1_deploy/runiac.yaml", []byte("runner: doesnotexist\n"), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+
+	// assert
+	require.Error(t, err, "A track with an unknown runner should fail gathering rather than be excluded")
+	require.Contains(t, err.Error(), "track-b")
+	require.Empty(t, mockTracks)
+}
+
+func TestGatherTracks_ShouldFailOnUnreadableDefaultTrack(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "step1_deploy/runiac.yaml", []byte("runner: doesnotexist\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track-a/step1_deploy/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+
+	// assert
+	require.Error(t, err, "An unreadable default track should fail gathering like any other track")
+	require.Contains(t, err.Error(), tracks.DEFAULT_TRACK_NAME)
+	require.Empty(t, mockTracks)
+}
+
+func TestGatherTracks_ShouldSkipStepsTargetingAnotherCSP(t *testing.T) {
+	tests := map[string]struct {
+		csp           string
+		expectedSteps map[string]string // K=step name, V=the CSP it targets
+	}{
+		"AWS skips AZU only steps": {
+			csp:           "AWS",
+			expectedSteps: map[string]string{"any": "", "aws": "AWS"},
+		},
+		"CSPs are case insensitive": {
+			csp:           "azu",
+			expectedSteps: map[string]string{"any": "", "azure": "AZU"},
+		},
+		"no CSP deploys every step": {
+			expectedSteps: map[string]string{"any": "", "aws": "AWS", "azure": "AZU"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(stubFs, "tracks/track/step1_aws/main.tf", []byte(""), 0644)
+			_ = afero.WriteFile(stubFs, "tracks/track/step1_aws/runiac.yaml", []byte("csp: AWS\n"), 0644)
+			_ = afero.WriteFile(stubFs, "tracks/track/step1_azure/main.tf", []byte(""), 0644)
+			_ = afero.WriteFile(stubFs, "tracks/track/step1_azure/runiac.yaml", []byte("csp: AZU\n"), 0644)
+			_ = afero.WriteFile(stubFs, "tracks/track/step2_any/main.tf", []byte(""), 0644)
+
+			stubTracker := tracks.DirectoryBasedTracker{
+				Fs:  stubFs,
+				Log: logger,
+			}
+
+			// act
+			mockTracks, err := stubTracker.GatherTracks(config.Config{
+				TargetAll: true,
+				CSP:       tc.csp,
+			})
+			require.NoError(t, err)
+
+			// assert
+			require.Len(t, mockTracks, 1)
+
+			gathered := map[string]string{}
+			for _, progression := range mockTracks[0].OrderedSteps {
+				for _, step := range progression {
+					gathered[step.Name] = step.CSP
+				}
+			}
+
+			require.Equal(t, tc.expectedSteps, gathered, "Steps targeting another CSP should be skipped")
+		})
+	}
+}
+
+func TestGatherTracks_ShouldMergeStepOverridesForRegionGroup(t *testing.T) {
+	tests := map[string]struct {
+		regionGroup         string
+		expectedParallelism int
+		expectedTestCommand string
+	}{
+		"ShouldUseOverrideInEuRegionGroup": {
+			regionGroup:         "eu",
+			expectedParallelism: 2,
+			expectedTestCommand: "make test-eu",
+		},
+		"ShouldUseDefaultInUsRegionGroup": {
+			regionGroup:         "us",
+			expectedParallelism: 10,
+			expectedTestCommand: "make test",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+			_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/runiac.yaml", []byte(`
+terraform_parallelism: 10
+test_command: make test
+expected_outputs:
+  - vpc_id
+per_region_group:
+  eu:
+    terraform_parallelism: 2
+    test_command: make test-eu
+`), 0644)
+
+			stubTracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+			// act
+			mockTracks, err := stubTracker.GatherTracks(config.Config{
+				TargetAll:   true,
+				RegionGroup: test.regionGroup,
+			})
+			require.NoError(t, err)
+
+			// assert
+			require.Len(t, mockTracks, 1)
+
+			stepConfig := mockTracks[0].OrderedSteps[1][0].Config
+			require.Equal(t, test.expectedParallelism, stepConfig.TerraformParallelism)
+			require.Equal(t, test.expectedTestCommand, stepConfig.TestCommand)
+			require.Equal(t, []string{"vpc_id"}, stepConfig.ExpectedOutputs, "Configuration without an override should be kept")
+		})
+	}
+}
+
+func TestGatherTracks_ShouldOverrideRetriesWithStepConfig(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/iam/step1_roles/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/iam/step1_roles/runiac.yaml", []byte("retryable_failure_retries: 5\nretry_backoff: 30s\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/iam/step1_policies/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll:               true,
+		RetryableFailureRetries: 1,
+		RetryBackoff:            time.Second,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+
+	deployConfigs := map[string]config.Config{}
+	for _, s := range mockTracks[0].OrderedSteps[1] {
+		deployConfigs[s.Name] = s.DeployConfig
+	}
+	require.Equal(t, 5, deployConfigs["roles"].RetryableFailureRetries)
+	require.Equal(t, 30*time.Second, deployConfigs["roles"].RetryBackoff)
+	require.Equal(t, 1, deployConfigs["policies"].RetryableFailureRetries, "Steps without an override should use the configured retries")
+	require.Equal(t, time.Second, deployConfigs["policies"].RetryBackoff)
+}
+
 func shouldHaveTests(s []config.Step, e string) bool {
 	for _, a := range s {
 		if a.Name == e {
@@ -298,7 +892,7 @@ func TestExecuteTracks_SkipsAllTracksIfPreTrackFails(t *testing.T) {
 	}
 	deployTrackExecutionSpy := []tracks.Execution{}
 
-	tracks.DeployTrack = func(execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
 		execution.Output.Name = t.Name
 		deployTrackExecutionSpy = append(deployTrackExecutionSpy, execution)
 		out <- deployTrackStub[t.Name]
@@ -306,7 +900,7 @@ func TestExecuteTracks_SkipsAllTracksIfPreTrackFails(t *testing.T) {
 	}
 
 	// act
-	mockExecution := sut.ExecuteTracks(config.Config{
+	mockExecution := sut.ExecuteTracks(context.Background(), config.Config{
 		TargetAll:   true,
 		SelfDestroy: true,
 	})
@@ -320,533 +914,4243 @@ func TestExecuteTracks_SkipsAllTracksIfPreTrackFails(t *testing.T) {
 	for _, tr := range mockExecution.Tracks {
 		if tr.Name != tracks.PRE_TRACK_NAME {
 			require.True(t, tr.Skipped, "All other tracks should be skipped")
+			require.Equal(t, tracks.SkipReasonPreTrackFailed, tr.SkipReason, "Tracks should record the pretrack failure as their skip reason")
+		} else {
+			require.Empty(t, tr.SkipReason, "The pretrack should not record a skip reason")
 		}
 	}
 }
 
-func TestExecuteTracks_ShouldHandleRegionalAutoDestroyWithRegionalOutputVariables(t *testing.T) {
+func TestExecuteTracks_ShouldExecuteTracksIndependentOfPreTrackAlongsideIt(t *testing.T) {
 	// arrange
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	stubFs := afero.NewMemMapFs()
+	for _, track := range []string{"_pretrack", "independent", "dependent"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+	}
+	_ = afero.WriteFile(stubFs, "tracks/independent/runiac.yaml", []byte("independent_of_pretrack: true\n"), 0644)
 
-	stubPrimaryStepOutputVars := map[string]map[string]string{
-		"step1": {
-			"primary": "primary",
-		},
+	independentStarted := make(chan struct{})
+	preTrackFinished := make(chan struct{})
+
+	var mu sync.Mutex
+	var independentStartedDuringPreTrack, dependentStartedAfterPreTrack, independentHadPreTrackOutput bool
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		switch t.Name {
+		case "_pretrack":
+			// the pretrack only completes once the independent track has started
+			select {
+			case <-independentStarted:
+				mu.Lock()
+				independentStartedDuringPreTrack = true
+				mu.Unlock()
+			case <-time.After(5 * time.Second):
+			}
+			close(preTrackFinished)
+		case "independent":
+			mu.Lock()
+			independentHadPreTrackOutput = execution.PreTrackOutput != nil
+			mu.Unlock()
+			close(independentStarted)
+		case "dependent":
+			select {
+			case <-preTrackFinished:
+				mu.Lock()
+				dependentStartedAfterPreTrack = execution.PreTrackOutput != nil
+				mu.Unlock()
+			default:
+			}
+		}
+
+		out <- tracks.Output{Name: t.Name}
 	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
 
-	stubRegionalStepOutputVars := map[string]map[string]string{
-		"step1": {
-			"regional": "regional",
-		},
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
 	}
 
-	stubRegionalRegion := "regionalregion"
-	stubPrimaryRegion := "primaryregion"
+	// act
+	mockStage := stubTracker.ExecuteTracks(context.Background(), config.Config{TargetAll: true})
 
-	deployTrackStub := map[string]tracks.Output{
-		"_pretrack": {
-			Name:                       "_pretrack",
-			PrimaryStepOutputVariables: stubPrimaryStepOutputVars,
-			Executions: []tracks.RegionExecution{
-				{
-					Output: tracks.ExecutionOutput{
-						StepOutputVariables: stubPrimaryStepOutputVars,
-					},
-					Region:           stubPrimaryRegion,
-					RegionDeployType: config.PrimaryRegionDeployType,
-				},
-			},
-		},
-		"track-a": {
-			Name:                       "track-a",
-			PrimaryStepOutputVariables: stubPrimaryStepOutputVars,
-			Executions: []tracks.RegionExecution{
-				{
-					Output: tracks.ExecutionOutput{
-						StepOutputVariables: stubPrimaryStepOutputVars,
-					},
-					Region:           stubPrimaryRegion,
-					RegionDeployType: config.PrimaryRegionDeployType,
-				},
-				{
-					Output: tracks.ExecutionOutput{
-						StepOutputVariables: stubRegionalStepOutputVars,
-					},
-					Region:           stubRegionalRegion,
-					RegionDeployType: config.RegionalRegionDeployType,
-				},
-			},
-		},
-		"track-b": {
-			Name:                       "track-b",
-			PrimaryStepOutputVariables: nil,
-			Executions:                 nil,
-		},
+	// assert
+	require.True(t, independentStartedDuringPreTrack, "Independent track should start without waiting for the pretrack")
+	require.False(t, independentHadPreTrackOutput, "Independent track should not receive the pretrack's outputs")
+	require.True(t, dependentStartedAfterPreTrack, "Dependent track should wait for the pretrack")
+	require.Len(t, mockStage.Tracks, 3)
+	require.Equal(t, "independent", mockStage.Tracks["independent"].Output.Name, "Independent track output should be recorded")
+}
+
+func TestExecuteTracks_ShouldExecuteTracksIndependentOfPreTrackWithinMaxParallelTracks(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	for _, track := range []string{"_pretrack", "independent", "dependent"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
 	}
-	var destroyTrackASpy tracks.Execution
-	deployTrackExecutionSpy := []tracks.Execution{}
+	_ = afero.WriteFile(stubFs, "tracks/independent/runiac.yaml", []byte("independent_of_pretrack: true\n"), 0644)
 
-	tracks.DeployTrack = func(execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
-		execution.Output.Name = t.Name
-		deployTrackExecutionSpy = append(deployTrackExecutionSpy, execution)
-		out <- deployTrackStub[t.Name]
-		return
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		if t.Name == "_pretrack" {
+			// the independent track waits on the slot, taking it ahead of the dependent track
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		out <- tracks.Output{Name: t.Name}
 	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
 
-	tracks.DestroyTrack = func(execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
-		if t.Name == "track-a" {
-			destroyTrackASpy = execution
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	done := make(chan tracks.Stage)
+
+	// act
+	go func() {
+		done <- stubTracker.ExecuteTracks(context.Background(), config.Config{TargetAll: true, MaxParallelTracks: 1})
+	}()
+
+	// assert
+	select {
+	case mockStage := <-done:
+		require.Len(t, mockStage.Tracks, 3)
+		for _, name := range []string{"_pretrack", "independent", "dependent"} {
+			require.Equal(t, name, mockStage.Tracks[name].Output.Name, "Every track should execute")
+		}
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Tracks should not wait on each other for the single track slot")
+	}
+}
+
+func stubPostTrackTracker(postTrackConfig string) tracks.DirectoryBasedTracker {
+	stubFs := afero.NewMemMapFs()
+	for _, track := range []string{"_posttrack", "network", "app"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+	}
+	_ = afero.WriteFile(stubFs, "tracks/_posttrack/runiac.yaml", []byte(postTrackConfig), 0644)
+
+	return tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+}
+
+func TestExecuteTracks_ShouldExecutePostTrackAfterAllTracksAndDestroyItFirst(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var deployed, destroyed []string
+	var upstreamTrackOutputs map[string]tracks.Output
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		deployed = append(deployed, t.Name)
+		if t.IsPostTrack {
+			upstreamTrackOutputs = execution.UpstreamTrackOutputs
 		}
+		mutex.Unlock()
 
 		out <- tracks.Output{
-			Name:                       "",
-			PrimaryStepOutputVariables: nil,
-			Executions:                 nil,
+			Name: t.Name,
+			Executions: []tracks.RegionExecution{{
+				RegionDeployType: config.PrimaryRegionDeployType,
+				Region:           "us-east-1",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{"deploy": {"id": t.Name}},
+				},
+			}},
 		}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
 
-		return
+	tracks.DestroyTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		destroyed = append(destroyed, t.Name)
+		mutex.Unlock()
+
+		out <- tracks.Output{Name: t.Name}
 	}
+	defer func() { tracks.DestroyTrack = tracks.ExecuteDestroyTrack }()
 
 	// act
-	mockExecution := sut.ExecuteTracks(config.Config{
-		TargetAll:   true,
-		SelfDestroy: true,
+	mockExecution := stubPostTrackTracker("").ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		SelfDestroy:   true,
+		PrimaryRegion: "us-east-1",
 	})
 
-	require.NotNil(t, mockExecution)
+	// assert
+	require.Len(t, deployed, 3)
+	require.Equal(t, tracks.POST_TRACK_NAME, deployed[2], "Post-track should be deployed after every other track")
+	require.Len(t, destroyed, 3)
+	require.Equal(t, tracks.POST_TRACK_NAME, destroyed[0], "Post-track should be destroyed before every other track")
+	require.False(t, mockExecution.Tracks[tracks.POST_TRACK_NAME].Skipped)
 
-	for _, executionSpy := range deployTrackExecutionSpy {
-		require.Contains(t, []string{"_pretrack", "track-a", "track-b"}, executionSpy.Output.Name, "Should execute correct tracks")
-	}
-	require.Equal(t, stubPrimaryStepOutputVars, destroyTrackASpy.DefaultExecutionStepOutputVariables[config.PrimaryRegionDeployType.String()+"-"+stubPrimaryRegion], "Should pass primary step output vars to destroy")
-	require.Equal(t, stubRegionalStepOutputVars, destroyTrackASpy.DefaultExecutionStepOutputVariables[config.RegionalRegionDeployType.String()+"-"+stubRegionalRegion], "Should pass regional region specific step output vars to destroy")
+	require.Len(t, upstreamTrackOutputs, 2, "Post-track should receive the outputs of every other track")
+	vars := tracks.AppendUpstreamTrackOutputsToDefaultStepOutputVariables(nil, upstreamTrackOutputs, config.PrimaryRegionDeployType, "us-east-1")
+	require.Equal(t, map[string]map[string]string{
+		"network-deploy": {"id": "network"},
+		"app-deploy":     {"id": "app"},
+		"network.deploy": {"id": "network"},
+		"app.deploy":     {"id": "app"},
+	}, vars, "Upstream outputs should be keyed by track and step")
 }
 
-func TestExecuteDeployTrack_ShouldExecuteCorrectStepsAndRegions(t *testing.T) {
-	// arrange
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	var test = map[string]struct {
-		stubExecutedFailCount     int
-		stubExecutedTestFailCount int
-		stubRegionalDeployment    bool
-		regionGroup               string
-		stubTargetRegions         []string
-		expectedCallCount         int
+func TestExecuteTracks_ShouldSkipPostTrackWhenTracksFailUnlessRunOnFailure(t *testing.T) {
+	tests := map[string]struct {
+		postTrackConfig  string
+		expectedSkipped  bool
+		expectedDeployed int
 	}{
-		"ShouldExecutePrimaryRegionOnceWithNoRegionalResources": {
-			stubExecutedFailCount:     0,
-			stubExecutedTestFailCount: 0,
-			stubRegionalDeployment:    false,
-			regionGroup:               "us",
-			stubTargetRegions:         []string{"us-east-1"},
-			expectedCallCount:         1,
-		},
-		"ShouldExecutePrimaryRegionTwiceWithRegionalResources": {
-			stubExecutedFailCount:     0,
-			stubExecutedTestFailCount: 0,
-			stubRegionalDeployment:    true,
-			regionGroup:               "us",
-			stubTargetRegions:         []string{"us-east-1"},
-			expectedCallCount:         2,
+		"ShouldSkipPostTrack": {
+			postTrackConfig:  "",
+			expectedSkipped:  true,
+			expectedDeployed: 2,
 		},
-		"ShouldExecuteN+PrimaryTimesWithRegionalResourcesTargetingNRegions": {
-			stubExecutedFailCount:     0,
-			stubExecutedTestFailCount: 0,
-			stubRegionalDeployment:    true,
-			regionGroup:               "us",
-			stubTargetRegions:         []string{"us-east-1", "us-east-2"},
-			expectedCallCount:         3,
+		"ShouldRunPostTrackOnFailure": {
+			postTrackConfig:  "run_on_failure: true\n",
+			expectedSkipped:  false,
+			expectedDeployed: 3,
 		},
 	}
 
-	executionParams := []tracks.RegionExecution{}
-
-	for name, test := range test {
+	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			// arrange
+			var mutex sync.Mutex
+			deployed := 0
 
-			var callCount int
-			tracks.DeployTrackRegion = func(in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
-				regionExecution := <-in
-				callCount++
-
-				executionParams = append(executionParams, regionExecution)
+			tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+				mutex.Lock()
+				deployed++
+				mutex.Unlock()
 
-				regionExecution.Output = tracks.ExecutionOutput{
-					FailureCount:        test.stubExecutedFailCount,
-					FailedTestCount:     test.stubExecutedTestFailCount,
-					StepOutputVariables: regionExecution.DefaultStepOutputVariables,
+				output := tracks.Output{Name: t.Name}
+				if t.Name == "network" {
+					output.Err = errors.New("network failed")
 				}
+				out <- output
+			}
+			defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
 
-				regionExecution.Output.StepOutputVariables["test-regional"] = map[string]string{
-					"region": regionExecution.Region,
-				}
+			// act
+			mockExecution := stubPostTrackTracker(test.postTrackConfig).ExecuteTracks(context.Background(), config.Config{
+				TargetAll:     true,
+				PrimaryRegion: "us-east-1",
+			})
 
-				out <- regionExecution
-				return
+			// assert
+			require.Equal(t, test.expectedDeployed, deployed)
+			require.Equal(t, test.expectedSkipped, mockExecution.Tracks[tracks.POST_TRACK_NAME].Skipped)
+			if test.expectedSkipped {
+				require.Equal(t, tracks.SkipReasonUpstreamTrackFailed, mockExecution.Tracks[tracks.POST_TRACK_NAME].SkipReason)
 			}
+		})
+	}
+}
 
-			trackChan := make(chan tracks.Output, 1)
+func TestExecuteTracks_ShouldSkipPreTrackAndUsePersistedOutputs(t *testing.T) {
+	tests := map[string]struct {
+		persisted              bool
+		expectedPreTrackOutput map[string]map[string]string
+	}{
+		"ShouldUsePersistedPreTrackOutputs": {
+			persisted:              true,
+			expectedPreTrackOutput: map[string]map[string]string{"pretrack-project": {"id": "123"}, "_pretrack.project": {"id": "123"}},
+		},
+		"ShouldExecuteTracksWithoutPreTrackOutputs": {
+			persisted: false,
+		},
+	}
 
-			// act
-			tracks.ExecuteDeployTrack(tracks.Execution{
-				Logger: logger,
-				Fs:     fs,
-				Output: tracks.ExecutionOutput{},
-			}, config.Config{
-				RegionalRegions: test.stubTargetRegions,
-				RegionGroup:     test.regionGroup,
-			}, tracks.Track{
-				RegionalDeployment: test.stubRegionalDeployment,
-			}, trackChan)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			for _, track := range []string{"_pretrack", "network", "app"} {
+				_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+			}
+			if test.persisted {
+				_ = afero.WriteFile(stubFs, "outputs/_pretrack/primary-us-east-1.json", []byte(`{"project":{"id":"123"}}`), 0644)
+			}
 
-			mockOutput := <-trackChan
+			var mutex sync.Mutex
+			var deployed, destroyed []string
+			preTrackOutputs := map[string]*tracks.Output{}
 
-			require.Len(t, mockOutput.Executions, callCount)
+			tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+				mutex.Lock()
+				deployed = append(deployed, t.Name)
+				preTrackOutputs[t.Name] = execution.PreTrackOutput
+				mutex.Unlock()
 
-			for _, exec := range mockOutput.Executions {
-				require.Equal(t, exec.Region, exec.Output.StepOutputVariables["test-regional"]["region"], "Region variables should stay scoped to executing region function")
+				out <- tracks.Output{Name: t.Name}
 			}
+			defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
 
-			require.Equal(t, map[string]map[string]string(nil), executionParams[0].Output.StepOutputVariables, "Primary execution should start with no incoming previous step variables")
-			require.Equal(t, test.stubExecutedFailCount*callCount, mockOutput.Executions[0].Output.FailureCount, "Should correctly set failed step count")
-			require.Equal(t, config.PrimaryRegionDeployType, executionParams[0].RegionDeployType, "First execution should be primary region")
-			require.Equal(t, test.expectedCallCount, callCount, "Should call DeployTrackRegion() the expected amount of times")
-		})
-	}
-}
+			tracks.DestroyTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+				mutex.Lock()
+				destroyed = append(destroyed, t.Name)
+				mutex.Unlock()
 
-func TestAddToTrackOutput(t *testing.T) {
-	stepOutputVariables := make(map[string]interface{})
-	stepOutputVariables["resource_name"] = "my-cool-resource"
-	stepOutputVariables["resource_id"] = "resource/my-cool-resource"
+				out <- tracks.Output{Name: t.Name}
+			}
+			defer func() { tracks.DestroyTrack = tracks.ExecuteDestroyTrack }()
 
-	stepOutput := config.StepOutput{
-		OutputVariables: stepOutputVariables,
-		StepName:        "cool_step1",
-	}
+			// act
+			mockExecution := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+				TargetAll:          true,
+				SkipPreTrack:       true,
+				SelfDestroy:        true,
+				OutputVariablesDir: "outputs",
+				PrimaryRegion:      "us-east-1",
+			})
 
-	trackOutputVars := make(map[string]map[string]string)
+			// assert
+			require.ElementsMatch(t, []string{"network", "app"}, deployed, "Pre-track should not be deployed")
+			require.ElementsMatch(t, []string{"network", "app"}, destroyed, "Pre-track should not be destroyed")
+			require.True(t, mockExecution.Tracks[tracks.PRE_TRACK_NAME].Skipped)
+			require.Equal(t, tracks.SkipReasonPreTrackSkipped, mockExecution.Tracks[tracks.PRE_TRACK_NAME].SkipReason)
 
-	mockPrevStepVars := tracks.AppendTrackOutput(trackOutputVars, stepOutput)
+			for _, track := range []string{"network", "app"} {
+				if test.expectedPreTrackOutput == nil {
+					require.Nil(t, preTrackOutputs[track])
+					continue
+				}
 
-	require.Equal(t, "my-cool-resource", mockPrevStepVars[stepOutput.StepName]["resource_name"], "The track output should have the correct key and value set")
-	require.Equal(t, "resource/my-cool-resource", mockPrevStepVars[stepOutput.StepName]["resource_id"], "The track output should have the correct key and value set")
+				require.NotNil(t, preTrackOutputs[track])
+				vars := tracks.AppendPreTrackOutputsToDefaultStepOutputVariables(map[string]map[string]string{}, preTrackOutputs[track], config.PrimaryRegionDeployType, "us-east-1")
+				require.Equal(t, test.expectedPreTrackOutput, vars)
+			}
+		})
+	}
 }
 
-func TestAppendPreTrackOutputsToDefaultStepOutputVariables_AddsPrimaryRegionExecutionsFromPreTrackToVars(t *testing.T) {
-	// Mock existing step output vars
-	defaultStepOutputVariables := make(map[string]map[string]string)
-	defaultStepOutputVariables["iam"] = map[string]string{
-		"var1": "out1",
+func TestExecuteTracks_ShouldDestroyOnlyUsingPersistedStepOutputs(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	for _, track := range []string{"_pretrack", "network", "app"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
 	}
+	_ = afero.WriteFile(stubFs, "tracks/app/runiac.yaml", []byte("depends_on:\n  - network\n"), 0644)
 
-	preTrackOutputs := &tracks.Output{
-		Name: tracks.PRE_TRACK_NAME,
-		PrimaryStepOutputVariables: map[string]map[string]string{
-			"account": {
-				"name": "new-account",
-			},
-		},
-		Executions: []tracks.RegionExecution{
-			{
+	for track, vars := range map[string]map[string]map[string]string{
+		"_pretrack": {"project": {"id": "123"}},
+		"network":   {"deploy": {"vpc_id": "vpc-1"}},
+	} {
+		require.NoError(t, tracks.WriteOutputVariableFiles(stubFs, "outputs", tracks.Output{
+			Name: track,
+			Executions: []tracks.RegionExecution{{
 				RegionDeployType: config.PrimaryRegionDeployType,
-				Region:           "centralus",
-				Output: tracks.ExecutionOutput{
-					StepOutputVariables: map[string]map[string]string{
-						"account": {
-							"name": "new-account",
-						},
-					},
-				},
-			},
-			{
-				RegionDeployType: config.RegionalRegionDeployType,
-				Region:           "centralus",
-				Output: tracks.ExecutionOutput{
-					StepOutputVariables: map[string]map[string]string{
-						"account": {
-							"group": "new-group",
-						},
-					},
-				},
-			},
-		},
+				Region:           "us-east-1",
+				Output:           tracks.ExecutionOutput{StepOutputVariables: vars},
+			}},
+		}))
 	}
 
-	regionDeployType := config.PrimaryRegionDeployType
-	region := "centralus"
+	var mutex sync.Mutex
+	var deployed, destroyed []string
+	destroyExecutions := map[string]tracks.Execution{}
 
-	newDefaultStepOutputVariables := tracks.AppendPreTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariables, preTrackOutputs, regionDeployType, region)
-	require.NotEmpty(t, newDefaultStepOutputVariables, "The new map should not be empty")
-	require.Equal(t, 2, len(newDefaultStepOutputVariables), "The map should contain the expected number of keys")
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		deployed = append(deployed, t.Name)
+		mutex.Unlock()
 
-	// Existing step output vars should remain
-	iamStepOutVarMap, iamKeyExists := newDefaultStepOutputVariables["iam"]
-	require.True(t, iamKeyExists, "The map should still contain the original iam key")
-	require.Equal(t, 1, len(iamStepOutVarMap), "The iam step should have the expected number of out vars")
-	var1Value, iamKeyVar1KeyExists := iamStepOutVarMap["var1"]
-	require.True(t, iamKeyVar1KeyExists, "The original key in iam should still exist")
-	require.Equal(t, "out1", var1Value, "The output in the iam step for var1 should be the expected value")
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
 
-	// Pretrack outputs from the primary region only (since this was a primary region deployment) should be added
-	preTrackAccountStepOutVarMap, preTrackKeyExists := newDefaultStepOutputVariables["pretrack-account"]
-	require.True(t, preTrackKeyExists, "There should be a key for the pretrack in the expected naming format")
-	require.Equal(t, 1, len(preTrackAccountStepOutVarMap), "The pre track account step should have the expected number of out vars")
-	accountKeyVal, accountKeyExists := preTrackAccountStepOutVarMap["name"]
-	require.True(t, accountKeyExists, "The name output var from the pretrack account step should be added")
-	require.Equal(t, "new-account", accountKeyVal, "The name output var from the pretrack account step should have the expected value")
-}
+	tracks.DestroyTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		destroyed = append(destroyed, t.Name)
+		destroyExecutions[t.Name] = execution
+		mutex.Unlock()
 
-func TestAppendPreTrackOutputsToDefaultStepOutputVariables_AddsRegionalExecutionsFromPreTrackToVars(t *testing.T) {
-	// Mock existing step output vars
-	defaultStepOutputVariables := make(map[string]map[string]string)
-	defaultStepOutputVariables["iam"] = map[string]string{
-		"var1": "out1",
+		out <- tracks.Output{Name: t.Name}
 	}
+	defer func() { tracks.DestroyTrack = tracks.ExecuteDestroyTrack }()
 
-	preTrackOutputs := &tracks.Output{
-		Name: tracks.PRE_TRACK_NAME,
+	// act
+	tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:          true,
+		DestroyOnly:        true,
+		OutputVariablesDir: "outputs",
+		PrimaryRegion:      "us-east-1",
+	})
+
+	// assert
+	require.Empty(t, deployed, "Tracks should not be deployed")
+	require.Equal(t, []string{"app", "network", tracks.PRE_TRACK_NAME}, destroyed, "Tracks should be destroyed in reverse dependency order, then the pre-track")
+
+	require.Equal(t, map[string]map[string]string{"deploy": {"vpc_id": "vpc-1"}}, destroyExecutions["network"].DefaultExecutionStepOutputVariables["primary-us-east-1"], "Persisted step outputs should be loaded for the destroy")
+	require.Empty(t, destroyExecutions["app"].DefaultExecutionStepOutputVariables, "Tracks without persisted step outputs should be destroyed without them")
+
+	require.NotNil(t, destroyExecutions["network"].PreTrackOutput)
+	vars := tracks.AppendPreTrackOutputsToDefaultStepOutputVariables(map[string]map[string]string{}, destroyExecutions["network"].PreTrackOutput, config.PrimaryRegionDeployType, "us-east-1")
+	require.Equal(t, map[string]map[string]string{"pretrack-project": {"id": "123"}, "_pretrack.project": {"id": "123"}}, vars, "Persisted pre-track outputs should be available to the destroy")
+}
+
+func TestExecuteTracks_ShouldFailTrackThatTimesOut(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	for _, track := range []string{"hung", "app"} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		if t.Name == "hung" {
+			// the track is signalled to stop, but keeps hanging
+			<-ctx.Done()
+			<-release
+		}
+
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	executed := make(chan tracks.Stage)
+
+	// act
+	go func() {
+		executed <- tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+			TargetAll:     true,
+			PrimaryRegion: "us-east-1",
+			TrackTimeout:  50 * time.Millisecond,
+		})
+	}()
+
+	// assert
+	var mockExecution tracks.Stage
+	select {
+	case mockExecution = <-executed:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Tracks should not hang on a track that timed out")
+	}
+
+	require.Error(t, mockExecution.Tracks["hung"].Output.Err)
+	require.Contains(t, mockExecution.Tracks["hung"].Output.Err.Error(), "timed out")
+	require.NoError(t, mockExecution.Tracks["app"].Output.Err, "Tracks completing in time should not fail")
+}
+
+func TestExecuteTracks_ShouldWaitForTimedOutTrackToStopWithinShutdownGracePeriod(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/slow/step1_deploy/main.tf", []byte(""), 0644)
+
+	var mutex sync.Mutex
+	stopped := false
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		// the track's runners take a moment to exit after being interrupted
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+
+		mutex.Lock()
+		stopped = true
+		mutex.Unlock()
+
+		out <- tracks.Output{
+			Name:       t.Name,
+			Executions: []tracks.RegionExecution{{TrackName: t.Name, Region: "us-east-1", RegionDeployType: config.PrimaryRegionDeployType}},
+		}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:           true,
+		PrimaryRegion:       "us-east-1",
+		TrackTimeout:        10 * time.Millisecond,
+		ShutdownGracePeriod: 5 * time.Second,
+	})
+
+	// assert
+	require.Error(t, mockExecution.Tracks["slow"].Output.Err)
+	require.Contains(t, mockExecution.Tracks["slow"].Output.Err.Error(), "timed out")
+	require.Len(t, mockExecution.Tracks["slow"].Output.Executions, 1, "The timed out track's output should be kept when it stops within the grace period")
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.True(t, stopped, "Tracks should not complete before the timed out track stopped")
+}
+
+func stubStagedTracker() tracks.DirectoryBasedTracker {
+	stubFs := afero.NewMemMapFs()
+	for track, stage := range map[string]string{"network": "bootstrap", "iam": "bootstrap", "cluster": "platform", "dns": "platform", "app": ""} {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+		if stage != "" {
+			_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/runiac.yaml", track), []byte(fmt.Sprintf("stage: %s\n", stage)), 0644)
+		}
+	}
+
+	return tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+}
+
+func TestExecuteTracks_ShouldExecuteStagesInOrderWithTracksInParallel(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var events []string
+	inFlight := map[string]int{}
+	maxInFlight := map[string]int{}
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		events = append(events, "start:"+t.Config.Stage)
+		inFlight[t.Config.Stage]++
+		if inFlight[t.Config.Stage] > maxInFlight[t.Config.Stage] {
+			maxInFlight[t.Config.Stage] = inFlight[t.Config.Stage]
+		}
+		mutex.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mutex.Lock()
+		events = append(events, "end:"+t.Config.Stage)
+		inFlight[t.Config.Stage]--
+		mutex.Unlock()
+
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := stubStagedTracker().ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		PrimaryRegion: "us-east-1",
+		Stages:        []string{"bootstrap", "platform"},
+	})
+
+	// assert
+	require.Len(t, mockExecution.Tracks, 5)
+	require.Equal(t, []string{
+		"start:bootstrap", "start:bootstrap", "end:bootstrap", "end:bootstrap",
+		"start:platform", "start:platform", "end:platform", "end:platform",
+		"start:", "end:",
+	}, events, "Each stage should complete before the next begins, with tracks without a stage last")
+	require.Equal(t, map[string]int{"bootstrap": 2, "platform": 2, "": 1}, maxInFlight, "Tracks within a stage should execute in parallel")
+}
+
+func TestExecuteTracks_ShouldLimitParallelTracks(t *testing.T) {
+	tests := map[string]struct {
+		maxParallelTracks   int
+		expectedMaxInFlight int
+	}{
+		"Unlimited": {
+			maxParallelTracks:   0,
+			expectedMaxInFlight: 5,
+		},
+		"Limited": {
+			maxParallelTracks:   2,
+			expectedMaxInFlight: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			for _, track := range []string{"network", "iam", "cluster", "dns", "app"} {
+				_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+			}
+
+			var mutex sync.Mutex
+			inFlight := map[string]int{}
+			maxInFlight := map[string]int{}
+
+			stubExecuteTrack := func(action string) tracks.ExecuteTrackFunc {
+				return func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+					mutex.Lock()
+					inFlight[action]++
+					if inFlight[action] > maxInFlight[action] {
+						maxInFlight[action] = inFlight[action]
+					}
+					mutex.Unlock()
+
+					time.Sleep(50 * time.Millisecond)
+
+					mutex.Lock()
+					inFlight[action]--
+					mutex.Unlock()
+
+					out <- tracks.Output{Name: t.Name}
+				}
+			}
+
+			tracks.DeployTrack = stubExecuteTrack("deploy")
+			tracks.DestroyTrack = stubExecuteTrack("destroy")
+			defer func() {
+				tracks.DeployTrack = tracks.ExecuteDeployTrack
+				tracks.DestroyTrack = tracks.ExecuteDestroyTrack
+			}()
+
+			stubTracker := tracks.DirectoryBasedTracker{
+				Fs:  stubFs,
+				Log: logger,
+			}
+
+			// act
+			mockExecution := stubTracker.ExecuteTracks(context.Background(), config.Config{
+				TargetAll:         true,
+				SelfDestroy:       true,
+				PrimaryRegion:     "us-east-1",
+				MaxParallelTracks: test.maxParallelTracks,
+			})
+
+			// assert
+			require.Len(t, mockExecution.Tracks, 5)
+			require.Equal(t, test.expectedMaxInFlight, maxInFlight["deploy"], "Deployed tracks should respect the limit")
+			require.Equal(t, test.expectedMaxInFlight, maxInFlight["destroy"], "Destroyed tracks should respect the limit")
+		})
+	}
+}
+
+func TestExecuteTracks_ShouldNotStartTracksWhilePaused(t *testing.T) {
+	// arrange
+	pauser := &tracks.Pauser{}
+	tracks.DeploymentPause = pauser
+	defer func() { tracks.DeploymentPause = &tracks.Pauser{} }()
+
+	var mutex sync.Mutex
+	var started []string
+	var completed []string
+
+	// both bootstrap tracks are in flight before pausing
+	var bootstrapStarted sync.WaitGroup
+	bootstrapStarted.Add(2)
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		started = append(started, t.Config.Stage)
+		mutex.Unlock()
+
+		// pausing mid-stage should let the in-flight tracks complete
+		if t.Config.Stage == "bootstrap" {
+			bootstrapStarted.Done()
+			bootstrapStarted.Wait()
+			pauser.Pause()
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		completed = append(completed, t.Config.Stage)
+		mutex.Unlock()
+
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	done := make(chan tracks.Stage)
+
+	// act
+	go func() {
+		done <- stubStagedTracker().ExecuteTracks(context.Background(), config.Config{
+			TargetAll:     true,
+			PrimaryRegion: "us-east-1",
+			Stages:        []string{"bootstrap", "platform"},
+		})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	// assert
+	mutex.Lock()
+	require.Equal(t, []string{"bootstrap", "bootstrap"}, started, "No tracks should start after pausing")
+	require.Equal(t, []string{"bootstrap", "bootstrap"}, completed, "In-flight tracks should complete while paused")
+	mutex.Unlock()
+	require.True(t, pauser.Paused())
+
+	pauser.Resume()
+
+	select {
+	case mockExecution := <-done:
+		require.Len(t, mockExecution.Tracks, 5)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Tracks should resume after resuming")
+	}
+}
+
+func TestExecuteTracks_ShouldCancelTracksWaitingWhilePaused(t *testing.T) {
+	// arrange
+	pauser := &tracks.Pauser{}
+	pauser.Pause()
+	tracks.DeploymentPause = pauser
+	defer func() { tracks.DeploymentPause = &tracks.Pauser{} }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mutex sync.Mutex
+	var cancelled []string
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		// the track's steps are cancelled by executing it with the cancelled context
+		if ctx.Err() != nil {
+			mutex.Lock()
+			cancelled = append(cancelled, t.Name)
+			mutex.Unlock()
+		}
+
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	done := make(chan tracks.Stage)
+
+	// act
+	go func() {
+		done <- stubStagedTracker().ExecuteTracks(ctx, config.Config{
+			TargetAll:     true,
+			PrimaryRegion: "us-east-1",
+			Stages:        []string{"bootstrap", "platform"},
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	// assert
+	select {
+	case <-done:
+		mutex.Lock()
+		require.Contains(t, cancelled, "network", "Tracks waiting while paused should be executed with the cancelled context")
+		mutex.Unlock()
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Cancelling should not wait for the deployment to resume")
+	}
+}
+
+func TestExecuteDeployTrackRegion_ShouldNotStartStepsWhilePaused(t *testing.T) {
+	// arrange
+	pauser := &tracks.Pauser{}
+	pauser.Pause()
+	tracks.DeploymentPause = pauser
+	defer func() { tracks.DeploymentPause = &tracks.Pauser{} }()
+
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	var mutex sync.Mutex
+	var executed []string
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		executed = append(executed, s.Name)
+		mutex.Unlock()
+
+		s.Output = config.StepOutput{Status: config.Success}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	// act
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		TrackStepProgressionsCount: 1,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "step1"}, {Name: "step2"}},
+		},
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// assert
+	mutex.Lock()
+	require.Empty(t, executed, "No steps should start while paused")
+	mutex.Unlock()
+
+	pauser.Resume()
+
+	select {
+	case primaryTrackExecution := <-primaryOutChan:
+		require.Equal(t, 2, primaryTrackExecution.Output.ExecutedCount)
+		require.ElementsMatch(t, []string{"step1", "step2"}, executed)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Steps should start after resuming")
+	}
+}
+
+func TestExecuteDeployTrackRegion_ShouldCancelStepsWaitingWhilePaused(t *testing.T) {
+	// arrange
+	pauser := &tracks.Pauser{}
+	pauser.Pause()
+	tracks.DeploymentPause = pauser
+	defer func() { tracks.DeploymentPause = &tracks.Pauser{} }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		require.Fail(t, "Steps cancelled while paused should not be executed")
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	// act
+	go tracks.ExecuteDeployTrackRegion(ctx, primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		TrackStepProgressionsCount: 1,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "step1"}, {Name: "step2"}},
+		},
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	// assert
+	select {
+	case primaryTrackExecution := <-primaryOutChan:
+		require.Equal(t, 2, primaryTrackExecution.Output.CancelledCount, "Steps waiting while paused should be cancelled")
+		require.Equal(t, 0, primaryTrackExecution.Output.ExecutedCount)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Cancelling should not wait for the deployment to resume")
+	}
+	require.True(t, pauser.Paused())
+}
+
+func stubDependentTracker(dependsOn map[string][]string) tracks.DirectoryBasedTracker {
+	stubFs := afero.NewMemMapFs()
+	for track, dependencies := range dependsOn {
+		_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+		if len(dependencies) > 0 {
+			_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/runiac.yaml", track), []byte(fmt.Sprintf("depends_on: [%s]\n", strings.Join(dependencies, ","))), 0644)
+		}
+	}
+
+	return tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+}
+
+func TestExecuteTracks_ShouldExecuteTracksAfterTheTracksTheyDependOn(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var events []string
+
+	stubExecuteTrack := func(action string) tracks.ExecuteTrackFunc {
+		return func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+			mutex.Lock()
+			events = append(events, fmt.Sprintf("%s:%s", action, t.Name))
+			mutex.Unlock()
+
+			out <- tracks.Output{Name: t.Name}
+		}
+	}
+
+	tracks.DeployTrack = stubExecuteTrack("deploy")
+	tracks.DestroyTrack = stubExecuteTrack("destroy")
+	defer func() {
+		tracks.DeployTrack = tracks.ExecuteDeployTrack
+		tracks.DestroyTrack = tracks.ExecuteDestroyTrack
+	}()
+
+	// act
+	mockExecution := stubDependentTracker(map[string][]string{
+		"network": nil,
+		"cluster": {"network"},
+		"app":     {"cluster", "network"},
+	}).ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		SelfDestroy:   true,
+		PrimaryRegion: "us-east-1",
+	})
+
+	// assert
+	require.NoError(t, mockExecution.Err)
+	require.Equal(t, []string{
+		"deploy:network", "deploy:cluster", "deploy:app",
+		"destroy:app", "destroy:cluster", "destroy:network",
+	}, events, "Tracks should be deployed after, and destroyed before, the tracks they depend on")
+}
+
+func TestExecuteTracks_ShouldSkipTracksDependingOnFailedTracks(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var deployed []string
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		deployed = append(deployed, t.Name)
+		mutex.Unlock()
+
+		output := tracks.Output{Name: t.Name}
+		if t.Name == "network" {
+			output.Err = errors.New("network failed")
+		}
+		out <- output
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := stubDependentTracker(map[string][]string{
+		"network": nil,
+		"iam":     nil,
+		"cluster": {"network"},
+		"app":     {"cluster"},
+	}).ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		PrimaryRegion: "us-east-1",
+	})
+
+	// assert
+	sort.Strings(deployed)
+	require.Equal(t, []string{"iam", "network"}, deployed, "Tracks independent of the failed track should still be deployed")
+	require.True(t, mockExecution.Tracks["cluster"].Skipped)
+	require.Equal(t, tracks.SkipReasonDependencyFailed, mockExecution.Tracks["cluster"].SkipReason)
+	require.True(t, mockExecution.Tracks["app"].Skipped, "Tracks transitively depending on the failed track should be skipped")
+	require.Equal(t, tracks.SkipReasonDependencyFailed, mockExecution.Tracks["app"].SkipReason)
+}
+
+func TestExecuteTracks_ShouldRejectDependencyCyclesBeforeExecuting(t *testing.T) {
+	// arrange
+	deployCount := 0
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		deployCount++
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := stubDependentTracker(map[string][]string{
+		"app":     {"cluster"},
+		"cluster": {"network"},
+		"network": {"app"},
+		"iam":     nil,
+	}).ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		PrimaryRegion: "us-east-1",
+	})
+
+	// assert
+	require.EqualError(t, mockExecution.Err, "track dependencies form a cycle: app -> cluster -> network -> app")
+	require.Equal(t, 0, deployCount, "No tracks should be executed")
+	for _, tr := range mockExecution.Tracks {
+		require.True(t, tr.Skipped)
+		require.Equal(t, tracks.SkipReasonDependencyCycle, tr.SkipReason)
+	}
+}
+
+func TestExecuteTracks_ShouldSkipLaterStagesWhenStageFails(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var deployed []string
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		deployed = append(deployed, t.Name)
+		mutex.Unlock()
+
+		output := tracks.Output{Name: t.Name}
+		if t.Name == "iam" {
+			output.Executions = []tracks.RegionExecution{{
+				Output: tracks.ExecutionOutput{
+					Steps: map[string]config.Step{"deploy": {Output: config.StepOutput{Status: config.Fail}}},
+				},
+			}}
+		}
+		out <- output
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := stubStagedTracker().ExecuteTracks(context.Background(), config.Config{
+		TargetAll:     true,
+		PrimaryRegion: "us-east-1",
+		Stages:        []string{"bootstrap", "platform"},
+	})
+
+	// assert
+	require.ElementsMatch(t, []string{"network", "iam"}, deployed, "Only the failed stage should be executed")
+	for _, name := range []string{"cluster", "dns", "app"} {
+		require.True(t, mockExecution.Tracks[name].Skipped, "Tracks in later stages should be skipped")
+		require.Equal(t, tracks.SkipReasonPreviousStageFailed, mockExecution.Tracks[name].SkipReason)
+	}
+	require.False(t, mockExecution.Tracks["network"].Skipped)
+}
+
+func TestExecuteTracks_ShouldExecuteTracksInTrackOrder(t *testing.T) {
+	tests := map[string]struct {
+		excludeUnlisted bool
+		expectedEvents  []string
+		expectedSkipped []string
+	}{
+		"ShouldExecuteUnlistedTracksAfterListedTracks": {
+			expectedEvents:  []string{"start:cluster", "end:cluster", "start:network", "end:network", "start:iam", "end:iam", "start:app", "end:app"},
+			expectedSkipped: nil,
+		},
+		"ShouldSkipUnlistedTracksWhenExcluded": {
+			excludeUnlisted: true,
+			expectedEvents:  []string{"start:cluster", "end:cluster", "start:network", "end:network", "start:iam", "end:iam"},
+			expectedSkipped: []string{"app"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			for _, track := range []string{"network", "iam", "cluster", "app"} {
+				_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/step1_deploy/main.tf", track), []byte(""), 0644)
+			}
+
+			var mutex sync.Mutex
+			var events []string
+
+			tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+				mutex.Lock()
+				events = append(events, "start:"+t.Name)
+				mutex.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mutex.Lock()
+				events = append(events, "end:"+t.Name)
+				mutex.Unlock()
+
+				out <- tracks.Output{Name: t.Name}
+			}
+			defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+			// act
+			mockExecution := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}.ExecuteTracks(context.Background(), config.Config{
+				TargetAll:                 true,
+				PrimaryRegion:             "us-east-1",
+				TrackOrder:                []string{"cluster", "network", "iam"},
+				TrackOrderExcludeUnlisted: test.excludeUnlisted,
+			})
+
+			// assert
+			require.Equal(t, test.expectedEvents, events, "Listed tracks should execute one at a time in order")
+
+			var skipped []string
+			for _, track := range mockExecution.Tracks {
+				if track.Skipped {
+					skipped = append(skipped, track.Name)
+					require.Equal(t, tracks.SkipReasonNotInTrackOrder, track.SkipReason)
+				}
+			}
+			require.Equal(t, test.expectedSkipped, skipped)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldFormatDefaultTrackStepIDs(t *testing.T) {
+	tests := map[string]struct {
+		includesName  bool
+		whitelist     []string
+		expectedSteps []string
+	}{
+		"ShouldOmitTrackNameByDefault": {
+			whitelist:     []string{"#project#sample"},
+			expectedSteps: []string{"#project#sample"},
+		},
+		"ShouldNotMatchTrackNameByDefault": {
+			whitelist:     []string{"#project#default#sample"},
+			expectedSteps: nil,
+		},
+		"ShouldIncludeTrackNameWhenConfigured": {
+			includesName:  true,
+			whitelist:     []string{"#project#default#sample"},
+			expectedSteps: []string{"#project#default#sample"},
+		},
+		"ShouldNotMatchWithoutTrackNameWhenConfigured": {
+			includesName:  true,
+			whitelist:     []string{"#project#sample"},
+			expectedSteps: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(stubFs, "step1_sample/main.tf", []byte(""), 0644)
+
+			tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+			// act
+			mockTracks, err := tracker.GatherTracks(config.Config{
+				Project:                    "project",
+				StepWhitelist:              test.whitelist,
+				DefaultTrackIDIncludesName: test.includesName,
+			})
+			require.NoError(t, err)
+
+			// assert
+			var stepIDs []string
+			for _, track := range mockTracks {
+				require.True(t, track.IsDefaultTrack)
+				for _, progression := range track.OrderedSteps {
+					for _, step := range progression {
+						stepIDs = append(stepIDs, step.ID)
+					}
+				}
+			}
+			require.Equal(t, test.expectedSteps, stepIDs)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldFailOnDefaultTrackCreation(t *testing.T) {
+	tests := map[string]struct {
+		topLevelTerraform bool
+		expectedErr       bool
+	}{
+		"ShouldErrorWhenTopLevelTerraformWouldCreateDefaultTrack": {
+			topLevelTerraform: true,
+			expectedErr:       true,
+		},
+		"ShouldGatherExplicitTracksWithoutTopLevelTerraform": {
+			topLevelTerraform: false,
+			expectedErr:       false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(stubFs, "tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+			if test.topLevelTerraform {
+				_ = afero.WriteFile(stubFs, "main.tf", []byte(""), 0644)
+			}
+
+			tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+			// act
+			mockTracks, err := tracker.GatherTracks(config.Config{
+				TargetAll:                  true,
+				FailOnDefaultTrackCreation: true,
+			})
+
+			// assert
+			if test.expectedErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "define explicit tracks")
+				require.Empty(t, mockTracks)
+
+				exists, _ := afero.DirExists(stubFs, "tracks/default")
+				require.False(t, exists, "The default track should not be created")
+			} else {
+				require.NoError(t, err)
+				require.Len(t, mockTracks, 1)
+				require.Equal(t, "network", mockTracks[0].Name)
+			}
+		})
+	}
+}
+
+func TestGatherTracks_ShouldOnlyCopyDefaultTrackWhenEnabled(t *testing.T) {
+	tests := map[string]struct {
+		copyDefaultTrack bool
+	}{
+		"ShouldNotModifyWorkingDirectoryByDefault": {},
+		"ShouldCopyDefaultTrackIdempotently":       {copyDefaultTrack: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(stubFs, "main.tf", []byte(""), 0644)
+			_ = afero.WriteFile(stubFs, "step1_vpc/main.tf", []byte("# vpc"), 0644)
+			_ = afero.WriteFile(stubFs, "docs/step1_notes/README.md", []byte(""), 0644)
+
+			tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+			cfg := config.Config{TargetAll: true, CopyDefaultTrack: test.copyDefaultTrack}
+
+			// act
+			_, err := tracker.GatherTracks(cfg)
+
+			// assert
+			require.NoError(t, err)
+
+			exists, _ := afero.DirExists(stubFs, "tracks/default")
+			require.Equal(t, test.copyDefaultTrack, exists)
+
+			if test.copyDefaultTrack {
+				b, err := afero.ReadFile(stubFs, "tracks/default/step1_vpc/main.tf")
+				require.NoError(t, err)
+				require.Equal(t, "# vpc", string(b))
+
+				copied, _ := stubFs.Stat("tracks/default/step1_vpc/main.tf")
+
+				_, err = tracker.GatherTracks(cfg)
+				require.NoError(t, err)
+
+				recopied, _ := stubFs.Stat("tracks/default/step1_vpc/main.tf")
+				require.Equal(t, copied.ModTime(), recopied.ModTime(), "Files matching their copy should not be rewritten")
+
+				exists, _ = afero.DirExists(stubFs, "tracks/default/tracks")
+				require.False(t, exists, "The copy should not be copied again on re-run")
+
+				exists, _ = afero.DirExists(stubFs, "tracks/default/docs")
+				require.False(t, exists, "Only step directories should be copied")
+			}
+		})
+	}
+}
+
+func TestGatherTracks_ShouldReadTracksFromConfiguredTracksDir(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "infra/tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "infra/step1_bootstrap/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/ignored/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "infra/main.tf", []byte(""), 0644)
+
+	tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+	// act
+	mockTracks, err := tracker.GatherTracks(config.Config{
+		TargetAll:                  true,
+		TracksDir:                  "infra/tracks",
+		FailOnDefaultTrackCreation: true,
+	})
+
+	// assert
+	require.Error(t, err, "Terraform files in the tracks directory's parent should create a default track")
+	require.Contains(t, err.Error(), "infra/tracks/{track}")
+
+	_ = stubFs.Remove("infra/main.tf")
+	mockTracks, err = tracker.GatherTracks(config.Config{
+		TargetAll: true,
+		TracksDir: "infra/tracks",
+	})
+
+	require.NoError(t, err)
+	dirs := map[string]string{}
+	for _, track := range mockTracks {
+		dirs[track.Name] = track.Dir
+	}
+	require.Equal(t, map[string]string{
+		tracks.DEFAULT_TRACK_NAME: "infra",
+		"network":                 "infra/tracks/network",
+	}, dirs, "Tracks should only be read from the configured tracks directory and its parent")
+}
+
+func TestGatherTracks_ShouldGatherAlwaysRunTrackDespiteNonMatchingWhitelist(t *testing.T) {
+	tests := map[string]struct {
+		whitelist     []string
+		expectedSteps []string
+	}{
+		"ShouldGatherAlongsideTargetedSteps": {
+			whitelist:     []string{"#project#apps#deploy"},
+			expectedSteps: []string{"#project#apps#deploy", "#project#baseline#guardrails", "#project#baseline#logging"},
+		},
+		"ShouldGatherWithoutAWhitelist": {
+			whitelist:     nil,
+			expectedSteps: []string{"#project#baseline#guardrails", "#project#baseline#logging"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			for _, step := range []string{"baseline/step1_guardrails", "baseline/step2_logging", "apps/step1_deploy", "apps/step1_other"} {
+				_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/main.tf", step), []byte(""), 0644)
+			}
+			_ = afero.WriteFile(stubFs, "tracks/baseline/runiac.yaml", []byte("always_run: true\n"), 0644)
+
+			tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+			// act
+			mockTracks, err := tracker.GatherTracks(config.Config{
+				Project:       "project",
+				StepWhitelist: test.whitelist,
+			})
+			require.NoError(t, err)
+
+			// assert
+			var stepIDs []string
+			for _, track := range mockTracks {
+				for _, progression := range track.OrderedSteps {
+					for _, step := range progression {
+						stepIDs = append(stepIDs, step.ID)
+					}
+				}
+			}
+			require.ElementsMatch(t, test.expectedSteps, stepIDs)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldMatchStepWhitelistPatterns(t *testing.T) {
+	tests := map[string]struct {
+		whitelist     []string
+		expectedSteps []string
+	}{
+		"ShouldSelectEveryStepInTrackWithWildcard": {
+			whitelist:     []string{"#project#apps#*"},
+			expectedSteps: []string{"#project#apps#deploy", "#project#apps#other"},
+		},
+		"ShouldSelectStepsAcrossTracksWithWildcard": {
+			whitelist:     []string{"#project#*#deploy"},
+			expectedSteps: []string{"#project#apps#deploy", "#project#unrelated#deploy"},
+		},
+		"ShouldSelectStepsWithRegex": {
+			whitelist:     []string{"regex:^#project#(networking|data)#"},
+			expectedSteps: []string{"#project#networking#vpc", "#project#data#database"},
+		},
+		"ShouldMatchExactStepIDs": {
+			whitelist:     []string{"#PROJECT#apps#deploy"},
+			expectedSteps: []string{"#project#apps#deploy"},
+		},
+		"ShouldSkipStepsNotMatchingPatterns": {
+			whitelist:     []string{"#project#missing#*", "regex:^#other#"},
+			expectedSteps: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			for _, step := range []string{"networking/step1_vpc", "data/step1_database", "apps/step1_deploy", "apps/step1_other", "unrelated/step1_deploy"} {
+				_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/main.tf", step), []byte(""), 0644)
+			}
+
+			tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+			// act
+			mockTracks, err := tracker.GatherTracks(config.Config{
+				Project:       "project",
+				StepWhitelist: test.whitelist,
+			})
+			require.NoError(t, err)
+
+			// assert
+			var stepIDs []string
+			for _, track := range mockTracks {
+				for _, progression := range track.OrderedSteps {
+					for _, step := range progression {
+						stepIDs = append(stepIDs, step.ID)
+					}
+				}
+			}
+			require.ElementsMatch(t, test.expectedSteps, stepIDs)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldIncludeTransitiveDependenciesOfTargetedTrack(t *testing.T) {
+	tests := map[string]struct {
+		includeDependencies bool
+		expectedSteps       []string
+	}{
+		"ShouldTargetFullDependencyChain": {
+			includeDependencies: true,
+			expectedSteps:       []string{"#project#apps#deploy", "#project#data#database", "#project#data#cache", "#project#networking#vpc"},
+		},
+		"ShouldOnlyTargetWhitelistedStepsByDefault": {
+			includeDependencies: false,
+			expectedSteps:       []string{"#project#apps#deploy"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			for _, step := range []string{"networking/step1_vpc", "data/step1_database", "data/step2_cache", "apps/step1_deploy", "apps/step1_other", "unrelated/step1_deploy"} {
+				_ = afero.WriteFile(stubFs, fmt.Sprintf("tracks/%s/main.tf", step), []byte(""), 0644)
+			}
+			_ = afero.WriteFile(stubFs, "tracks/apps/runiac.yaml", []byte("depends_on:\n  - data\n"), 0644)
+			_ = afero.WriteFile(stubFs, "tracks/data/runiac.yaml", []byte("depends_on:\n  - networking\n"), 0644)
+
+			tracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+			// act
+			mockTracks, err := tracker.GatherTracks(config.Config{
+				Project:             "project",
+				StepWhitelist:       []string{"#project#apps#deploy"},
+				IncludeDependencies: test.includeDependencies,
+			})
+			require.NoError(t, err)
+
+			// assert
+			var stepIDs []string
+			for _, track := range mockTracks {
+				for _, progression := range track.OrderedSteps {
+					for _, step := range progression {
+						stepIDs = append(stepIDs, step.ID)
+					}
+				}
+			}
+			require.ElementsMatch(t, test.expectedSteps, stepIDs)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldFailOnTrackWithUnknownStage(t *testing.T) {
+	// act
+	mockTracks, err := stubStagedTracker().GatherTracks(config.Config{
+		TargetAll: true,
+		Stages:    []string{"bootstrap"},
+	})
+
+	// assert
+	require.Error(t, err, "Tracks with a stage that is not configured should fail gathering")
+	require.Contains(t, err.Error(), "which is not one of the configured stages")
+	require.Empty(t, mockTracks)
+}
+
+func TestExecuteTracks_ShouldRunBeforeAndAfterAllCommandsOnceAroundDeployment(t *testing.T) {
+	// arrange
+	var calls []string
+	var mutex sync.Mutex
+
+	tracks.RunDeploymentCommand = func(ctx context.Context, logger *logrus.Entry, cfg config.Config, command string) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls = append(calls, command)
+		return "", nil
+	}
+	defer func() { tracks.RunDeploymentCommand = tracks.RunDeploymentCommandImpl }()
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		mutex.Lock()
+		calls = append(calls, t.Name)
+		mutex.Unlock()
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := sut.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:        true,
+		BeforeAllCommand: "acquire-lease",
+		AfterAllCommand:  "release-lease",
+	})
+
+	// assert
+	require.NoError(t, mockExecution.Err)
+	require.Len(t, calls, stubTrackCount+2, "Each track should deploy with before and after commands run once")
+	require.Equal(t, "acquire-lease", calls[0], "Before all command should run first")
+	require.Equal(t, "release-lease", calls[len(calls)-1], "After all command should run last")
+	require.ElementsMatch(t, []string{stubPreTrackName, stubTrackNameA, stubTrackNameB}, calls[1:len(calls)-1])
+}
+
+func TestExecuteTracks_ShouldAbortWhenBeforeAllCommandFails(t *testing.T) {
+	// arrange
+	var commands []string
+
+	tracks.RunDeploymentCommand = func(ctx context.Context, logger *logrus.Entry, cfg config.Config, command string) (string, error) {
+		commands = append(commands, command)
+		if command == "acquire-lease" {
+			return "lease held", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+	defer func() { tracks.RunDeploymentCommand = tracks.RunDeploymentCommandImpl }()
+
+	deployCount := 0
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		deployCount++
+		out <- tracks.Output{Name: t.Name}
+	}
+	defer func() { tracks.DeployTrack = tracks.ExecuteDeployTrack }()
+
+	// act
+	mockExecution := sut.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:        true,
+		BeforeAllCommand: "acquire-lease",
+		AfterAllCommand:  "release-lease",
+	})
+
+	// assert
+	require.Error(t, mockExecution.Err, "Stage should record the aborted deployment")
+	require.Equal(t, 0, deployCount, "No tracks should be deployed")
+	require.Equal(t, []string{"acquire-lease", "release-lease"}, commands, "After all command should still run once")
+
+	for _, tr := range mockExecution.Tracks {
+		require.True(t, tr.Skipped, "All tracks should be skipped")
+		require.Equal(t, tracks.SkipReasonBeforeAllCommandFailed, tr.SkipReason)
+	}
+}
+
+func TestExecuteTracks_ShouldFailOnRegionsNotAllowedBeforeExecutingSteps(t *testing.T) {
+	// arrange
+	stepCount := 0
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		stepCount++
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	// act
+	mockExecution := sut.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:       true,
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-1", "us-esat-2", "eu-west-1", "us-esat-2"},
+		AllowedRegions:  []string{"us-east-1", "us-east-2", "eu-west-1"},
+	})
+
+	// assert
+	require.EqualError(t, mockExecution.Err, "regions [us-esat-2] are not allowed regions [us-east-1 us-east-2 eu-west-1]")
+	require.Equal(t, 0, stepCount, "No steps should be executed")
+	require.Empty(t, mockExecution.Tracks)
+}
+
+func TestExecuteTracks_ShouldFailOnDestroyRegionsNotDeployedToBeforeExecutingSteps(t *testing.T) {
+	// arrange
+	stepCount := 0
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		stepCount++
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	// act
+	mockExecution := sut.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:       true,
+		SelfDestroy:     true,
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2"},
+		DestroyRegions:  []string{"us-east-2", "eu-west-1"},
+	})
+
+	// assert
+	require.EqualError(t, mockExecution.Err, "destroy regions [eu-west-1] are not regions the tracks are deployed to")
+	require.Equal(t, 0, stepCount, "No steps should be executed")
+	require.Empty(t, mockExecution.Tracks)
+}
+
+func TestRunDeploymentCommandImpl_ShouldSubstituteRunID(t *testing.T) {
+	// act
+	resp, err := tracks.RunDeploymentCommandImpl(context.Background(), logger, config.Config{UniqueExternalExecutionID: "run-123"}, "echo {run_id} $RUNIAC_RUN_ID")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "run-123 run-123\n", resp)
+}
+
+func TestRunDeploymentCommandImpl_ShouldInterruptCommandWhenCancelled(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// act
+	start := time.Now()
+	_, err := tracks.RunDeploymentCommandImpl(ctx, logger, config.Config{ShutdownGracePeriod: time.Minute}, "sleep 30")
+
+	// assert
+	require.Error(t, err, "The command should exit due to the interrupt")
+	require.True(t, time.Since(start) < 10*time.Second, "The command should be interrupted once the deployment is cancelled")
+}
+
+func TestExecuteTracks_ShouldHandleRegionalAutoDestroyWithRegionalOutputVariables(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubPrimaryStepOutputVars := map[string]map[string]string{
+		"step1": {
+			"primary": "primary",
+		},
+	}
+
+	stubRegionalStepOutputVars := map[string]map[string]string{
+		"step1": {
+			"regional": "regional",
+		},
+	}
+
+	stubRegionalRegion := "regionalregion"
+	stubPrimaryRegion := "primaryregion"
+
+	deployTrackStub := map[string]tracks.Output{
+		"_pretrack": {
+			Name:                       "_pretrack",
+			PrimaryStepOutputVariables: stubPrimaryStepOutputVars,
+			Executions: []tracks.RegionExecution{
+				{
+					Output: tracks.ExecutionOutput{
+						StepOutputVariables: stubPrimaryStepOutputVars,
+					},
+					Region:           stubPrimaryRegion,
+					RegionDeployType: config.PrimaryRegionDeployType,
+				},
+			},
+		},
+		"track-a": {
+			Name:                       "track-a",
+			PrimaryStepOutputVariables: stubPrimaryStepOutputVars,
+			Executions: []tracks.RegionExecution{
+				{
+					Output: tracks.ExecutionOutput{
+						StepOutputVariables: stubPrimaryStepOutputVars,
+					},
+					Region:           stubPrimaryRegion,
+					RegionDeployType: config.PrimaryRegionDeployType,
+				},
+				{
+					Output: tracks.ExecutionOutput{
+						StepOutputVariables: stubRegionalStepOutputVars,
+					},
+					Region:           stubRegionalRegion,
+					RegionDeployType: config.RegionalRegionDeployType,
+				},
+			},
+		},
+		"track-b": {
+			Name:                       "track-b",
+			PrimaryStepOutputVariables: nil,
+			Executions:                 nil,
+		},
+	}
+	var destroyTrackASpy tracks.Execution
+	deployTrackExecutionSpy := []tracks.Execution{}
+
+	tracks.DeployTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		execution.Output.Name = t.Name
+		deployTrackExecutionSpy = append(deployTrackExecutionSpy, execution)
+		out <- deployTrackStub[t.Name]
+		return
+	}
+
+	tracks.DestroyTrack = func(ctx context.Context, execution tracks.Execution, cfg config.Config, t tracks.Track, out chan<- tracks.Output) {
+		if t.Name == "track-a" {
+			destroyTrackASpy = execution
+		}
+
+		out <- tracks.Output{
+			Name:                       "",
+			PrimaryStepOutputVariables: nil,
+			Executions:                 nil,
+		}
+
+		return
+	}
+
+	// act
+	mockExecution := sut.ExecuteTracks(context.Background(), config.Config{
+		TargetAll:   true,
+		SelfDestroy: true,
+	})
+
+	require.NotNil(t, mockExecution)
+
+	for _, executionSpy := range deployTrackExecutionSpy {
+		require.Contains(t, []string{"_pretrack", "track-a", "track-b"}, executionSpy.Output.Name, "Should execute correct tracks")
+	}
+	require.Equal(t, stubPrimaryStepOutputVars, destroyTrackASpy.DefaultExecutionStepOutputVariables[config.PrimaryRegionDeployType.String()+"-"+stubPrimaryRegion], "Should pass primary step output vars to destroy")
+	require.Equal(t, stubRegionalStepOutputVars, destroyTrackASpy.DefaultExecutionStepOutputVariables[config.RegionalRegionDeployType.String()+"-"+stubRegionalRegion], "Should pass regional region specific step output vars to destroy")
+}
+
+func TestExecuteDeployTrack_ShouldExecuteCorrectStepsAndRegions(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var test = map[string]struct {
+		stubExecutedFailCount     int
+		stubExecutedTestFailCount int
+		stubRegionalDeployment    bool
+		regionGroup               string
+		stubTargetRegions         []string
+		expectedCallCount         int
+	}{
+		"ShouldExecutePrimaryRegionOnceWithNoRegionalResources": {
+			stubExecutedFailCount:     0,
+			stubExecutedTestFailCount: 0,
+			stubRegionalDeployment:    false,
+			regionGroup:               "us",
+			stubTargetRegions:         []string{"us-east-1"},
+			expectedCallCount:         1,
+		},
+		"ShouldExecutePrimaryRegionTwiceWithRegionalResources": {
+			stubExecutedFailCount:     0,
+			stubExecutedTestFailCount: 0,
+			stubRegionalDeployment:    true,
+			regionGroup:               "us",
+			stubTargetRegions:         []string{"us-east-1"},
+			expectedCallCount:         2,
+		},
+		"ShouldExecuteN+PrimaryTimesWithRegionalResourcesTargetingNRegions": {
+			stubExecutedFailCount:     0,
+			stubExecutedTestFailCount: 0,
+			stubRegionalDeployment:    true,
+			regionGroup:               "us",
+			stubTargetRegions:         []string{"us-east-1", "us-east-2"},
+			expectedCallCount:         3,
+		},
+	}
+
+	executionParams := []tracks.RegionExecution{}
+
+	for name, test := range test {
+		t.Run(name, func(t *testing.T) {
+
+			var callCount int
+			tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+				regionExecution := <-in
+				callCount++
+
+				executionParams = append(executionParams, regionExecution)
+
+				regionExecution.Output = tracks.ExecutionOutput{
+					FailureCount:        test.stubExecutedFailCount,
+					FailedTestCount:     test.stubExecutedTestFailCount,
+					StepOutputVariables: regionExecution.DefaultStepOutputVariables,
+				}
+
+				regionExecution.Output.StepOutputVariables["test-regional"] = map[string]string{
+					"region": regionExecution.Region,
+				}
+
+				out <- regionExecution
+				return
+			}
+
+			trackChan := make(chan tracks.Output, 1)
+
+			// act
+			tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+				Logger: logger,
+				Fs:     fs,
+				Output: tracks.ExecutionOutput{},
+			}, config.Config{
+				RegionalRegions: test.stubTargetRegions,
+				RegionGroup:     test.regionGroup,
+			}, tracks.Track{
+				RegionalDeployment: test.stubRegionalDeployment,
+			}, trackChan)
+
+			mockOutput := <-trackChan
+
+			require.Len(t, mockOutput.Executions, callCount)
+
+			for _, exec := range mockOutput.Executions {
+				require.Equal(t, exec.Region, exec.Output.StepOutputVariables["test-regional"]["region"], "Region variables should stay scoped to executing region function")
+			}
+
+			require.Equal(t, map[string]map[string]string(nil), executionParams[0].Output.StepOutputVariables, "Primary execution should start with no incoming previous step variables")
+			require.Equal(t, test.stubExecutedFailCount*callCount, mockOutput.Executions[0].Output.FailureCount, "Should correctly set failed step count")
+			require.Equal(t, config.PrimaryRegionDeployType, executionParams[0].RegionDeployType, "First execution should be primary region")
+			require.Equal(t, test.expectedCallCount, callCount, "Should call DeployTrackRegion() the expected amount of times")
+		})
+	}
+}
+
+func TestExecuteDeployTrack_ShouldWriteOutputVariableFilesForEachRegion(t *testing.T) {
+	// arrange
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+
+		regionExecution.Output = tracks.ExecutionOutput{
+			StepOutputVariables: regionExecution.DefaultStepOutputVariables,
+		}
+
+		if regionExecution.RegionDeployType == config.PrimaryRegionDeployType {
+			regionExecution.Output.StepOutputVariables["step1"] = map[string]string{
+				"primary_var": regionExecution.Region,
+			}
+		} else {
+			regionExecution.Output.StepOutputVariables["step1-regional"] = map[string]string{
+				"regional_var": regionExecution.Region,
+			}
+		}
+
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	stubFs := afero.NewMemMapFs()
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     stubFs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:      "us-east-1",
+		RegionalRegions:    []string{"us-east-1", "us-east-2"},
+		OutputVariablesDir: "/outputs",
+	}, tracks.Track{
+		Name:               "track-outputs",
+		RegionalDeployment: true,
+	}, trackChan)
+
+	<-trackChan
+
+	// assert
+	readOutputVariableFile := func(file string) map[string]map[string]string {
+		bytes, err := afero.ReadFile(stubFs, filepath.Join("/outputs", "track-outputs", file))
+		require.NoError(t, err, "%s should be written", file)
+
+		vars := map[string]map[string]string{}
+		require.NoError(t, json.Unmarshal(bytes, &vars))
+
+		return vars
+	}
+
+	primary := readOutputVariableFile("primary-us-east-1.json")
+	require.Equal(t, map[string]map[string]string{"step1": {"primary_var": "us-east-1"}}, primary)
+
+	for _, region := range []string{"us-east-1", "us-east-2"} {
+		regional := readOutputVariableFile(fmt.Sprintf("regional-%s.json", region))
+
+		require.Equal(t, "us-east-1", regional["step1"]["primary_var"], "Regional file should include primary outputs available to the region")
+		require.Equal(t, region, regional["step1-regional"]["regional_var"], "Regional file should include the region's own outputs")
+	}
+}
+
+func TestExecuteDeployTrack_ShouldNotExecuteRegionallyForPrimaryOnlyTrack(t *testing.T) {
+	// arrange
+	var regionDeployTypes []config.RegionDeployType
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+		regionDeployTypes = append(regionDeployTypes, regionExecution.RegionDeployType)
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-1", "us-east-2"},
+	}, tracks.Track{
+		RegionalDeployment: true,
+		Config: config.TrackConfig{
+			RegionDeployTypes: []string{"primary"},
+		},
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.Len(t, mockOutput.Executions, 1)
+	require.Equal(t, []config.RegionDeployType{config.PrimaryRegionDeployType}, regionDeployTypes, "Only the primary region should execute")
+}
+
+func TestExecuteDeployTrack_ShouldOnlyDeployFirstPrimaryProgressionForSmokeDeploy(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var executed []string
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		executed = append(executed, fmt.Sprintf("%s/%s/%s", s.Name, regionDeployType, region))
+		mutex.Unlock()
+
+		s.Output = config.StepOutput{Status: config.Success}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2"},
+		SmokeDeploy:     true,
+	}, tracks.Track{
+		Name:                  "network",
+		RegionalDeployment:    true,
+		StepProgressionsCount: 3,
+		OrderedSteps: map[int][]config.Step{
+			1: {{Name: "vpc", RegionalResourcesExist: true}, {Name: "dns"}},
+			2: {{Name: "subnets", RegionalResourcesExist: true}},
+			3: {{Name: "peering"}},
+		},
+	}, trackChan)
+
+	output := <-trackChan
+
+	// assert
+	require.ElementsMatch(t, []string{"vpc/primary/us-east-1", "dns/primary/us-east-1"}, executed, "Only first progression primary steps should be deployed")
+	require.Len(t, output.Executions, 1, "Regional regions should not be deployed")
+
+	primary := output.Executions[0].Output
+	require.Equal(t, 2, primary.ExecutedCount)
+	require.Equal(t, 2, primary.SkippedCount)
+	require.Equal(t, config.Skipped, primary.Steps["subnets"].Output.Status)
+	require.Equal(t, config.Skipped, primary.Steps["peering"].Output.Status)
+}
+
+func TestExecuteDeployTrack_ShouldOnlyDeployRegionalRegionsWithPersistedPrimaryOutputs(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "outputs/network/primary-us-east-1.json", []byte(`{"vpc":{"vpc_id":"vpc-123"}}`), 0644)
+
+	var mutex sync.Mutex
+	var executions []tracks.RegionExecution
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+
+		mutex.Lock()
+		executions = append(executions, regionExecution)
+		mutex.Unlock()
+
+		regionExecution.Output = tracks.ExecutionOutput{StepOutputVariables: regionExecution.DefaultStepOutputVariables}
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     stubFs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:      "us-east-1",
+		RegionalRegions:    []string{"us-east-2", "us-west-2"},
+		RegionalOnly:       true,
+		OutputVariablesDir: "outputs",
+	}, tracks.Track{
+		Name:               "network",
+		RegionalDeployment: true,
+	}, trackChan)
+
+	output := <-trackChan
+
+	// assert
+	require.NoError(t, output.Err)
+	require.Len(t, executions, 2, "Only the regional regions should be deployed")
+	for _, exec := range executions {
+		require.Equal(t, config.RegionalRegionDeployType, exec.RegionDeployType)
+		require.Equal(t, "vpc-123", exec.DefaultStepOutputVariables["vpc"]["vpc_id"], "Regional regions should receive the persisted primary outputs")
+	}
+
+	require.Len(t, output.Executions, 2)
+	require.Equal(t, map[string]map[string]string{"vpc": {"vpc_id": "vpc-123"}}, output.PrimaryStepOutputVariables)
+
+	// the persisted primary outputs are left as is
+	primaryOutputs, _ := afero.ReadFile(stubFs, "outputs/network/primary-us-east-1.json")
+	require.JSONEq(t, `{"vpc":{"vpc_id":"vpc-123"}}`, string(primaryOutputs))
+}
+
+func TestExecuteDeployTrack_ShouldFailRegionalOnlyDeployWithoutPersistedPrimaryOutputs(t *testing.T) {
+	// arrange
+	var callCount int
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+		callCount++
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     afero.NewMemMapFs(),
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:      "us-east-1",
+		RegionalRegions:    []string{"us-east-2"},
+		RegionalOnly:       true,
+		OutputVariablesDir: "outputs",
+	}, tracks.Track{
+		Name:               "network",
+		RegionalDeployment: true,
+	}, trackChan)
+
+	output := <-trackChan
+
+	// assert
+	require.Error(t, output.Err)
+	require.Equal(t, 0, callCount, "No region should be deployed without the primary outputs")
+}
+
+func TestExecuteDeployTrack_ShouldOrderResultsIndependentOfCompletionOrder(t *testing.T) {
+	// arrange
+	regions := []string{"us-west-2", "us-east-2", "eu-west-1", "ap-south-1"}
+	stepNames := []string{"alpha", "bravo", "charlie", "delta"}
+
+	deploy := func(reverse bool) []byte {
+		tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+			s config.Step, out chan<- config.Step, destroy bool) {
+			// complete steps and regions in a different order each deployment
+			delay := len(s.Name) + len(region)
+			if reverse {
+				delay = 20 - delay
+			}
+			time.Sleep(time.Duration(delay) * time.Millisecond)
+
+			s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, Region: region, RegionDeployType: regionDeployType}
+			if s.Name != "alpha" {
+				s.Output.Status = config.Fail
+				s.Output.Err = errors.New("failed")
+			}
+			out <- s
+		}
+
+		orderedSteps := map[int][]config.Step{}
+		for _, name := range stepNames {
+			orderedSteps[1] = append(orderedSteps[1], config.Step{Name: name, RegionalResourcesExist: true})
+		}
+		if reverse {
+			for i, j := 0, len(orderedSteps[1])-1; i < j; i, j = i+1, j-1 {
+				orderedSteps[1][i], orderedSteps[1][j] = orderedSteps[1][j], orderedSteps[1][i]
+			}
+		}
+
+		trackChan := make(chan tracks.Output, 1)
+		tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+			Logger: logger,
+			Fs:     afero.NewMemMapFs(),
+			Output: tracks.ExecutionOutput{},
+		}, config.Config{
+			PrimaryRegion:   "us-east-1",
+			RegionalRegions: regions,
+		}, tracks.Track{
+			Name:                  "network",
+			RegionalDeployment:    true,
+			StepProgressionsCount: 1,
+			OrderedSteps:          orderedSteps,
+		}, trackChan)
+		output := <-trackChan
+
+		type summary struct {
+			RegionDeployType string
+			Region           string
+			FailedSteps      []string
+			Output           tracks.ExecutionOutput
+		}
+
+		var summaries []summary
+		for _, exec := range output.Executions {
+			var failedSteps []string
+			for _, s := range exec.Output.FailedSteps {
+				failedSteps = append(failedSteps, s.Name)
+			}
+			exec.Output.FailedSteps = nil
+			exec.Output.Duration = 0 // timings differ between deployments
+			summaries = append(summaries, summary{exec.RegionDeployType.String(), exec.Region, failedSteps, exec.Output})
+		}
+
+		b, err := json.Marshal(summaries)
+		require.NoError(t, err)
+		return b
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	// act
+	first := deploy(false)
+	second := deploy(true)
+
+	// assert
+	require.Equal(t, string(first), string(second), "Summaries of the same result should be identical")
+	require.Regexp(t, `^\[{"RegionDeployType":"primary","Region":"us-east-1","FailedSteps":\["bravo","charlie","delta"\]`, string(first))
+}
+
+func TestExecuteDeployTrack_ShouldPauseBeforeRegional(t *testing.T) {
+	tests := map[string]struct {
+		approved                bool
+		expectedRegionDeploys   int
+		expectedPartial         bool
+		expectedApprovalRegions []string
+	}{
+		"ShouldExecuteRegionallyWhenApproved": {
+			approved:                true,
+			expectedRegionDeploys:   3,
+			expectedPartial:         false,
+			expectedApprovalRegions: []string{"us-east-1", "us-east-2"},
+		},
+		"ShouldSkipRegionalAndMarkPartialWhenDenied": {
+			approved:                false,
+			expectedRegionDeploys:   1,
+			expectedPartial:         true,
+			expectedApprovalRegions: []string{"us-east-1", "us-east-2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			var mu sync.Mutex
+			var regionDeployTypes []config.RegionDeployType
+			tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+				regionExecution := <-in
+				mu.Lock()
+				regionDeployTypes = append(regionDeployTypes, regionExecution.RegionDeployType)
+				mu.Unlock()
+				out <- regionExecution
+			}
+			defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+			var requests []tracks.ApprovalRequest
+			tracks.RequestApproval = func(ctx context.Context, logger *logrus.Entry, cfg config.Config, request tracks.ApprovalRequest) (bool, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				// the primary region must complete before approval is requested
+				require.Equal(t, []config.RegionDeployType{config.PrimaryRegionDeployType}, regionDeployTypes)
+				requests = append(requests, request)
+				return test.approved, nil
+			}
+			defer func() { tracks.RequestApproval = tracks.RequestApprovalImpl }()
+
+			trackChan := make(chan tracks.Output, 1)
+
+			// act
+			tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+				Logger: logger,
+				Fs:     fs,
+				Output: tracks.ExecutionOutput{},
+			}, config.Config{
+				PrimaryRegion:   "us-east-1",
+				RegionalRegions: []string{"us-east-1", "us-east-2"},
+			}, tracks.Track{
+				Name:               "track",
+				RegionalDeployment: true,
+				Config: config.TrackConfig{
+					PauseBeforeRegional: true,
+				},
+			}, trackChan)
+
+			mockOutput := <-trackChan
+
+			// assert
+			require.Len(t, requests, 1, "Approval should be requested once")
+			require.Equal(t, "track", requests[0].TrackName)
+			require.Equal(t, "regional", requests[0].Phase)
+			require.Equal(t, test.expectedApprovalRegions, requests[0].Regions)
+			require.Len(t, regionDeployTypes, test.expectedRegionDeploys)
+			require.Len(t, mockOutput.Executions, test.expectedRegionDeploys)
+			require.Equal(t, test.expectedPartial, mockOutput.Partial)
+		})
+	}
+}
+
+func TestExecuteDeployTrack_ShouldUseRegionalRegionsFromPrimaryStepOutput(t *testing.T) {
+	tests := map[string]struct {
+		outputValue     string
+		expectedRegions []string
+		expectedErr     bool
+	}{
+		"ShouldFanOutToJSONListOfRegions": {
+			outputValue:     `["us-west-1","us-west-2"]`,
+			expectedRegions: []string{"us-west-1", "us-west-2"},
+		},
+		"ShouldFanOutToCommaSeparatedRegions": {
+			outputValue:     "eu-west-1, eu-central-1",
+			expectedRegions: []string{"eu-central-1", "eu-west-1"},
+		},
+		"ShouldSkipRegionalWhenListIsEmpty": {
+			outputValue:     "[]",
+			expectedRegions: nil,
+		},
+		"ShouldSkipRegionalAndFailTrackWhenOutputIsMissing": {
+			expectedRegions: nil,
+			expectedErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			var mu sync.Mutex
+			var regionalRegions []string
+			tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+				regionExecution := <-in
+
+				if regionExecution.RegionDeployType == config.PrimaryRegionDeployType {
+					regionExecution.Output.StepOutputVariables = map[string]map[string]string{
+						"regions": {},
+					}
+					if test.outputValue != "" {
+						regionExecution.Output.StepOutputVariables["regions"]["enabled_regions"] = test.outputValue
+					}
+				} else {
+					mu.Lock()
+					regionalRegions = append(regionalRegions, regionExecution.Region)
+					mu.Unlock()
+				}
+
+				out <- regionExecution
+			}
+			defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+			trackChan := make(chan tracks.Output, 1)
+
+			// act
+			tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+				Logger: logger,
+				Fs:     fs,
+				Output: tracks.ExecutionOutput{},
+			}, config.Config{
+				PrimaryRegion:   "us-east-1",
+				RegionalRegions: []string{"us-east-1", "us-east-2"},
+			}, tracks.Track{
+				Name:               "track",
+				RegionalDeployment: true,
+				Config: config.TrackConfig{
+					RegionalRegionsOutput: "regions.enabled_regions",
+				},
+			}, trackChan)
+
+			mockOutput := <-trackChan
+
+			// assert
+			sort.Strings(regionalRegions)
+			require.Equal(t, test.expectedRegions, regionalRegions, "Regional deployments should use the regions produced by the primary step")
+			require.Len(t, mockOutput.Executions, len(test.expectedRegions)+1)
+			if test.expectedErr {
+				require.Error(t, mockOutput.Err, "Track should fail when its regional regions can not be determined")
+			} else {
+				require.NoError(t, mockOutput.Err)
+			}
+		})
+	}
+}
+
+func TestExecuteDeployTrack_ShouldEnforceMinSuccessfulRegions(t *testing.T) {
+	tests := map[string]struct {
+		minSuccessfulRegions int
+		expectedErr          bool
+	}{
+		"ShouldFailTrackWhenFewerRegionsSucceeded": {
+			minSuccessfulRegions: 3,
+			expectedErr:          true,
+		},
+		"ShouldSucceedWhenMinimumRegionsSucceeded": {
+			minSuccessfulRegions: 2,
+			expectedErr:          false,
+		},
+		"ShouldNotRequireRegionsByDefault": {
+			minSuccessfulRegions: 0,
+			expectedErr:          false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+				regionExecution := <-in
+
+				status := config.Success
+				if regionExecution.RegionDeployType == config.RegionalRegionDeployType && regionExecution.Region == "us-west-2" {
+					status = config.Fail
+				}
+
+				regionExecution.Output.Steps = map[string]config.Step{
+					"step": {Name: "step", Output: config.StepOutput{Status: status}},
+				}
+
+				out <- regionExecution
+			}
+			defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+			trackChan := make(chan tracks.Output, 1)
+
+			// act
+			tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+				Logger: logger,
+				Fs:     fs,
+				Output: tracks.ExecutionOutput{},
+			}, config.Config{
+				PrimaryRegion:   "us-east-1",
+				RegionalRegions: []string{"us-east-1", "us-east-2", "us-west-2"},
+			}, tracks.Track{
+				Name:               "track",
+				RegionalDeployment: true,
+				Config: config.TrackConfig{
+					MinSuccessfulRegions: test.minSuccessfulRegions,
+				},
+			}, trackChan)
+
+			mockOutput := <-trackChan
+
+			// assert
+			require.Len(t, mockOutput.Executions, 4)
+			if test.expectedErr {
+				require.Error(t, mockOutput.Err, "Track should fail when fewer than the minimum regions succeeded")
+				require.Contains(t, mockOutput.Err.Error(), "2 of 3 regional regions succeeded")
+			} else {
+				require.NoError(t, mockOutput.Err)
+			}
+		})
+	}
+}
+
+func TestExecuteDeployTrack_ShouldWriteRegionStatusFilesAsRegionsFinish(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+
+	readStatus := func(region string) tracks.RegionStatus {
+		var status tracks.RegionStatus
+		b, err := afero.ReadFile(stubFs, fmt.Sprintf("status/track/%s.status", region))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &status))
+		return status
+	}
+
+	var mu sync.Mutex
+	inFlightStatuses := map[string]tracks.RegionStatus{}
+
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+
+		mu.Lock()
+		inFlightStatuses[fmt.Sprintf("%s-%s", regionExecution.RegionDeployType, regionExecution.Region)] = readStatus(regionExecution.Region)
+		mu.Unlock()
+
+		status := config.Success
+		if regionExecution.RegionDeployType == config.RegionalRegionDeployType && regionExecution.Region == "us-west-2" {
+			status = config.Fail
+		}
+
+		regionExecution.Output.Steps = map[string]config.Step{
+			"step": {Name: "step", Output: config.StepOutput{Status: status}},
+		}
+
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     stubFs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-1", "us-west-2"},
+		RegionStatusDir: "status",
+		Environment:     "prod",
+	}, tracks.Track{
+		Name:               "track",
+		RegionalDeployment: true,
+	}, trackChan)
+
+	<-trackChan
+
+	// assert
+	require.Len(t, inFlightStatuses, 3)
+	for key, status := range inFlightStatuses {
+		require.Equal(t, tracks.RegionStatusInProgress, status.Status, "%s should be in progress while executing", key)
+		require.False(t, status.Timestamp.IsZero())
+		require.Equal(t, "prod", status.Environment, "%s should be tagged with the environment", key)
+	}
+	require.Equal(t, "primary", inFlightStatuses["primary-us-east-1"].RegionDeployType)
+	require.Equal(t, "regional", inFlightStatuses["regional-us-east-1"].RegionDeployType)
+
+	eastStatus := readStatus("us-east-1")
+	require.Equal(t, config.Success.String(), eastStatus.Status)
+	require.Equal(t, "regional", eastStatus.RegionDeployType, "The regional execution should supersede the primary execution's status")
+	require.False(t, eastStatus.Timestamp.Before(inFlightStatuses["regional-us-east-1"].Timestamp))
+
+	westStatus := readStatus("us-west-2")
+	require.Equal(t, config.Fail.String(), westStatus.Status)
+	require.Equal(t, "regional", westStatus.RegionDeployType)
+}
+
+func TestExecuteDeployTrack_ShouldCancelRemainingRegionsAfterMaxRegionalFailures(t *testing.T) {
+	// arrange
+	failingRegions := map[string]bool{"us-east-2": true, "us-west-1": true}
+
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+
+		status := config.Success
+		if regionExecution.RegionDeployType == config.RegionalRegionDeployType {
+			if failingRegions[regionExecution.Region] {
+				status = config.Fail
+			} else {
+				// the remaining regions are still in progress until they are cancelled
+				select {
+				case <-regionExecution.Cancelled:
+					status = config.Skipped
+				case <-time.After(5 * time.Second):
+				}
+			}
+		}
+
+		regionExecution.Output.Steps = map[string]config.Step{
+			"step": {Name: "step", Output: config.StepOutput{Status: status}},
+		}
+
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2", "us-west-1", "us-west-2", "eu-west-1"},
+	}, tracks.Track{
+		Name:               "track",
+		RegionalDeployment: true,
+		Config: config.TrackConfig{
+			MaxRegionalFailures: 1,
+		},
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.True(t, mockOutput.Partial, "Track should be partially deployed")
+	require.Len(t, mockOutput.Executions, 5)
+	for _, exec := range mockOutput.Executions {
+		expected := config.Success
+		if exec.RegionDeployType == config.RegionalRegionDeployType {
+			expected = config.Skipped
+			if failingRegions[exec.Region] {
+				expected = config.Fail
+			}
+		}
+		require.Equal(t, expected, exec.Output.Steps["step"].Output.Status, "%s %s", exec.RegionDeployType, exec.Region)
+	}
+}
+
+func TestExecuteDeployTrack_ShouldDeployOnlyAdHocRegionRegionally(t *testing.T) {
+	// arrange
+	tracks.DeployTrackRegion = func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+		regionExecution := <-in
+		regionExecution.Output.Steps = map[string]config.Step{
+			"step": {Name: "step", Output: config.StepOutput{Status: config.Success}},
+		}
+		regionExecution.Output.StepOutputVariables = map[string]map[string]string{
+			"step": {"region": regionExecution.Region},
+		}
+		out <- regionExecution
+	}
+	defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2", "us-west-2"},
+		AdHocRegion:     "ap-south-1",
+	}, tracks.Track{
+		Name:               "track",
+		RegionalDeployment: true,
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.Len(t, mockOutput.Executions, 2)
+	require.Equal(t, config.PrimaryRegionDeployType, mockOutput.Executions[0].RegionDeployType)
+	require.Equal(t, "us-east-1", mockOutput.Executions[0].Region)
+	require.Equal(t, config.RegionalRegionDeployType, mockOutput.Executions[1].RegionDeployType, "The ad-hoc region should be a regional execution")
+	require.Equal(t, "ap-south-1", mockOutput.Executions[1].Region)
+	require.Equal(t, "ap-south-1", mockOutput.Executions[1].Output.StepOutputVariables["step"]["region"], "The ad-hoc region's outputs should be collected")
+}
+
+func TestExecuteDeployTrack_ShouldDeployAndDestroyTrackRegionOverrides(t *testing.T) {
+	tests := map[string]struct {
+		track           tracks.Track
+		expectedRegions []string
+	}{
+		"ShouldUseTrackRegions": {
+			track:           tracks.Track{Name: "track", RegionalDeployment: true, PrimaryRegion: "eu-west-1", RegionalRegions: []string{"eu-central-1"}},
+			expectedRegions: []string{"primary-eu-west-1", "regional-eu-central-1"},
+		},
+		"ShouldFallBackToConfiguredRegions": {
+			track:           tracks.Track{Name: "track", RegionalDeployment: true},
+			expectedRegions: []string{"primary-us-east-1", "regional-us-east-2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			var mutex sync.Mutex
+			var deployed, destroyed []string
+
+			stubTrackRegion := func(regions *[]string) tracks.ExecuteTrackRegionFunc {
+				return func(ctx context.Context, in <-chan tracks.RegionExecution, out chan<- tracks.RegionExecution) {
+					regionExecution := <-in
+					mutex.Lock()
+					*regions = append(*regions, fmt.Sprintf("%s-%s", regionExecution.RegionDeployType, regionExecution.Region))
+					mutex.Unlock()
+					out <- regionExecution
+				}
+			}
+
+			tracks.DeployTrackRegion = stubTrackRegion(&deployed)
+			defer func() { tracks.DeployTrackRegion = tracks.ExecuteDeployTrackRegion }()
+
+			tracks.DestroyTrackRegion = stubTrackRegion(&destroyed)
+			defer func() { tracks.DestroyTrackRegion = tracks.ExecuteDestroyTrackRegion }()
+
+			cfg := config.Config{
+				PrimaryRegion:   "us-east-1",
+				RegionalRegions: []string{"us-east-2"},
+			}
+			execution := tracks.Execution{Logger: logger, Fs: fs, Output: tracks.ExecutionOutput{}}
+			trackChan := make(chan tracks.Output, 1)
+
+			// act
+			tracks.ExecuteDeployTrack(context.Background(), execution, cfg, test.track, trackChan)
+			<-trackChan
+			tracks.ExecuteDestroyTrack(context.Background(), execution, cfg, test.track, trackChan)
+			<-trackChan
+
+			// assert
+			sort.Strings(deployed)
+			sort.Strings(destroyed)
+			require.Equal(t, test.expectedRegions, deployed)
+			require.Equal(t, test.expectedRegions, destroyed)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldResolveTrackRegionOverrides(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/eu/step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/eu/runiac.yaml", []byte("primary_region: eu-west-1\nregional_regions:\n  - eu-*\n  - eu-west-2\n"), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/global/step1_deploy/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll:       true,
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2", "eu-central-1", "eu-north-1"},
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 2)
+	for _, track := range mockTracks {
+		step := track.OrderedSteps[1][0]
+
+		switch track.Name {
+		case "eu":
+			require.Equal(t, "eu-west-1", track.PrimaryRegion)
+			require.Equal(t, []string{"eu-central-1", "eu-north-1", "eu-west-2"}, track.RegionalRegions, "Patterns should select from the configured regional regions")
+			require.Equal(t, track.RegionalRegions, step.DeployConfig.RegionalRegions, "Steps should be deployed to the track's regions")
+		case "global":
+			require.Equal(t, "us-east-1", track.PrimaryRegion)
+			require.Equal(t, []string{"us-east-2", "eu-central-1", "eu-north-1"}, track.RegionalRegions)
+		}
+	}
+}
+
+func TestGatherTracks_ShouldNotReadDeploymentConfigAsDefaultTrackConfig(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "step1_deploy/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "runiac.yml", []byte("project: hello-world\nprimary_region: us-central1\nregional_regions:\n  - us-west1\n"), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{Fs: stubFs, Log: logger}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll:       true,
+		PrimaryRegion:   "us-east1",
+		RegionalRegions: []string{"us-east4"},
+	})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, mockTracks, 1)
+	require.True(t, mockTracks[0].IsDefaultTrack)
+	require.Equal(t, "us-east1", mockTracks[0].PrimaryRegion, "The deployment's configuration file should not override the default track's regions")
+	require.Equal(t, []string{"us-east4"}, mockTracks[0].RegionalRegions)
+	require.Equal(t, "us-east1", mockTracks[0].OrderedSteps[1][0].DeployConfig.PrimaryRegion)
+}
+
+func TestRequestApprovalImpl_ShouldApproveOnlyWhenCommandSucceeds(t *testing.T) {
+	request := tracks.ApprovalRequest{TrackName: "track", Phase: "regional", Regions: []string{"us-east-2"}}
+
+	approved, err := tracks.RequestApprovalImpl(context.Background(), logger, config.Config{ApprovalCommand: `test "$RUNIAC_APPROVAL_TRACK" = track`}, request)
+	require.NoError(t, err)
+	require.True(t, approved, "A successful approval command should approve the request")
+
+	approved, err = tracks.RequestApprovalImpl(context.Background(), logger, config.Config{ApprovalCommand: "exit 1"}, request)
+	require.NoError(t, err)
+	require.False(t, approved, "A failing approval command should deny the request")
+
+	approved, err = tracks.RequestApprovalImpl(context.Background(), logger, config.Config{}, request)
+	require.Error(t, err, "Pausing without an approval command should error")
+	require.False(t, approved)
+}
+
+func TestAddToTrackOutput(t *testing.T) {
+	stepOutputVariables := make(map[string]interface{})
+	stepOutputVariables["resource_name"] = "my-cool-resource"
+	stepOutputVariables["resource_id"] = "resource/my-cool-resource"
+
+	stepOutput := config.StepOutput{
+		OutputVariables: stepOutputVariables,
+		StepName:        "cool_step1",
+	}
+
+	trackOutputVars := make(map[string]map[string]string)
+
+	mockPrevStepVars := tracks.AppendTrackOutput(trackOutputVars, stepOutput, "")
+
+	require.Equal(t, "my-cool-resource", mockPrevStepVars[stepOutput.StepName]["resource_name"], "The track output should have the correct key and value set")
+	require.Equal(t, "resource/my-cool-resource", mockPrevStepVars[stepOutput.StepName]["resource_id"], "The track output should have the correct key and value set")
+}
+
+func TestAppendPreTrackOutputsToDefaultStepOutputVariables_AddsPrimaryRegionExecutionsFromPreTrackToVars(t *testing.T) {
+	// Mock existing step output vars
+	defaultStepOutputVariables := make(map[string]map[string]string)
+	defaultStepOutputVariables["iam"] = map[string]string{
+		"var1": "out1",
+	}
+
+	preTrackOutputs := &tracks.Output{
+		Name: tracks.PRE_TRACK_NAME,
+		PrimaryStepOutputVariables: map[string]map[string]string{
+			"account": {
+				"name": "new-account",
+			},
+		},
+		Executions: []tracks.RegionExecution{
+			{
+				RegionDeployType: config.PrimaryRegionDeployType,
+				Region:           "centralus",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{
+						"account": {
+							"name": "new-account",
+						},
+					},
+				},
+			},
+			{
+				RegionDeployType: config.RegionalRegionDeployType,
+				Region:           "centralus",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{
+						"account": {
+							"group": "new-group",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	regionDeployType := config.PrimaryRegionDeployType
+	region := "centralus"
+
+	newDefaultStepOutputVariables := tracks.AppendPreTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariables, preTrackOutputs, regionDeployType, region)
+	require.NotEmpty(t, newDefaultStepOutputVariables, "The new map should not be empty")
+	require.Equal(t, 3, len(newDefaultStepOutputVariables), "The map should contain the expected number of keys")
+
+	// Existing step output vars should remain
+	iamStepOutVarMap, iamKeyExists := newDefaultStepOutputVariables["iam"]
+	require.True(t, iamKeyExists, "The map should still contain the original iam key")
+	require.Equal(t, 1, len(iamStepOutVarMap), "The iam step should have the expected number of out vars")
+	var1Value, iamKeyVar1KeyExists := iamStepOutVarMap["var1"]
+	require.True(t, iamKeyVar1KeyExists, "The original key in iam should still exist")
+	require.Equal(t, "out1", var1Value, "The output in the iam step for var1 should be the expected value")
+
+	// Pretrack outputs from the primary region only (since this was a primary region deployment) should be added
+	preTrackAccountStepOutVarMap, preTrackKeyExists := newDefaultStepOutputVariables["pretrack-account"]
+	require.True(t, preTrackKeyExists, "There should be a key for the pretrack in the expected naming format")
+	require.Equal(t, 1, len(preTrackAccountStepOutVarMap), "The pre track account step should have the expected number of out vars")
+	accountKeyVal, accountKeyExists := preTrackAccountStepOutVarMap["name"]
+	require.True(t, accountKeyExists, "The name output var from the pretrack account step should be added")
+	require.Equal(t, "new-account", accountKeyVal, "The name output var from the pretrack account step should have the expected value")
+	require.Equal(t, preTrackAccountStepOutVarMap, newDefaultStepOutputVariables["_pretrack.account"], "The pretrack outputs should also be namespaced by the pretrack name")
+}
+
+func TestAppendUpstreamTrackOutputsToDefaultStepOutputVariables_ShouldOnlyNamespaceCollidingKeys(t *testing.T) {
+	// arrange
+	upstreamOutput := func(step string, id string) tracks.Output {
+		return tracks.Output{
+			Executions: []tracks.RegionExecution{{
+				RegionDeployType: config.PrimaryRegionDeployType,
+				Region:           "us-east-1",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{step: {"id": id}},
+				},
+			}},
+		}
+	}
+
+	upstreamTrackOutputs := map[string]tracks.Output{
+		"a-b":     upstreamOutput("c", "1"),
+		"a":       upstreamOutput("b-c", "2"),
+		"network": upstreamOutput("vpc", "3"),
+	}
+
+	// act
+	vars := tracks.AppendUpstreamTrackOutputsToDefaultStepOutputVariables(nil, upstreamTrackOutputs, config.PrimaryRegionDeployType, "us-east-1")
+
+	// assert
+	require.Equal(t, map[string]map[string]string{
+		"a-b.c":       {"id": "1"},
+		"a.b-c":       {"id": "2"},
+		"network.vpc": {"id": "3"},
+		"network-vpc": {"id": "3"},
+	}, vars, "Colliding un-namespaced keys should be left out")
+}
+
+func TestExecutionOutput_OutputVariable(t *testing.T) {
+	output := tracks.ExecutionOutput{
+		Name: "app",
+		StepOutputVariables: map[string]map[string]string{
+			"deploy":            {"url": "https://app"},
+			"network.vpc":       {"vpc_id": "vpc-1"},
+			"_pretrack.account": {"id": "123"},
+		},
+	}
+
+	tests := map[string]struct {
+		qualifiedName string
+		expectedValue string
+		expectedOk    bool
+	}{
+		"OwnStep":          {qualifiedName: "deploy.url", expectedValue: "https://app", expectedOk: true},
+		"OwnTrackStep":     {qualifiedName: "app.deploy.url", expectedValue: "https://app", expectedOk: true},
+		"OtherTrackStep":   {qualifiedName: "network.vpc.vpc_id", expectedValue: "vpc-1", expectedOk: true},
+		"PreTrackStep":     {qualifiedName: "_pretrack.account.id", expectedValue: "123", expectedOk: true},
+		"MissingVariable":  {qualifiedName: "network.vpc.subnet_id"},
+		"UnqualifiedName":  {qualifiedName: "vpc_id"},
+		"UnnamespacedStep": {qualifiedName: "vpc.vpc_id"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, ok := output.OutputVariable(test.qualifiedName)
+
+			require.Equal(t, test.expectedOk, ok)
+			require.Equal(t, test.expectedValue, value)
+		})
+	}
+}
+
+func TestAppendPreTrackOutputsToDefaultStepOutputVariables_AddsRegionalExecutionsFromPreTrackToVars(t *testing.T) {
+	// Mock existing step output vars
+	defaultStepOutputVariables := make(map[string]map[string]string)
+	defaultStepOutputVariables["iam"] = map[string]string{
+		"var1": "out1",
+	}
+
+	preTrackOutputs := &tracks.Output{
+		Name: tracks.PRE_TRACK_NAME,
 		PrimaryStepOutputVariables: map[string]map[string]string{
 			"account": {
 				"name": "new-account",
 			},
 		},
-		Executions: []tracks.RegionExecution{
-			{
-				RegionDeployType: config.PrimaryRegionDeployType,
-				Region:           "centralus",
-				Output: tracks.ExecutionOutput{
-					StepOutputVariables: map[string]map[string]string{
-						"account": {
-							"name": "new-account",
-						},
-					},
-				},
+		Executions: []tracks.RegionExecution{
+			{
+				RegionDeployType: config.PrimaryRegionDeployType,
+				Region:           "centralus",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{
+						"account": {
+							"name": "new-account",
+						},
+					},
+				},
+			},
+			{
+				RegionDeployType: config.RegionalRegionDeployType,
+				Region:           "centralus",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{
+						"account": {
+							"group": "new-group1-1",
+						},
+					},
+				},
+			},
+			{
+				RegionDeployType: config.RegionalRegionDeployType,
+				Region:           "eastus",
+				Output: tracks.ExecutionOutput{
+					StepOutputVariables: map[string]map[string]string{
+						"account": {
+							"group": "new-group-2",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	regionDeployType := config.RegionalRegionDeployType
+	region := "centralus"
+
+	newDefaultStepOutputVariables := tracks.AppendPreTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariables, preTrackOutputs, regionDeployType, region)
+	fmt.Printf("newDefaultStepOutputVariables: %+v\n", newDefaultStepOutputVariables)
+	require.NotEmpty(t, newDefaultStepOutputVariables, "The new map should not be empty")
+	require.Equal(t, 3, len(newDefaultStepOutputVariables), "The map should contain the expected number of keys")
+
+	// Existing step output vars should remain
+	iamStepOutVarMap, iamKeyExists := newDefaultStepOutputVariables["iam"]
+	require.True(t, iamKeyExists, "The map should still contain the original iam key")
+	require.Equal(t, 1, len(iamStepOutVarMap), "The iam step should have the expected number of out vars")
+	var1Value, iamKeyVar1KeyExists := iamStepOutVarMap["var1"]
+	require.True(t, iamKeyVar1KeyExists, "The original key in iam should still exist")
+	require.Equal(t, "out1", var1Value, "The output in the iam step for var1 should be the expected value")
+
+	// Pretrack outputs from the regional deployment (in the same region) should be added
+	preTrackAccountStepOutVarMap, preTrackKeyExists := newDefaultStepOutputVariables["pretrack-account"]
+	require.True(t, preTrackKeyExists, "There should be a key for the pretrack in the expected naming format")
+	require.Equal(t, 1, len(preTrackAccountStepOutVarMap), "The pre track account step should have the expected number of out vars")
+	groupKeyVal, groupKeyExists := preTrackAccountStepOutVarMap["group"]
+	require.True(t, groupKeyExists, "The group output var from the pretrack account step should be added")
+	require.Equal(t, "new-group1-1", groupKeyVal, "The group output var from the pretrack account step should have the expected value")
+}
+
+func TestAppendTrackOutput_WithRegionalStepDeploymentOutput(t *testing.T) {
+	stepOutputVariables := make(map[string]interface{})
+	stepOutputVariables["resource_name"] = "my-cool-resource"
+	stepOutputVariables["resource_id"] = "resource/my-cool-resource"
+
+	stepOutput := config.StepOutput{
+		OutputVariables:  stepOutputVariables,
+		StepName:         "cool_step1",
+		RegionDeployType: config.RegionalRegionDeployType,
+	}
+
+	trackOutputVars := make(map[string]map[string]string)
+
+	mockPrevStepVars := tracks.AppendTrackOutput(trackOutputVars, stepOutput, "")
+
+	key := fmt.Sprintf("%s-%s", stepOutput.StepName, config.RegionalRegionDeployType.String())
+
+	for k, v := range stepOutputVariables {
+		require.Equal(t, v, mockPrevStepVars[key][k], "The track output should match the stubbed key value: %s, %s", k, v)
+	}
+}
+
+func TestAppendTrackOutput_ShouldKeyRegionalOutputsByStrategy(t *testing.T) {
+	tests := map[string]struct {
+		strategy       string
+		expectedParams map[string]string
+	}{
+		"ShouldSuffixByDefault": {
+			strategy: "",
+			expectedParams: map[string]string{
+				"vpc-vpc_id":           "vpc-primary",
+				"vpc-regional-vpc_id":  "vpc-regional",
+				"vpc-regional-subnets": "a,b",
+			},
+		},
+		"ShouldSuffix": {
+			strategy: config.SuffixRegionalOutputKey,
+			expectedParams: map[string]string{
+				"vpc-vpc_id":           "vpc-primary",
+				"vpc-regional-vpc_id":  "vpc-regional",
+				"vpc-regional-subnets": "a,b",
+			},
+		},
+		"ShouldPrefix": {
+			strategy: config.PrefixRegionalOutputKey,
+			expectedParams: map[string]string{
+				"vpc-vpc_id":           "vpc-primary",
+				"regional-vpc-vpc_id":  "vpc-regional",
+				"regional-vpc-subnets": "a,b",
+			},
+		},
+		"ShouldNest": {
+			strategy: config.NestedRegionalOutputKey,
+			expectedParams: map[string]string{
+				"vpc-vpc_id":   "vpc-primary",
+				"vpc-regional": `{"subnets":"a,b","vpc_id":"vpc-regional"}`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			primaryOutputVars := tracks.AppendTrackOutput(map[string]map[string]string{}, config.StepOutput{
+				StepName:         "vpc",
+				RegionDeployType: config.PrimaryRegionDeployType,
+				OutputVariables:  map[string]interface{}{"vpc_id": "vpc-primary"},
+			}, test.strategy)
+
+			// regional executions start from the primary step outputs
+			regionalOutputVars := map[string]map[string]string{}
+			for k, v := range primaryOutputVars {
+				regionalOutputVars[k] = v
+			}
+
+			// act
+			regionalOutputVars = tracks.AppendTrackOutput(regionalOutputVars, config.StepOutput{
+				StepName:         "vpc",
+				RegionDeployType: config.RegionalRegionDeployType,
+				OutputVariables:  map[string]interface{}{"vpc_id": "vpc-regional", "subnets": "a,b"},
+			}, test.strategy)
+
+			// assert
+			params := steps.AppendToStepParams(map[string]string{}, regionalOutputVars)
+			require.Equal(t, test.expectedParams, params, "Consuming steps should receive the regional outputs keyed by the strategy")
+			require.Equal(t, map[string]string{"vpc_id": "vpc-primary"}, primaryOutputVars["vpc"], "Primary step outputs shared with other regions should not change")
+		})
+	}
+}
+
+type spyExecuteStep struct {
+	OutputVars map[string]map[string]string
+	StepName   string
+}
+
+func TestExecuteDeployTrackRegion_ShouldPassRegionalVariables(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	trackOutputVars := []spyExecuteStep{}
+
+	stubStepP1OutputVars := map[string]interface{}{
+		"var": "var",
+	}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		trackOutputVars = append(trackOutputVars, spyExecuteStep{
+			OutputVars: defaultStepOutputVariables,
+			StepName:   s.Name,
+		})
+
+		if s.ProgressionLevel == 1 {
+			s.Output.OutputVariables = stubStepP1OutputVars
+			s.Output.Status = config.Success
+			s.Output.StepName = s.Name
+		}
+		s.Output.RegionDeployType = regionDeployType
+		s.Output.Region = region
+		out <- s
+		return
+	}
+
+	regionalExecution := tracks.RegionExecution{
+		TrackName:                  "",
+		TrackDir:                   "",
+		TrackStepProgressionsCount: 2,
+		TrackStepsWithTestsCount:   0,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {
+				{
+					Name:                   "step1_p1",
+					ProgressionLevel:       1,
+					RegionalResourcesExist: true,
+				},
+				{
+					Name:                   "step2_p1",
+					ProgressionLevel:       1,
+					RegionalResourcesExist: true,
+				},
+			},
+			2: {
+				{
+					Name:                   "step_p2",
+					RegionalResourcesExist: true,
+				},
+			},
+		},
+		Logger:           logger,
+		Fs:               fs,
+		Output:           tracks.ExecutionOutput{},
+		Region:           "",
+		RegionDeployType: config.RegionalRegionDeployType,
+		DefaultStepOutputVariables: map[string]map[string]string{
+			"step1_p1": {
+				"primaryvarkey": "primaryvarvalue",
+			},
+		},
+	}
+
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- regionalExecution
+
+	primaryTrackExecution := <-primaryOutChan
+
+	require.NotNil(t, primaryTrackExecution)
+
+	expectedStepP1OutputVarsStrings := map[string]map[string]string{
+		"step1_p1-regional": {
+			"var": "var",
+		},
+		"step2_p1-regional": {
+			"var": "var",
+		},
+		"step1_p1": {
+			"primaryvarkey": "primaryvarvalue",
+		},
+	}
+
+	var trackOutputVarsSpyStepP2 map[string]map[string]string
+	for _, outputVars := range trackOutputVars {
+		if outputVars.StepName == "step_p2" {
+			trackOutputVarsSpyStepP2 = outputVars.OutputVars
+		}
+	}
+
+	require.Equal(t, expectedStepP1OutputVarsStrings["step1_p1"], trackOutputVarsSpyStepP2["step1_p1"], "Primary execution output vars should be passed to regional")
+	require.Equal(t, expectedStepP1OutputVarsStrings["step1_p1-regional"], trackOutputVarsSpyStepP2["step1_p1-regional"], "Output vars should be passed from previous progression steps to current progression steps")
+	require.Equal(t, expectedStepP1OutputVarsStrings["step2_p1-regional"], trackOutputVarsSpyStepP2["step2_p1-regional"], "Output vars should be passed from previous progression steps to current progression steps")
+
+}
+
+func TestExecuteDeployTrackRegion_ShouldNotExecuteSecondProgressionWhenFirstFails(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	executeStepSpy := map[string]config.Step{}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		executeStepSpy[s.Name] = s
+
+		s.Output = config.StepOutput{
+			Status: config.Fail,
+		}
+		out <- s
+		return
+	}
+	regionalExecution := tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		TrackStepProgressionsCount: 2,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {
+				{
+					ID:        "",
+					Name:      "step_p1",
+					TrackName: "",
+					Dir:       "",
+				},
+			},
+			2: {
+				{
+					ID:        "",
+					Name:      "step_p2",
+					TrackName: "",
+					Dir:       "",
+				},
+			},
+		},
+	}
+
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- regionalExecution
+	primaryTrackExecution := <-primaryOutChan
+
+	require.NotNil(t, primaryTrackExecution)
+	require.Len(t, executeStepSpy, 1, "Should not execute the second progression step with a failure in first progression")
+}
+
+func TestExecuteDeployTrackRegion_ShouldExecuteSecondProgressionWhenFirstFailsAndContinuingOnError(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	executeStepSpy := map[string]config.Step{}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		executeStepSpy[s.Name] = s
+
+		s.Output = config.StepOutput{
+			Status: config.Success,
+		}
+		if s.Name == "step_p1" {
+			s.Output.Status = config.Fail
+		}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	regionalExecution := tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		TrackStepProgressionsCount: 2,
+		ContinueOnError:            true,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "step_p1"}},
+			2: {{Name: "step_p2"}},
+		},
+	}
+
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- regionalExecution
+	primaryTrackExecution := <-primaryOutChan
+
+	require.Len(t, executeStepSpy, 2, "Should execute the second progression step despite a failure in the first progression")
+	require.Equal(t, config.Success, primaryTrackExecution.Output.Steps["step_p2"].Output.Status)
+	require.Equal(t, 1, primaryTrackExecution.Output.FailureCount, "Region execution should still be failed")
+}
+
+func TestExecuteDeployTrackRegion_ShouldCancelRemainingStepsWhenDeploymentIsCancelled(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executeStepSpy := map[string]config.Step{}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		executeStepSpy[s.Name] = s
+
+		// the deployment is interrupted while the first step executes
+		cancel()
+
+		s.Output = config.StepOutput{
+			Status: config.Cancelled,
+		}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	regionalExecution := tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		TrackStepProgressionsCount: 2,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "step_p1"}},
+			2: {{Name: "step_p2"}},
+		},
+	}
+
+	go tracks.ExecuteDeployTrackRegion(ctx, primaryInChan, primaryOutChan)
+	primaryInChan <- regionalExecution
+	primaryTrackExecution := <-primaryOutChan
+
+	require.Len(t, executeStepSpy, 1, "Should not execute steps after the deployment was cancelled")
+	require.Equal(t, config.Cancelled, primaryTrackExecution.Output.Steps["step_p1"].Output.Status)
+	require.Equal(t, config.Cancelled, primaryTrackExecution.Output.Steps["step_p2"].Output.Status)
+	require.Equal(t, 2, primaryTrackExecution.Output.CancelledCount)
+	require.Equal(t, 0, primaryTrackExecution.Output.SkippedCount)
+	require.Equal(t, 0, primaryTrackExecution.Output.FailureCount)
+}
+
+func TestExecuteDeployTrackRegion_ShouldCountCancelledStepsSeparatelyFromFailures(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	statuses := map[string]config.DeployResult{
+		"succeeded": config.Success,
+		"failed":    config.Fail,
+		"skipped":   config.Skipped,
+		"cancelled": config.Cancelled,
+	}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		s.Output = config.StepOutput{
+			Status: statuses[s.Name],
+		}
+		if s.Output.Status == config.Fail || s.Output.Status == config.Cancelled {
+			s.Output.Err = errors.New("signal: interrupt")
+		}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		TrackStepProgressionsCount: 1,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "succeeded"}, {Name: "failed"}, {Name: "skipped"}, {Name: "cancelled"}},
+		},
+	}
+	primaryTrackExecution := <-primaryOutChan
+
+	require.Equal(t, 2, primaryTrackExecution.Output.ExecutedCount)
+	require.Equal(t, 1, primaryTrackExecution.Output.SkippedCount)
+	require.Equal(t, 1, primaryTrackExecution.Output.CancelledCount)
+	require.Equal(t, 1, primaryTrackExecution.Output.FailureCount, "Cancelled steps should not be counted as failures")
+	require.Len(t, primaryTrackExecution.Output.FailedSteps, 1)
+	require.Equal(t, "failed", primaryTrackExecution.Output.FailedSteps[0].Name)
+}
+
+func TestExecuteDeployTrackRegion_ShouldDetectStepsOverwritingOutputVariables(t *testing.T) {
+	tests := map[string]struct {
+		strict         bool
+		expectedStatus config.DeployResult
+		expectedVpcID  string
+	}{
+		"Warns": {
+			expectedStatus: config.Success,
+			expectedVpcID:  "vpc-1",
+		},
+		"Strict": {
+			strict:         true,
+			expectedStatus: config.Fail,
+			expectedVpcID:  "vpc-2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			outChan := make(chan tracks.RegionExecution, 1)
+			inChan := make(chan tracks.RegionExecution, 1)
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				s.Output = config.StepOutput{
+					Status:           config.Success,
+					StepName:         s.Name,
+					RegionDeployType: regionDeployType,
+					OutputVariables:  map[string]interface{}{"vpc_id": "vpc-1"},
+				}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), inChan, outChan)
+			inChan <- tracks.RegionExecution{
+				Logger: logger,
+				Fs:     fs,
+				Output: tracks.ExecutionOutput{},
+				// the primary outputs of a step named vpc-regional
+				DefaultStepOutputVariables: map[string]map[string]string{"vpc-regional": {"vpc_id": "vpc-2"}},
+				Region:                     "us-east-2",
+				RegionDeployType:           config.RegionalRegionDeployType,
+				TrackStepProgressionsCount: 1,
+				StrictOutputVariables:      test.strict,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {{Name: "vpc", RegionalResourcesExist: true}},
+				},
+			}
+			execution := <-outChan
+
+			// assert
+			step := execution.Output.Steps["vpc"]
+			require.Equal(t, test.expectedStatus, step.Output.Status)
+			require.Equal(t, test.expectedVpcID, execution.Output.StepOutputVariables["vpc-regional"]["vpc_id"])
+
+			if test.strict {
+				require.EqualError(t, step.Output.Err, "output variable vpc-regional-vpc_id of step vpc-regional would be overwritten by step vpc")
+				require.Equal(t, 1, execution.Output.FailureCount)
+			}
+		})
+	}
+}
+
+func TestExecuteDeployTrackRegion_ShouldLimitStepOutputVariablesToOutputScope(t *testing.T) {
+	tests := map[string]struct {
+		outputScope            string
+		expectedNextVisible    bool
+		expectedLaterVisible   bool
+		expectedInTrackOutputs bool
+	}{
+		"Default": {
+			expectedNextVisible:    true,
+			expectedLaterVisible:   true,
+			expectedInTrackOutputs: true,
+		},
+		"Track": {
+			outputScope:            config.OutputScopeTrack,
+			expectedNextVisible:    true,
+			expectedLaterVisible:   true,
+			expectedInTrackOutputs: true,
+		},
+		"Progression": {
+			outputScope:         config.OutputScopeProgression,
+			expectedNextVisible: true,
+		},
+		"None": {
+			outputScope: config.OutputScopeNone,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			primaryOutChan := make(chan tracks.RegionExecution, 1)
+			primaryInChan := make(chan tracks.RegionExecution, 1)
+
+			var mutex sync.Mutex
+			received := map[string]map[string]map[string]string{}
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				mutex.Lock()
+				received[s.Name] = defaultStepOutputVariables
+				mutex.Unlock()
+
+				s.Output = config.StepOutput{
+					Status:          config.Success,
+					StepName:        s.Name,
+					OutputVariables: map[string]interface{}{"id": s.Name},
+				}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+			primaryInChan <- tracks.RegionExecution{
+				Logger:                     logger,
+				Fs:                         fs,
+				Output:                     tracks.ExecutionOutput{},
+				TrackStepProgressionsCount: 3,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {{Name: "network", Config: config.StepConfig{OutputScope: test.outputScope}}},
+					2: {{Name: "cluster"}},
+					3: {{Name: "app"}},
+				},
+			}
+			primaryTrackExecution := <-primaryOutChan
+
+			// assert
+			_, nextVisible := received["cluster"]["network"]
+			require.Equal(t, test.expectedNextVisible, nextVisible, "Next progression should only receive the outputs within scope")
+
+			_, laterVisible := received["app"]["network"]
+			require.Equal(t, test.expectedLaterVisible, laterVisible, "Later progressions should only receive the outputs within scope")
+
+			_, inTrackOutputs := primaryTrackExecution.Output.StepOutputVariables["network"]
+			require.Equal(t, test.expectedInTrackOutputs, inTrackOutputs, "Only track scoped outputs should be passed on from the track")
+			require.Equal(t, "cluster", primaryTrackExecution.Output.StepOutputVariables["cluster"]["id"], "Steps without a scope should keep passing on their outputs")
+		})
+	}
+}
+
+func TestExecuteDeployTrackRegion_ShouldCleanupWorkdirsAccordingToRetention(t *testing.T) {
+	tests := map[string]struct {
+		retention        string
+		expectedRetained []string
+	}{
+		"Default": {
+			expectedRetained: []string{"failed"},
+		},
+		"OnFailure": {
+			retention:        config.RetainWorkdirsOnFailure,
+			expectedRetained: []string{"failed"},
+		},
+		"Always": {
+			retention:        config.AlwaysRetainWorkdirs,
+			expectedRetained: []string{"failed", "succeeded"},
+		},
+		"Never": {
+			retention: config.NeverRetainWorkdirs,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			stubFs := afero.NewMemMapFs()
+			regionalOutChan := make(chan tracks.RegionExecution, 1)
+			regionalInChan := make(chan tracks.RegionExecution, 1)
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				_ = fs.MkdirAll(steps.RegionalWorkdir(s, region), 0700)
+
+				s.Output = config.StepOutput{Status: config.Success}
+				if s.Name == "failed" {
+					s.Output.Status = config.Fail
+				}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			deployConfig := config.Config{WorkdirRetention: test.retention}
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), regionalInChan, regionalOutChan)
+			regionalInChan <- tracks.RegionExecution{
+				Logger:                     logger,
+				Fs:                         stubFs,
+				Output:                     tracks.ExecutionOutput{},
+				Region:                     "us-east-2",
+				RegionDeployType:           config.RegionalRegionDeployType,
+				TrackStepProgressionsCount: 1,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {
+						{Name: "succeeded", Dir: "track/step1_succeeded", RegionalResourcesExist: true, DeployConfig: deployConfig},
+						{Name: "failed", Dir: "track/step1_failed", RegionalResourcesExist: true, DeployConfig: deployConfig},
+					},
+				},
+			}
+			<-regionalOutChan
+
+			// assert
+			var retained []string
+			for _, step := range []string{"failed", "succeeded"} {
+				if exists, _ := afero.DirExists(stubFs, fmt.Sprintf("track/step1_%s/regional-us-east-2", step)); exists {
+					retained = append(retained, step)
+				}
+			}
+			require.Equal(t, test.expectedRetained, retained)
+		})
+	}
+}
+
+func TestExecuteDeployTrackRegion_ShouldLimitParallelStepsPerProgression(t *testing.T) {
+	tests := map[string]struct {
+		maxParallelSteps    int
+		expectedMaxInFlight int
+	}{
+		"Unlimited": {
+			maxParallelSteps:    0,
+			expectedMaxInFlight: 4,
+		},
+		"Limited": {
+			maxParallelSteps:    2,
+			expectedMaxInFlight: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			primaryOutChan := make(chan tracks.RegionExecution, 1)
+			primaryInChan := make(chan tracks.RegionExecution, 1)
+
+			var mutex sync.Mutex
+			var events []int
+			inFlight := 0
+			maxInFlight := 0
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				mutex.Lock()
+				events = append(events, stepProgression)
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mutex.Unlock()
+
+				time.Sleep(50 * time.Millisecond)
+
+				mutex.Lock()
+				inFlight--
+				mutex.Unlock()
+
+				s.Output = config.StepOutput{Status: config.Success}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+			primaryInChan <- tracks.RegionExecution{
+				Logger:                     logger,
+				Fs:                         fs,
+				Output:                     tracks.ExecutionOutput{},
+				TrackStepProgressionsCount: 2,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+					2: {{Name: "e"}, {Name: "f"}},
+				},
+				MaxParallelSteps: test.maxParallelSteps,
+			}
+			primaryTrackExecution := <-primaryOutChan
+
+			// assert
+			require.Equal(t, 6, primaryTrackExecution.Output.ExecutedCount)
+			require.Equal(t, test.expectedMaxInFlight, maxInFlight)
+			require.Equal(t, []int{1, 1, 1, 1, 2, 2}, events, "Every step in a progression should complete before the next progression starts")
+		})
+	}
+}
+
+func TestExecuteDeployTrackRegion_ShouldCountRateLimitedStepsSeparately(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		switch s.Name {
+		case "throttled_then_succeeded":
+			s.Output = config.StepOutput{Status: config.Success, RateLimited: true}
+		case "throttled":
+			s.Output = config.StepOutput{Status: config.Fail, RateLimited: true, Err: errors.New("ThrottlingException: Rate exceeded")}
+		default:
+			s.Output = config.StepOutput{Status: config.Success}
+		}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		RegionDeployType:           config.PrimaryRegionDeployType,
+		TrackStepProgressionsCount: 1,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "throttled_then_succeeded"}, {Name: "throttled"}, {Name: "succeeded"}},
+		},
+	}
+	execution := <-primaryOutChan
+
+	require.Equal(t, 2, execution.Output.RateLimitedCount, "Rate limited steps should be counted regardless of their result")
+	require.Equal(t, 1, execution.Output.FailureCount)
+	require.Equal(t, 3, execution.Output.ExecutedCount)
+}
+
+func TestExecuteDeployTrackRegion_ShouldReceiveEveryStepWithBufferedChannels(t *testing.T) {
+	tests := map[string]struct {
+		channelBufferSize int
+	}{
+		"ShouldReceiveEveryStepUnbuffered":          {channelBufferSize: 0},
+		"ShouldReceiveEveryStepWithSmallerBuffer":   {channelBufferSize: 4},
+		"ShouldReceiveEveryStepWithBufferExceeding": {channelBufferSize: 64},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			primaryOutChan := make(chan tracks.RegionExecution, 1)
+			primaryInChan := make(chan tracks.RegionExecution, 1)
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, OutputVariables: map[string]interface{}{"name": s.Name}}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			orderedSteps := map[int][]config.Step{}
+			for i := 0; i < 20; i++ {
+				orderedSteps[1] = append(orderedSteps[1], config.Step{Name: fmt.Sprintf("first_%d", i)})
+			}
+			for i := 0; i < 10; i++ {
+				orderedSteps[2] = append(orderedSteps[2], config.Step{Name: fmt.Sprintf("second_%d", i)})
+			}
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+			primaryInChan <- tracks.RegionExecution{
+				Logger:                     logger,
+				Fs:                         fs,
+				Output:                     tracks.ExecutionOutput{},
+				RegionDeployType:           config.PrimaryRegionDeployType,
+				TrackStepProgressionsCount: 2,
+				TrackOrderedSteps:          orderedSteps,
+				ChannelBufferSize:          test.channelBufferSize,
+			}
+			execution := <-primaryOutChan
+
+			// assert
+			require.Equal(t, 30, execution.Output.ExecutedCount)
+			require.Len(t, execution.Output.Steps, 30, "Every step's result should be received")
+			require.Len(t, execution.Output.StepOutputVariables, 30, "Every step's outputs should be passed along")
+			require.Equal(t, "second_9", execution.Output.StepOutputVariables["second_9"]["name"])
+		})
+	}
+}
+
+func TestExecuteStepImpl_ShouldRetryBasedOnFailureClassification(t *testing.T) {
+	tests := map[string]struct {
+		err              error
+		retries          int
+		expectedAttempts int
+		expectedCategory config.FailureCategory
+	}{
+		"ShouldRetryRetryableFailures": {
+			err:              errors.New("ThrottlingException: Rate exceeded"),
+			retries:          2,
+			expectedAttempts: 3,
+			expectedCategory: config.RetryableFailure,
+		},
+		"ShouldNotRetryUserErrors": {
+			err:              errors.New("Error: Unsupported argument"),
+			retries:          2,
+			expectedAttempts: 1,
+			expectedCategory: config.UserFailure,
+		},
+		"ShouldNotRetryByDefault": {
+			err:              errors.New("ThrottlingException: Rate exceeded"),
+			expectedAttempts: 1,
+			expectedCategory: config.RetryableFailure,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubRunner := mocks.NewMockStepper(ctrl)
+			stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+				return exec, nil
+			})
+			stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{
+				Status: config.Fail,
+				Err:    test.err,
+			}).Times(test.expectedAttempts)
+
+			out := make(chan config.Step, 1)
+
+			// act
+			tracks.ExecuteStepImpl(context.Background(), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+				Name:         "step",
+				Runner:       stubRunner,
+				DeployConfig: config.Config{RetryableFailureRetries: test.retries},
+			}, out, false)
+
+			s := <-out
+
+			// assert
+			require.Equal(t, config.Fail, s.Output.Status)
+			require.Equal(t, test.expectedCategory, s.Output.FailureCategory)
+		})
+	}
+}
+
+func TestExecuteStepImpl_ShouldSucceedWhenRetriedFailureRecovers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tracks.FailureClassifier = stubFailureClassifier{category: config.RetryableFailure}
+	defer func() { tracks.FailureClassifier = steps.DefaultFailureClassifier{} }()
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+		return exec, nil
+	})
+	gomock.InOrder(
+		stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{Status: config.Fail, Err: errors.New("eventual consistency")}),
+		stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{Status: config.Success}),
+	)
+
+	out := make(chan config.Step, 1)
+
+	// act
+	tracks.ExecuteStepImpl(context.Background(), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:         "step",
+		Runner:       stubRunner,
+		DeployConfig: config.Config{RetryableFailureRetries: 1},
+	}, out, false)
+
+	s := <-out
+
+	// assert
+	require.Equal(t, config.Success, s.Output.Status, "A custom classifier should drive the retry")
+	require.Empty(t, s.Output.FailureCategory)
+	require.Equal(t, 2, s.Output.Attempts)
+}
+
+func TestExecuteStepImpl_ShouldRecordStepTiming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+		return exec, nil
+	})
+	stubRunner.EXPECT().ExecuteStep(gomock.Any()).DoAndReturn(func(exec config.StepExecution) config.StepOutput {
+		time.Sleep(10 * time.Millisecond)
+		return config.StepOutput{Status: config.Success}
+	})
+
+	out := make(chan config.Step, 1)
+	before := time.Now()
+
+	// act
+	tracks.ExecuteStepImpl(context.Background(), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:   "step",
+		Runner: stubRunner,
+	}, out, false)
+
+	s := <-out
+
+	// assert
+	require.False(t, s.Output.StartTime.Before(before), "The step should start once executed")
+	require.True(t, s.Output.EndTime.After(s.Output.StartTime))
+	require.Equal(t, s.Output.EndTime.Sub(s.Output.StartTime), s.Output.Duration)
+	require.GreaterOrEqual(t, int64(s.Output.Duration), int64(10*time.Millisecond))
+}
+
+func TestExecuteDeployTrackRegion_ShouldRecordRegionExecutionDuration(t *testing.T) {
+	// arrange
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		time.Sleep(10 * time.Millisecond)
+		s.Output = config.StepOutput{Status: config.Success}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	inChan := make(chan tracks.RegionExecution, 1)
+	outChan := make(chan tracks.RegionExecution, 1)
+
+	// act
+	go tracks.ExecuteDeployTrackRegion(context.Background(), inChan, outChan)
+	inChan <- tracks.RegionExecution{
+		TrackName:                  "track",
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		Region:                     "us-east-1",
+		TrackStepProgressionsCount: 2,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "first"}},
+			2: {{Name: "second"}},
+		},
+	}
+	execution := <-outChan
+
+	// assert
+	require.GreaterOrEqual(t, int64(execution.Output.Duration), int64(20*time.Millisecond), "The duration should span every progression")
+}
+
+func TestExecuteDeployTrackRegion_ShouldMergeExtraStepInputs(t *testing.T) {
+	var test = map[string]struct {
+		asDefaults bool
+		expected   map[string]map[string]string
+	}{
+		"extra step inputs override step outputs": {
+			asDefaults: false,
+			expected: map[string]map[string]string{
+				"pretrack-account": {"group": "extra-group"},
+				"vpc":              {"vpc_id": "extra-vpc"},
+				"dns":              {"zone": "extra-zone"},
+			},
+		},
+		"extra step inputs as defaults": {
+			asDefaults: true,
+			expected: map[string]map[string]string{
+				"pretrack-account": {"group": "pretrack-group"},
+				"vpc":              {"vpc_id": "step-vpc"},
+				"dns":              {"zone": "extra-zone"},
 			},
-			{
-				RegionDeployType: config.RegionalRegionDeployType,
-				Region:           "centralus",
-				Output: tracks.ExecutionOutput{
-					StepOutputVariables: map[string]map[string]string{
-						"account": {
-							"group": "new-group1-1",
-						},
-					},
+		},
+	}
+
+	for name, tc := range test {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			var mutex sync.Mutex
+			received := map[string]map[string]map[string]string{}
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				mutex.Lock()
+				received[s.Name] = defaultStepOutputVariables
+				mutex.Unlock()
+
+				s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, RegionDeployType: regionDeployType, Region: region}
+				if s.Name == "vpc" {
+					s.Output.OutputVariables = map[string]interface{}{"vpc_id": "step-vpc"}
+				}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			inChan := make(chan tracks.RegionExecution, 1)
+			outChan := make(chan tracks.RegionExecution, 1)
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), inChan, outChan)
+			inChan <- tracks.RegionExecution{
+				TrackName:                  "track",
+				Logger:                     logger,
+				Fs:                         fs,
+				Output:                     tracks.ExecutionOutput{},
+				Region:                     "us-east-1",
+				RegionDeployType:           config.PrimaryRegionDeployType,
+				TrackStepProgressionsCount: 2,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {{Name: "vpc", ProgressionLevel: 1}},
+					2: {{Name: "app", ProgressionLevel: 2}},
 				},
-			},
-			{
-				RegionDeployType: config.RegionalRegionDeployType,
-				Region:           "eastus",
-				Output: tracks.ExecutionOutput{
-					StepOutputVariables: map[string]map[string]string{
-						"account": {
-							"group": "new-group-2",
-						},
-					},
+				DefaultStepOutputVariables: map[string]map[string]string{
+					"pretrack-account": {"group": "pretrack-group"},
 				},
-			},
-		},
+				ExtraStepInputs: map[string]string{
+					"pretrack-account-group": "extra-group",
+					"vpc-vpc_id":             "extra-vpc",
+					"dns-zone":               "extra-zone",
+				},
+				ExtraStepInputsAsDefaults: tc.asDefaults,
+			}
+			execution := <-outChan
+
+			// assert
+			require.Equal(t, tc.expected, received["app"], "Extra step inputs should be merged with the pretrack and step outputs")
+			require.Equal(t, "step-vpc", execution.Output.StepOutputVariables["vpc"]["vpc_id"], "Extra step inputs should not replace the step's own outputs")
+			require.NotContains(t, execution.Output.StepOutputVariables, "dns", "Extra step inputs should not be recorded as step outputs")
+		})
 	}
+}
 
-	regionDeployType := config.RegionalRegionDeployType
-	region := "centralus"
+func TestExecuteStepImpl_ShouldRetryFailuresMatchingRetryablePatternWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	newDefaultStepOutputVariables := tracks.AppendPreTrackOutputsToDefaultStepOutputVariables(defaultStepOutputVariables, preTrackOutputs, regionDeployType, region)
-	fmt.Printf("newDefaultStepOutputVariables: %+v\n", newDefaultStepOutputVariables)
-	require.NotEmpty(t, newDefaultStepOutputVariables, "The new map should not be empty")
-	require.Equal(t, 2, len(newDefaultStepOutputVariables), "The map should contain the expected number of keys")
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+		return exec, nil
+	})
 
-	// Existing step output vars should remain
-	iamStepOutVarMap, iamKeyExists := newDefaultStepOutputVariables["iam"]
-	require.True(t, iamKeyExists, "The map should still contain the original iam key")
-	require.Equal(t, 1, len(iamStepOutVarMap), "The iam step should have the expected number of out vars")
-	var1Value, iamKeyVar1KeyExists := iamStepOutVarMap["var1"]
-	require.True(t, iamKeyVar1KeyExists, "The original key in iam should still exist")
-	require.Equal(t, "out1", var1Value, "The output in the iam step for var1 should be the expected value")
+	var attemptTimes []time.Time
+	stubRunner.EXPECT().ExecuteStep(gomock.Any()).DoAndReturn(func(exec config.StepExecution) config.StepOutput {
+		attemptTimes = append(attemptTimes, time.Now())
+		return config.StepOutput{Status: config.Fail, Err: errors.New("InvalidParameterValue: The role cannot be assumed by the service")}
+	}).Times(3)
 
-	// Pretrack outputs from the regional deployment (in the same region) should be added
-	preTrackAccountStepOutVarMap, preTrackKeyExists := newDefaultStepOutputVariables["pretrack-account"]
-	require.True(t, preTrackKeyExists, "There should be a key for the pretrack in the expected naming format")
-	require.Equal(t, 1, len(preTrackAccountStepOutVarMap), "The pre track account step should have the expected number of out vars")
-	groupKeyVal, groupKeyExists := preTrackAccountStepOutVarMap["group"]
-	require.True(t, groupKeyExists, "The group output var from the pretrack account step should be added")
-	require.Equal(t, "new-group1-1", groupKeyVal, "The group output var from the pretrack account step should have the expected value")
+	out := make(chan config.Step, 1)
+
+	// act
+	tracks.ExecuteStepImpl(context.Background(), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:         "step",
+		Runner:       stubRunner,
+		DeployConfig: config.Config{RetryablePattern: "role cannot be assumed", RetryableFailureRetries: 2, RetryBackoff: 20 * time.Millisecond},
+	}, out, false)
+
+	s := <-out
+
+	// assert
+	require.Equal(t, config.Fail, s.Output.Status)
+	require.Equal(t, config.RetryableFailure, s.Output.FailureCategory, "A failure matching the pattern should be retryable")
+	require.Equal(t, 3, s.Output.Attempts)
+	require.GreaterOrEqual(t, int64(attemptTimes[1].Sub(attemptTimes[0])), int64(20*time.Millisecond))
+	require.GreaterOrEqual(t, int64(attemptTimes[2].Sub(attemptTimes[1])), int64(40*time.Millisecond), "The backoff should double for each retry")
 }
 
-func TestAppendTrackOutput_WithRegionalStepDeploymentOutput(t *testing.T) {
-	stepOutputVariables := make(map[string]interface{})
-	stepOutputVariables["resource_name"] = "my-cool-resource"
-	stepOutputVariables["resource_id"] = "resource/my-cool-resource"
+func TestExecuteStepImpl_ShouldPassContextToRunnerToInterruptItsProcesses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	stepOutput := config.StepOutput{
-		OutputVariables:  stepOutputVariables,
-		StepName:         "cool_step1",
-		RegionDeployType: config.RegionalRegionDeployType,
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	trackOutputVars := make(map[string]map[string]string)
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+		return exec, nil
+	})
+
+	var received context.Context
+	stubRunner.EXPECT().ExecuteStep(gomock.Any()).DoAndReturn(func(exec config.StepExecution) config.StepOutput {
+		received = exec.Context
+		return config.StepOutput{Status: config.Success}
+	})
 
-	mockPrevStepVars := tracks.AppendTrackOutput(trackOutputVars, stepOutput)
+	out := make(chan config.Step, 1)
 
-	key := fmt.Sprintf("%s-%s", stepOutput.StepName, config.RegionalRegionDeployType.String())
+	// act
+	tracks.ExecuteStepImpl(ctx, "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:   "step",
+		Runner: stubRunner,
+	}, out, false)
 
-	for k, v := range stepOutputVariables {
-		require.Equal(t, v, mockPrevStepVars[key][k], "The track output should match the stubbed key value: %s, %s", k, v)
-	}
+	<-out
+
+	// assert
+	require.Equal(t, ctx, received, "The runner should be interrupted when the deployment is cancelled")
 }
 
-type spyExecuteStep struct {
-	OutputVars map[string]map[string]string
-	StepName   string
+func TestExecuteStepImpl_ShouldCancelStepWhenCancelledDuringRetryBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+		return exec, nil
+	})
+	stubRunner.EXPECT().ExecuteStep(gomock.Any()).DoAndReturn(func(exec config.StepExecution) config.StepOutput {
+		// cancelled once the step failed, while backing off
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return config.StepOutput{Status: config.Fail, Err: errors.New("ThrottlingException: Rate exceeded")}
+	}).Times(1)
+
+	out := make(chan config.Step, 1)
+	start := time.Now()
+
+	// act
+	tracks.ExecuteStepImpl(ctx, "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:         "step",
+		Runner:       stubRunner,
+		DeployConfig: config.Config{RetryablePattern: "Rate exceeded", RetryableFailureRetries: 3, RetryBackoff: time.Hour},
+	}, out, false)
+
+	s := <-out
+
+	// assert
+	require.Equal(t, config.Cancelled, s.Output.Status, "A step cancelled while backing off should not be retried")
+	require.Equal(t, 1, s.Output.Attempts)
+	require.Less(t, int64(time.Since(start)), int64(5*time.Second), "Cancelling should not wait for the backoff to elapse")
 }
 
-func TestExecuteDeployTrackRegion_ShouldPassRegionalVariables(t *testing.T) {
+func TestExecuteStepImpl_ShouldCategorizeInitFailuresSeparatelyFromApplyFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().PreExecute(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (config.StepExecution, error) {
+		return exec, nil
+	})
+	stubRunner.EXPECT().ExecuteStep(gomock.Any()).Return(config.StepOutput{Status: config.Fail, Err: errors.New("apply failed")})
+
+	stepDir := t.TempDir()
+	out := make(chan config.Step, 2)
+
+	// act
+	// the step has no regional directory to initialize the regional execution from
+	tracks.ExecuteStepImpl(context.Background(), "us-east-2", config.RegionalRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:   "step",
+		Dir:    stepDir,
+		Runner: stubRunner,
+	}, out, false)
+	tracks.ExecuteStepImpl(context.Background(), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:   "step",
+		Dir:    stepDir,
+		Runner: stubRunner,
+	}, out, false)
+
+	initFailure := <-out
+	applyFailure := <-out
+
+	// assert
+	var initErr steps.InitError
+	require.Equal(t, config.Fail, initFailure.Output.Status)
+	require.Equal(t, config.InitFailure, initFailure.Output.FailureCategory)
+	require.True(t, errors.As(initFailure.Output.Err, &initErr), "Init failures should be an InitError")
+	require.Equal(t, steps.MissingDirInitError, initErr.Cause)
+
+	require.Equal(t, config.Fail, applyFailure.Output.Status)
+	require.NotEqual(t, config.InitFailure, applyFailure.Output.FailureCategory)
+	require.False(t, errors.As(applyFailure.Output.Err, &initErr), "Apply failures should not be an InitError")
+}
+
+type stubFailureClassifier struct {
+	category config.FailureCategory
+}
+
+func (c stubFailureClassifier) Classify(output config.StepOutput) config.FailureCategory {
+	return c.category
+}
+
+func TestExecuteDeployTrackRegion_ShouldSkipStepsWhenCancelled(t *testing.T) {
+	// arrange
 	primaryOutChan := make(chan tracks.RegionExecution, 1)
 	primaryInChan := make(chan tracks.RegionExecution, 1)
 
-	trackOutputVars := []spyExecuteStep{}
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		require.Fail(t, "Cancelled region executions should not execute steps")
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
 
-	stubStepP1OutputVars := map[string]interface{}{
-		"var": "var",
+	cancelled := make(chan struct{})
+	close(cancelled)
+
+	// act
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		Region:                     "us-west-2",
+		RegionDeployType:           config.RegionalRegionDeployType,
+		TrackStepProgressionsCount: 2,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "first", RegionalResourcesExist: true}},
+			2: {{Name: "second", RegionalResourcesExist: true}},
+		},
+		Cancelled: cancelled,
+	}
+	execution := <-primaryOutChan
+
+	// assert
+	require.Equal(t, 0, execution.Output.ExecutedCount)
+	require.Equal(t, config.Skipped, execution.Output.Steps["first"].Output.Status)
+	require.Equal(t, config.Skipped, execution.Output.Steps["second"].Output.Status)
+}
+
+func TestExecuteDeployTrackRegion_ShouldFailStepWithoutRunner(t *testing.T) {
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	// act
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		RegionDeployType:           config.PrimaryRegionDeployType,
+		Region:                     "us-east-1",
+		TrackStepProgressionsCount: 1,
+		TrackOrderedSteps: map[int][]config.Step{
+			1: {{Name: "unknown", ProgressionLevel: 1}},
+		},
 	}
+	execution := <-primaryOutChan
+
+	// assert
+	require.Equal(t, 1, execution.Output.FailureCount, "A step without a runner should fail")
+	require.Equal(t, config.Fail, execution.Output.Steps["unknown"].Output.Status)
+	require.EqualError(t, execution.Output.Steps["unknown"].Output.Err, "no runner for step unknown")
+}
+
+func TestExecuteStepImpl_ShouldRecordStepsFailingBeforeTheirRunnerExecutes(t *testing.T) {
+	// arrange
+	out := make(chan config.Step, 1)
+
+	// act
+	tracks.ExecuteStepImpl(context.Background(), "us-east-1", config.PrimaryRegionDeployType, logger, fs, map[string]map[string]string{}, 1, config.Step{
+		Name:         "unknown",
+		TrackName:    "runnerless",
+		DeployConfig: config.Config{UniqueExternalExecutionID: "run", Project: "project"},
+	}, out, false)
+	<-out
+
+	steps, err := cloudaccountdeployment.FlushTrack(logger, "runnerless")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, cloudaccountdeployment.Fail.String(), steps["run#project#runnerless#unknown"].Result, "A step failing before its runner executes should be recorded as failed")
+}
+
+func TestExecuteDeployTrackRegion_ShouldAttributeCommandTestRunnerResultsToSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primaryOutChan := make(chan tracks.RegionExecution, 1)
+	primaryInChan := make(chan tracks.RegionExecution, 1)
+
+	// the fake runner reports results without identifying the step they belong to
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().ExecuteStepTests(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (output config.StepTestOutput) {
+		output.StreamOutput, output.Err = testrunner.CommandTestRunner{Command: exec.TestCommand}.RunTests(exec, "tests", nil)
+		return
+	}).Times(2)
 
-	tracks.ExecuteStep = func(region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
 		s config.Step, out chan<- config.Step, destroy bool) {
-		trackOutputVars = append(trackOutputVars, spyExecuteStep{
-			OutputVars: defaultStepOutputVariables,
-			StepName:   s.Name,
-		})
+		s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, OutputVariables: map[string]interface{}{}}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
 
-		if s.ProgressionLevel == 1 {
-			s.Output.OutputVariables = stubStepP1OutputVars
-			s.Output.Status = config.Success
-			s.Output.StepName = s.Name
+	stepWithTestCommand := func(name string, command string) config.Step {
+		return config.Step{
+			Name:             name,
+			ProgressionLevel: 1,
+			TestsExist:       true,
+			Runner:           stubRunner,
+			Config:           config.StepConfig{TestCommand: command},
 		}
-		s.Output.RegionDeployType = regionDeployType
-		s.Output.Region = region
-		out <- s
-		return
 	}
 
-	regionalExecution := tracks.RegionExecution{
-		TrackName:                  "",
-		TrackDir:                   "",
-		TrackStepProgressionsCount: 2,
-		TrackStepsWithTestsCount:   0,
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+	primaryInChan <- tracks.RegionExecution{
+		Logger:                     logger,
+		Fs:                         fs,
+		Output:                     tracks.ExecutionOutput{},
+		RegionDeployType:           config.PrimaryRegionDeployType,
+		TrackStepProgressionsCount: 1,
+		TrackStepsWithTestsCount:   2,
 		TrackOrderedSteps: map[int][]config.Step{
-			1: {
-				{
-					Name:                   "step1_p1",
-					ProgressionLevel:       1,
-					RegionalResourcesExist: true,
-				},
-				{
-					Name:                   "step2_p1",
-					ProgressionLevel:       1,
-					RegionalResourcesExist: true,
-				},
-			},
-			2: {
-				{
-					Name:                   "step_p2",
-					RegionalResourcesExist: true,
-				},
-			},
-		},
-		Logger:           logger,
-		Fs:               fs,
-		Output:           tracks.ExecutionOutput{},
-		Region:           "",
-		RegionDeployType: config.RegionalRegionDeployType,
-		DefaultStepOutputVariables: map[string]map[string]string{
-			"step1_p1": {
-				"primaryvarkey": "primaryvarvalue",
-			},
+			1: {stepWithTestCommand("passes", "echo passed"), stepWithTestCommand("fails", "echo failed && exit 1")},
 		},
 	}
+	execution := <-primaryOutChan
 
-	go tracks.ExecuteDeployTrackRegion(primaryInChan, primaryOutChan)
-	primaryInChan <- regionalExecution
+	// assert
+	require.Equal(t, 1, execution.Output.FailedTestCount)
 
-	primaryTrackExecution := <-primaryOutChan
+	passes := execution.Output.Steps["passes"].TestOutput
+	require.Equal(t, "passes", passes.StepName)
+	require.NoError(t, passes.Err)
+	require.Contains(t, passes.StreamOutput, "passed")
 
-	require.NotNil(t, primaryTrackExecution)
+	fails := execution.Output.Steps["fails"].TestOutput
+	require.Equal(t, "fails", fails.StepName)
+	require.Error(t, fails.Err)
+	require.Contains(t, fails.StreamOutput, "failed")
+}
 
-	expectedStepP1OutputVarsStrings := map[string]map[string]string{
-		"step1_p1-regional": {
-			"var": "var",
-		},
-		"step2_p1-regional": {
-			"var": "var",
-		},
-		"step1_p1": {
-			"primaryvarkey": "primaryvarvalue",
+func TestExecuteDeployTrack_ShouldOnlyRunRegionalTestsInRegionalTestRegions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stepDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(stepDir, "regional"), 0755))
+
+	var mu sync.Mutex
+	var testedRegions []string
+
+	stubRunner := mocks.NewMockStepper(ctrl)
+	stubRunner.EXPECT().ExecuteStepTests(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (output config.StepTestOutput) {
+		mu.Lock()
+		testedRegions = append(testedRegions, exec.Region)
+		mu.Unlock()
+		return
+	}).AnyTimes()
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, OutputVariables: map[string]interface{}{}}
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	go tracks.ExecuteDeployTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:       "us-east-1",
+		RegionalRegions:     []string{"us-east-2", "us-west-2", "eu-west-1"},
+		RegionalTestRegions: []string{"us-west-2"},
+	}, tracks.Track{
+		Name:                        "track",
+		RegionalDeployment:          true,
+		StepProgressionsCount:       1,
+		StepsWithRegionalTestsCount: 1,
+		OrderedSteps: map[int][]config.Step{
+			1: {{
+				Name:                   "step",
+				Dir:                    stepDir,
+				ProgressionLevel:       1,
+				RegionalResourcesExist: true,
+				RegionalTestsExist:     true,
+				Runner:                 stubRunner,
+			}},
 		},
+	}, trackChan)
+
+	var mockOutput tracks.Output
+	select {
+	case mockOutput = <-trackChan:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "Track did not complete, test results were not collected")
 	}
 
-	var trackOutputVarsSpyStepP2 map[string]map[string]string
-	for _, outputVars := range trackOutputVars {
-		if outputVars.StepName == "step_p2" {
-			trackOutputVarsSpyStepP2 = outputVars.OutputVars
-		}
+	// assert
+	require.Len(t, mockOutput.Executions, 4, "Regional steps should still deploy to every region")
+	require.Equal(t, []string{"us-west-2"}, testedRegions, "Regional tests should only run in the regional test regions")
+}
+
+func TestExecuteDeployTrackRegion_ShouldIncludeTestOnlyFailuresInFailedStepsWhenConfigured(t *testing.T) {
+	tests := map[string]struct {
+		failedStepsIncludeTests bool
+		expectedFailedSteps     []string
+	}{
+		"ShouldIncludeTestOnlyFailures":         {failedStepsIncludeTests: true, expectedFailedSteps: []string{"fails_tests"}},
+		"ShouldNotIncludeTestFailuresByDefault": {failedStepsIncludeTests: false, expectedFailedSteps: nil},
 	}
 
-	require.Equal(t, expectedStepP1OutputVarsStrings["step1_p1"], trackOutputVarsSpyStepP2["step1_p1"], "Primary execution output vars should be passed to regional")
-	require.Equal(t, expectedStepP1OutputVarsStrings["step1_p1-regional"], trackOutputVarsSpyStepP2["step1_p1-regional"], "Output vars should be passed from previous progression steps to current progression steps")
-	require.Equal(t, expectedStepP1OutputVarsStrings["step2_p1-regional"], trackOutputVarsSpyStepP2["step2_p1-regional"], "Output vars should be passed from previous progression steps to current progression steps")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
-}
+			primaryOutChan := make(chan tracks.RegionExecution, 1)
+			primaryInChan := make(chan tracks.RegionExecution, 1)
 
-func TestExecuteDeployTrackRegion_ShouldNotExecuteSecondProgressionWhenFirstFails(t *testing.T) {
-	primaryOutChan := make(chan tracks.RegionExecution, 1)
-	primaryInChan := make(chan tracks.RegionExecution, 1)
+			stubRunner := mocks.NewMockStepper(ctrl)
+			stubRunner.EXPECT().ExecuteStepTests(gomock.Any()).DoAndReturn(func(exec config.StepExecution) (output config.StepTestOutput) {
+				if exec.StepName == "fails_tests" {
+					output.Err = errors.New("assertion failed")
+				}
+				return
+			}).Times(2)
 
-	executeStepSpy := map[string]config.Step{}
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				s.Output = config.StepOutput{Status: config.Success, StepName: s.Name, OutputVariables: map[string]interface{}{}}
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
 
-	tracks.ExecuteStep = func(region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
-		s config.Step, out chan<- config.Step, destroy bool) {
-		executeStepSpy[s.Name] = s
+			stepWithTests := func(name string) config.Step {
+				return config.Step{
+					Name:             name,
+					ProgressionLevel: 1,
+					TestsExist:       true,
+					Runner:           stubRunner,
+					DeployConfig:     config.Config{FailedStepsIncludeTests: test.failedStepsIncludeTests},
+				}
+			}
 
-		s.Output = config.StepOutput{
-			Status: config.Fail,
-		}
-		out <- s
-		return
-	}
-	regionalExecution := tracks.RegionExecution{
-		Logger:                     logger,
-		Fs:                         fs,
-		Output:                     tracks.ExecutionOutput{},
-		TrackStepProgressionsCount: 2,
-		TrackOrderedSteps: map[int][]config.Step{
-			1: {
-				{
-					ID:        "",
-					Name:      "step_p1",
-					TrackName: "",
-					Dir:       "",
-				},
-			},
-			2: {
-				{
-					ID:        "",
-					Name:      "step_p2",
-					TrackName: "",
-					Dir:       "",
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
+			primaryInChan <- tracks.RegionExecution{
+				Logger:                     logger,
+				Fs:                         fs,
+				Output:                     tracks.ExecutionOutput{},
+				RegionDeployType:           config.PrimaryRegionDeployType,
+				TrackStepProgressionsCount: 1,
+				TrackStepsWithTestsCount:   2,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {stepWithTests("passes_tests"), stepWithTests("fails_tests")},
 				},
-			},
-		},
-	}
+			}
+			execution := <-primaryOutChan
 
-	go tracks.ExecuteDeployTrackRegion(primaryInChan, primaryOutChan)
-	primaryInChan <- regionalExecution
-	primaryTrackExecution := <-primaryOutChan
+			// assert
+			require.Equal(t, 1, execution.Output.FailedTestCount)
+			require.Equal(t, 0, execution.Output.FailureCount, "Test failures should not count as deploy failures")
 
-	require.NotNil(t, primaryTrackExecution)
-	require.Len(t, executeStepSpy, 1, "Should not execute the second progression step with a failure in first progression")
+			var failedSteps []string
+			for _, s := range execution.Output.FailedSteps {
+				require.True(t, s.TestsFailedOnly, "Test only failures should be marked")
+				require.Equal(t, config.Success, s.Output.Status)
+				require.Error(t, s.TestOutput.Err)
+				failedSteps = append(failedSteps, s.Name)
+			}
+			require.Equal(t, test.expectedFailedSteps, failedSteps)
+		})
+	}
 }
 
 func TestExecuteDeployTrackRegion_ShouldSkipWhenPrimaryFails(t *testing.T) {
@@ -855,7 +5159,7 @@ func TestExecuteDeployTrackRegion_ShouldSkipWhenPrimaryFails(t *testing.T) {
 
 	executeStepSpy := map[string]config.Step{}
 
-	tracks.ExecuteStep = func(region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
 		s config.Step, out chan<- config.Step, destroy bool) {
 		executeStepSpy[s.Name] = s
 
@@ -884,7 +5188,7 @@ func TestExecuteDeployTrackRegion_ShouldSkipWhenPrimaryFails(t *testing.T) {
 		PrimaryOutput: tracks.ExecutionOutput{FailureCount: 1},
 	}
 
-	go tracks.ExecuteDeployTrackRegion(primaryInChan, primaryOutChan)
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
 	primaryInChan <- regionalExecution
 	primaryTrackExecution := <-primaryOutChan
 
@@ -915,10 +5219,454 @@ func TestExecuteDeployTrackRegion_ShouldNaWhenRegionalResourcesDoNotExist(t *tes
 		RegionDeployType: config.RegionalRegionDeployType,
 	}
 
-	go tracks.ExecuteDeployTrackRegion(primaryInChan, primaryOutChan)
+	go tracks.ExecuteDeployTrackRegion(context.Background(), primaryInChan, primaryOutChan)
 	primaryInChan <- regionalExecution
 	primaryTrackExecution := <-primaryOutChan
 
 	require.NotNil(t, primaryTrackExecution)
 	require.Equal(t, config.Na, primaryTrackExecution.Output.Steps["step_p1"].Output.Status)
 }
+
+func TestExecuteDestroyTrack_ShouldDestroyRegionalOnlyStepOnceInRegionalPass(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	destroyed := map[string][]string{}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		destroyed[s.Name] = append(destroyed[s.Name], fmt.Sprintf("%s-%s-%v", regionDeployType, region, destroy))
+		mutex.Unlock()
+
+		s.Output.Status = config.Success
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDestroyTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2"},
+	}, tracks.Track{
+		Name:                  "track",
+		RegionalDeployment:    true,
+		StepProgressionsCount: 2,
+		OrderedSteps: map[int][]config.Step{
+			1: {
+				{
+					Name:                   "global_and_regional",
+					ProgressionLevel:       1,
+					RegionalResourcesExist: true,
+				},
+			},
+			2: {
+				{
+					Name:                   "regional_only",
+					ProgressionLevel:       2,
+					RegionalResourcesExist: true,
+					RegionalOnly:           true,
+				},
+			},
+		},
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.Equal(t, []string{"regional-us-east-2-true"}, destroyed["regional_only"], "Regional only step should be destroyed exactly once in the regional pass")
+	require.Equal(t, []string{"regional-us-east-2-true", "primary-us-east-1-true"}, destroyed["global_and_regional"], "Step should be destroyed regionally then in the primary region")
+
+	for _, exec := range mockOutput.Executions {
+		if exec.RegionDeployType == config.PrimaryRegionDeployType {
+			require.Equal(t, config.Na, exec.Output.Steps["regional_only"].Output.Status, "Regional only step should not apply to the primary region")
+		}
+	}
+}
+
+func TestExecuteDestroyTrack_ShouldOnlyDestroyDestroyRegions(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	var destroyed []string
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		destroyed = append(destroyed, fmt.Sprintf("%s-%s", regionDeployType, region))
+		mutex.Unlock()
+
+		s.Output.Status = config.Success
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDestroyTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-1", "us-east-2", "eu-west-1"},
+		DestroyRegions:  []string{"eu-west-1"},
+	}, tracks.Track{
+		Name:                  "track",
+		RegionalDeployment:    true,
+		StepProgressionsCount: 1,
+		OrderedSteps: map[int][]config.Step{
+			1: {
+				{
+					Name:                   "step",
+					ProgressionLevel:       1,
+					RegionalResourcesExist: true,
+				},
+			},
+		},
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.Equal(t, []string{"regional-eu-west-1"}, destroyed, "Only the destroy regions should be destroyed, keeping the primary region")
+	require.Len(t, mockOutput.Executions, 1)
+	require.Equal(t, "eu-west-1", mockOutput.Executions[0].Region)
+}
+
+func TestExecuteDestroyTrack_ShouldDestroyRegionalRegionsDeployedFromPrimaryStepOutput(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	destroyed := map[string]map[string]map[string]string{}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		destroyed[fmt.Sprintf("%s-%s", regionDeployType, region)] = defaultStepOutputVariables
+		mutex.Unlock()
+
+		s.Output.Status = config.Success
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDestroyTrack(context.Background(), tracks.Execution{
+		Logger: logger,
+		Fs:     fs,
+		Output: tracks.ExecutionOutput{},
+		DefaultExecutionStepOutputVariables: map[string]map[string]map[string]string{
+			"primary-us-east-1":  {"regions": {"enabled_regions": `["us-west-1","us-west-2"]`}},
+			"regional-us-west-1": {"step": {"id": "west-1"}},
+			"regional-us-west-2": {"step": {"id": "west-2"}},
+		},
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-1", "us-east-2"},
+	}, tracks.Track{
+		Name:                  "track",
+		RegionalDeployment:    true,
+		StepProgressionsCount: 1,
+		Config: config.TrackConfig{
+			RegionalRegionsOutput: "regions.enabled_regions",
+		},
+		OrderedSteps: map[int][]config.Step{
+			1: {
+				{
+					Name:                   "step",
+					ProgressionLevel:       1,
+					RegionalResourcesExist: true,
+				},
+			},
+		},
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.Len(t, mockOutput.Executions, 3)
+	require.Len(t, destroyed, 3, "The primary region and the regional regions deployed to should be destroyed")
+	require.Equal(t, "west-1", destroyed["regional-us-west-1"]["step"]["id"], "Regional regions should be destroyed with their deployed step outputs")
+	require.Equal(t, "west-2", destroyed["regional-us-west-2"]["step"]["id"], "Regional regions should be destroyed with their deployed step outputs")
+	require.NotContains(t, destroyed, "regional-us-east-2", "Configured regional regions that were not deployed to should not be destroyed")
+}
+
+func TestExecuteDestroyTrack_ShouldDestroyWithPreTrackOutputsWithoutPersistedStepOutputs(t *testing.T) {
+	// arrange
+	var mutex sync.Mutex
+	destroyed := map[string]map[string]map[string]string{}
+
+	tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+		s config.Step, out chan<- config.Step, destroy bool) {
+		mutex.Lock()
+		destroyed[fmt.Sprintf("%s-%s", regionDeployType, region)] = defaultStepOutputVariables
+		mutex.Unlock()
+
+		s.Output.Status = config.Success
+		out <- s
+	}
+	defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+	preTrackOutput := &tracks.Output{
+		Name: tracks.PRE_TRACK_NAME,
+		Executions: []tracks.RegionExecution{
+			{
+				RegionDeployType: config.PrimaryRegionDeployType,
+				Region:           "us-east-1",
+				Output:           tracks.ExecutionOutput{StepOutputVariables: map[string]map[string]string{"project": {"id": "123"}}},
+			},
+			{
+				RegionDeployType: config.RegionalRegionDeployType,
+				Region:           "us-east-2",
+				Output:           tracks.ExecutionOutput{StepOutputVariables: map[string]map[string]string{"project": {"id": "456"}}},
+			},
+		},
+	}
+
+	trackChan := make(chan tracks.Output, 1)
+
+	// act
+	tracks.ExecuteDestroyTrack(context.Background(), tracks.Execution{
+		Logger:         logger,
+		Fs:             fs,
+		Output:         tracks.ExecutionOutput{},
+		PreTrackOutput: preTrackOutput,
+	}, config.Config{
+		PrimaryRegion:   "us-east-1",
+		RegionalRegions: []string{"us-east-2"},
+	}, tracks.Track{
+		Name:                  "track",
+		RegionalDeployment:    true,
+		StepProgressionsCount: 1,
+		OrderedSteps: map[int][]config.Step{
+			1: {
+				{
+					Name:                   "step",
+					ProgressionLevel:       1,
+					RegionalResourcesExist: true,
+				},
+			},
+		},
+	}, trackChan)
+
+	mockOutput := <-trackChan
+
+	// assert
+	require.Len(t, mockOutput.Executions, 2)
+	require.Equal(t, "123", destroyed["primary-us-east-1"]["pretrack-project"]["id"], "The pre-track's outputs should be available without persisted step outputs")
+	require.Equal(t, "456", destroyed["regional-us-east-2"]["pretrack-project"]["id"], "The pre-track's outputs should be available without persisted step outputs")
+}
+
+func TestGatherTracks_ShouldDetectRegionalOnlySteps(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_global/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step1_global/regional/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "tracks/track/step2_regional/regional/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll: true,
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, mockTracks, 1)
+	require.False(t, mockTracks[0].OrderedSteps[1][0].RegionalOnly, "Step with primary resources should not be regional only")
+	require.True(t, mockTracks[0].OrderedSteps[2][0].RegionalOnly, "Step with only regional resources should be regional only")
+}
+
+func TestExecuteDeployTrackRegion_ShouldRunTestsInPlanAssertionModeWhenTestingAgainstPlan(t *testing.T) {
+	var test = map[string]struct {
+		testAgainstPlan    bool
+		expectedTestsCalls int
+	}{
+		"ShouldRunTestsAgainstPlan":     {testAgainstPlan: true, expectedTestsCalls: 1},
+		"ShouldSkipTestsForPlainDryRun": {testAgainstPlan: false, expectedTestsCalls: 0},
+	}
+
+	for name, tc := range test {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubRunner := mocks.NewMockStepper(ctrl)
+
+			var testExecutions []config.StepExecution
+			stubRunner.EXPECT().ExecuteStepTests(gomock.Any()).DoAndReturn(func(exec config.StepExecution) config.StepTestOutput {
+				testExecutions = append(testExecutions, exec)
+				return config.StepTestOutput{StepName: exec.StepName}
+			}).Times(tc.expectedTestsCalls)
+
+			tracks.ExecuteStep = func(ctx context.Context, region string, regionDeployType config.RegionDeployType, entry *logrus.Entry, fs afero.Fs, defaultStepOutputVariables map[string]map[string]string, stepProgression int,
+				s config.Step, out chan<- config.Step, destroy bool) {
+				s.Output.Status = config.Success
+				s.Output.StepName = s.Name
+				out <- s
+			}
+			defer func() { tracks.ExecuteStep = tracks.ExecuteStepImpl }()
+
+			inChan := make(chan tracks.RegionExecution, 1)
+			outChan := make(chan tracks.RegionExecution, 1)
+
+			inChan <- tracks.RegionExecution{
+				Logger:                     logger,
+				Fs:                         fs,
+				Region:                     "us-east-1",
+				RegionDeployType:           config.PrimaryRegionDeployType,
+				TrackStepProgressionsCount: 1,
+				TrackStepsWithTestsCount:   1,
+				TrackOrderedSteps: map[int][]config.Step{
+					1: {
+						{
+							Name:             "step",
+							ProgressionLevel: 1,
+							TestsExist:       true,
+							Runner:           stubRunner,
+							DeployConfig: config.Config{
+								DryRun:          true,
+								TestAgainstPlan: tc.testAgainstPlan,
+							},
+						},
+					},
+				},
+			}
+
+			// act
+			go tracks.ExecuteDeployTrackRegion(context.Background(), inChan, outChan)
+			execution := <-outChan
+
+			// assert
+			require.Equal(t, 0, execution.Output.FailedTestCount)
+			require.Len(t, testExecutions, tc.expectedTestsCalls)
+
+			for _, exec := range testExecutions {
+				require.True(t, exec.TestAgainstPlan, "Tests should be invoked in plan assertion mode")
+				require.True(t, exec.DryRun, "Step should not be applied")
+			}
+		})
+	}
+}
+
+func TestLimitOutputValues_ShouldTruncateOrRejectOversizedValues(t *testing.T) {
+	stubOutputVariables := map[string]interface{}{
+		"small":     "abc",
+		"large":     "abcdefghij",
+		"multibyte": "aé€bcdefgh",
+		"number":    42,
+		"list":      []interface{}{"abcdef", "ghijkl"},
+	}
+
+	var test = map[string]struct {
+		cfg      config.Config
+		expected map[string]interface{}
+	}{
+		"ShouldNotLimitWhenUnset": {
+			cfg:      config.Config{},
+			expected: stubOutputVariables,
+		},
+		"ShouldTruncateOversizedValues": {
+			cfg: config.Config{MaxOutputValueBytes: 5},
+			expected: map[string]interface{}{
+				"small":     "abc",
+				"large":     "abcde",
+				"multibyte": "aé",
+				"number":    42,
+			},
+		},
+		"ShouldRejectOversizedValues": {
+			cfg: config.Config{MaxOutputValueBytes: 5, RejectOversizedOutputs: true},
+			expected: map[string]interface{}{
+				"small":  "abc",
+				"number": 42,
+			},
+		},
+	}
+
+	for name, tc := range test {
+		t.Run(name, func(t *testing.T) {
+			// act
+			limited := tracks.LimitOutputValues(logger, tc.cfg, stubOutputVariables)
+
+			// assert
+			require.Equal(t, tc.expected, limited)
+		})
+	}
+}
+
+func TestGatherTracks_ShouldGatherTracksFromMultipleRoots(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "repo-a/tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "repo-b/tracks/compute/step1_cluster/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "repo-b/tracks/_pretrack/step1_account/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(config.Config{
+		TargetAll:  true,
+		TrackRoots: []string{"repo-a", "repo-b"},
+	})
+
+	// assert
+	require.NoError(t, err)
+
+	trackDirs := map[string]string{}
+	for _, track := range mockTracks {
+		trackDirs[track.Name] = track.Dir
+	}
+
+	require.Equal(t, map[string]string{
+		"network":   "repo-a/tracks/network",
+		"compute":   "repo-b/tracks/compute",
+		"_pretrack": "repo-b/tracks/_pretrack",
+	}, trackDirs, "Tracks from each root should be merged")
+}
+
+func TestGatherTracks_ShouldErrorWhenTrackNamesCollideAcrossRoots(t *testing.T) {
+	// arrange
+	stubFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(stubFs, "repo-a/tracks/network/step1_vpc/main.tf", []byte(""), 0644)
+	_ = afero.WriteFile(stubFs, "repo-b/tracks/network/step1_peering/main.tf", []byte(""), 0644)
+
+	stubTracker := tracks.DirectoryBasedTracker{
+		Fs:  stubFs,
+		Log: logger,
+	}
+
+	cfg := config.Config{
+		TargetAll:  true,
+		TrackRoots: []string{"repo-a", "repo-b"},
+	}
+
+	// act
+	mockTracks, err := stubTracker.GatherTracks(cfg)
+	mockExecution := stubTracker.ExecuteTracks(context.Background(), cfg)
+
+	// assert
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "network")
+	require.Contains(t, err.Error(), "repo-a")
+	require.Contains(t, err.Error(), "repo-b")
+	require.Nil(t, mockTracks)
+
+	require.Error(t, mockExecution.Err, "Deployment should be aborted")
+	require.Len(t, mockExecution.Tracks, 0)
+}
@@ -0,0 +1,60 @@
+package tracks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StepWarnings are the warnings reported by a step execution's runner
+type StepWarnings struct {
+	TrackName        string   `json:"track"`
+	StepName         string   `json:"step"`
+	StepID           string   `json:"stepId"`
+	RegionDeployType string   `json:"regionDeployType"`
+	Region           string   `json:"region"`
+	Warnings         []string `json:"warnings"`
+}
+
+func (w StepWarnings) String() string {
+	return fmt.Sprintf("%v/%v/%v/%v (%s)", w.TrackName, w.StepName, w.RegionDeployType, w.Region, strings.Join(w.Warnings, "; "))
+}
+
+// Warnings returns the warnings reported by each deployed step execution in the stage, ordered by track, step, region
+// deploy type and region
+func (s Stage) Warnings() (warnings []StepWarnings) {
+	for _, t := range s.Tracks {
+		for _, exec := range t.Output.Executions {
+			for _, step := range exec.Output.Steps {
+				if len(step.Output.Warnings) == 0 {
+					continue
+				}
+
+				warnings = append(warnings, StepWarnings{
+					TrackName:        t.Name,
+					StepName:         step.Name,
+					StepID:           step.ID,
+					RegionDeployType: exec.RegionDeployType.String(),
+					Region:           exec.Region,
+					Warnings:         step.Output.Warnings,
+				})
+			}
+		}
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		a, b := warnings[i], warnings[j]
+		if a.TrackName != b.TrackName {
+			return a.TrackName < b.TrackName
+		}
+		if a.StepName != b.StepName {
+			return a.StepName < b.StepName
+		}
+		if a.RegionDeployType != b.RegionDeployType {
+			return a.RegionDeployType < b.RegionDeployType
+		}
+		return a.Region < b.Region
+	})
+
+	return
+}
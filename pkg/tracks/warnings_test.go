@@ -0,0 +1,56 @@
+package tracks_test
+
+import (
+	"testing"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/tracks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageWarnings_ShouldAggregateStepWarnings(t *testing.T) {
+	// arrange
+	stepWithWarnings := func(name string, warnings ...string) config.Step {
+		return config.Step{ID: "#core#network#" + name, Name: name, Output: config.StepOutput{Status: config.Success, Warnings: warnings}}
+	}
+
+	stage := tracks.Stage{
+		Tracks: map[string]tracks.Track{
+			"network": {
+				Name: "network",
+				Output: tracks.Output{
+					Executions: []tracks.RegionExecution{
+						{
+							Region:           "us-east-2",
+							RegionDeployType: config.RegionalRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc": stepWithWarnings("vpc", "Argument is deprecated"),
+								},
+							},
+						},
+						{
+							Region:           "us-east-1",
+							RegionDeployType: config.PrimaryRegionDeployType,
+							Output: tracks.ExecutionOutput{
+								Steps: map[string]config.Step{
+									"vpc":   stepWithWarnings("vpc", "Argument is deprecated", "Version constraints inside provider configuration blocks are deprecated"),
+									"quiet": stepWithWarnings("quiet"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	warnings := stage.Warnings()
+
+	// assert
+	require.Equal(t, []tracks.StepWarnings{
+		{TrackName: "network", StepName: "vpc", StepID: "#core#network#vpc", RegionDeployType: "primary", Region: "us-east-1", Warnings: []string{"Argument is deprecated", "Version constraints inside provider configuration blocks are deprecated"}},
+		{TrackName: "network", StepName: "vpc", StepID: "#core#network#vpc", RegionDeployType: "regional", Region: "us-east-2", Warnings: []string{"Argument is deprecated"}},
+	}, warnings)
+}
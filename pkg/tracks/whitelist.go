@@ -0,0 +1,43 @@
+package tracks
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+)
+
+// isWhitelisted reports whether the step id matches an entry of the step whitelist. Entries match step ids exactly,
+// ignoring case, as glob patterns, e.g. #project#track#*, or as regular expressions when prefixed with regex:
+func isWhitelisted(whitelist []string, stepID string) bool {
+	// exact step ids are the common case
+	if contains(whitelist, stepID) {
+		return true
+	}
+
+	for _, entry := range whitelist {
+		if pattern := strings.TrimPrefix(entry, config.StepWhitelistRegexPrefix); pattern != entry {
+			if matched, err := regexp.MatchString(pattern, stepID); err == nil && matched {
+				return true
+			}
+		} else if config.IsStepIDPattern(entry) {
+			if matched, err := path.Match(strings.ToLower(entry), strings.ToLower(stepID)); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesWhitelistedTrack reports whether the track name parsed from a step whitelist entry, which may be a glob
+// pattern, matches the name of a track
+func matchesWhitelistedTrack(whitelisted string, name string) bool {
+	if config.IsStepIDPattern(whitelisted) {
+		matched, err := path.Match(strings.ToLower(whitelisted), strings.ToLower(name))
+		return err == nil && matched
+	}
+
+	return strings.EqualFold(whitelisted, name)
+}
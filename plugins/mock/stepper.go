@@ -0,0 +1,93 @@
+package plugins_mock
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/spf13/afero"
+)
+
+// Fixtures are the canned output variables of each step simulated by the MockStepper, e.g.
+// {"network": {"vpc": {"vpc_id": "vpc-123"}}}. K=track name, K=step name, V=output variables
+type Fixtures map[string]map[string]map[string]interface{}
+
+// ReadFixtures reads the fixtures in file, an empty file path has no fixtures
+func ReadFixtures(fs afero.Fs, file string) (Fixtures, error) {
+	fixtures := Fixtures{}
+	if file == "" {
+		return fixtures, nil
+	}
+
+	b, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &fixtures); err != nil {
+		return nil, fmt.Errorf("unable to read mock fixtures %s: %w", file, err)
+	}
+
+	return fixtures, nil
+}
+
+// MockStepper simulates steps without calling a runner or cloud provider, returning the canned output variables in
+// FixturesFile. It allows the track and region orchestration to be exercised offline
+type MockStepper struct {
+	FixturesFile string
+}
+
+func (stepper MockStepper) PreExecute(exec config.StepExecution) (config.StepExecution, error) {
+	return exec, nil
+}
+
+// ExecuteStep simulates deploying a step, returning its fixture outputs
+func (stepper MockStepper) ExecuteStep(exec config.StepExecution) (output config.StepOutput) {
+	output = config.StepOutput{
+		Status:           config.Success,
+		RegionDeployType: exec.RegionDeployType,
+		Region:           exec.Region,
+		StepName:         exec.StepName,
+		OutputVariables:  map[string]interface{}{},
+	}
+
+	fixtures, err := ReadFixtures(exec.Fs, stepper.FixturesFile)
+	if err != nil {
+		exec.Logger.WithError(err).Error("Unable to read mock fixtures")
+		output.Status = config.Fail
+		output.Err = err
+		return
+	}
+
+	outputs, ok := fixtures[exec.TrackName][exec.StepName]
+	if !ok {
+		exec.Logger.Warn("Mock fixtures have no outputs for the step")
+	}
+
+	for k, v := range outputs {
+		output.OutputVariables[k] = v
+	}
+
+	exec.Logger.Info("Mock step deployed")
+
+	return
+}
+
+// ExecuteStepTests skips a step's tests, as there are no deployed resources to test
+func (stepper MockStepper) ExecuteStepTests(exec config.StepExecution) config.StepTestOutput {
+	exec.Logger.Info("Skipping tests of mock step")
+
+	return config.StepTestOutput{StepName: exec.StepName}
+}
+
+// ExecuteStepDestroy simulates destroying a step
+func (stepper MockStepper) ExecuteStepDestroy(exec config.StepExecution) config.StepOutput {
+	exec.Logger.Info("Mock step destroyed")
+
+	return config.StepOutput{
+		Status:           config.Success,
+		RegionDeployType: exec.RegionDeployType,
+		Region:           exec.Region,
+		StepName:         exec.StepName,
+	}
+}
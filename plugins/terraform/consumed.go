@@ -0,0 +1,64 @@
+package plugins_terraform
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/spf13/afero"
+)
+
+// variableDeclarationPattern matches the name of a terraform input variable declaration, e.g. variable "name" {
+var variableDeclarationPattern = regexp.MustCompile(`(?m)^\s*variable\s+"?([\w-]+)"?\s*\{`)
+
+// ConsumedVariables returns the previous step output variables the step references. Terraform only reads the
+// variables a step declares, so an output is consumed when the step declares it as {step_name}-{output_variable_name}.
+// The result is keyed by the previous step as in exec.DefaultStepOutputVariables
+func ConsumedVariables(exec config.StepExecution) (map[string][]string, error) {
+	declared, err := declaredVariables(exec.Fs, exec.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	consumed := map[string][]string{}
+	for step, outputVars := range exec.DefaultStepOutputVariables {
+		for name := range outputVars {
+			if declared[fmt.Sprintf("%s-%s", step, name)] {
+				consumed[step] = append(consumed[step], name)
+			}
+		}
+
+		sort.Strings(consumed[step])
+	}
+
+	return consumed, nil
+}
+
+// declaredVariables returns the names of the input variables declared in the terraform files of dir
+func declaredVariables(fs afero.Fs, dir string) (map[string]bool, error) {
+	files, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := map[string]bool{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".tf") {
+			continue
+		}
+
+		b, err := afero.ReadFile(fs, filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range variableDeclarationPattern.FindAllStringSubmatch(string(b), -1) {
+			declared[match[1]] = true
+		}
+	}
+
+	return declared, nil
+}
@@ -0,0 +1,31 @@
+package plugins_terraform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnexpectedDestroyChanges is returned when a step's destroy plan would create or update resources (e.g. due to drift)
+// rather than only deleting them
+type UnexpectedDestroyChanges struct {
+	Resources []string // The addresses and actions of the resources the destroy plan creates or updates
+}
+
+func (err UnexpectedDestroyChanges) Error() string {
+	return fmt.Sprintf("destroy plan would create or update resources: %s", strings.Join(err.Resources, ", "))
+}
+
+// unexpectedDestroyChanges returns the resources, with their actions, a destroy plan changes other than by deleting them.
+// Unchanged resources and data source reads are expected
+func unexpectedDestroyChanges(p plan) (resources []string) {
+	for _, c := range p.ResourceChanges {
+		for _, action := range c.Change.Actions {
+			if action != "delete" && action != "no-op" && action != "read" {
+				resources = append(resources, fmt.Sprintf("%s %s", c.Address, c.Change.Actions))
+				break
+			}
+		}
+	}
+
+	return
+}
@@ -34,14 +34,17 @@ func RunTerraformCommand(streamOutput bool, additionalOptions *Options, addition
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
 
 	cmd := shell.Command{
-		Command:           options.TerraformBinary,
-		Args:              args,
-		WorkingDir:        options.TerraformDir,
-		Env:               options.EnvVars,
-		OutputMaxLineSize: options.OutputMaxLineSize,
-		NonInteractive:    true,
-		SensitiveArgs:     false,
-		Logger:            options.Logger,
+		Command:             options.TerraformBinary,
+		Args:                args,
+		WorkingDir:          options.TerraformDir,
+		Env:                 options.EnvVars,
+		EnvAllowlist:        options.EnvAllowlist,
+		OutputMaxLineSize:   options.OutputMaxLineSize,
+		NonInteractive:      true,
+		SensitiveArgs:       false,
+		Logger:              options.Logger,
+		Context:             options.Context,
+		ShutdownGracePeriod: options.ShutdownGracePeriod,
 	}
 
 	options.Logger.Debugf("Executing Command with following Env Vars set: %s", KeysStringString(cmd.Env))
@@ -66,14 +69,17 @@ func GetExitCodeForTerraformCommand(additionalOptions *Options, additionalArgs .
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
 
 	cmd := shell.Command{
-		Command:           options.TerraformBinary,
-		Args:              args,
-		WorkingDir:        options.TerraformDir,
-		Env:               options.EnvVars,
-		OutputMaxLineSize: options.OutputMaxLineSize,
-		Logger:            options.Logger,
-		NonInteractive:    true,
-		SensitiveArgs:     true,
+		Command:             options.TerraformBinary,
+		Args:                args,
+		WorkingDir:          options.TerraformDir,
+		Env:                 options.EnvVars,
+		EnvAllowlist:        options.EnvAllowlist,
+		OutputMaxLineSize:   options.OutputMaxLineSize,
+		Logger:              options.Logger,
+		NonInteractive:      true,
+		SensitiveArgs:       true,
+		Context:             options.Context,
+		ShutdownGracePeriod: options.ShutdownGracePeriod,
 	}
 
 	_, err := shell.RunShellCommandAndGetOutput(cmd)
@@ -19,6 +19,16 @@ func FormatArgs(options *Options, args ...string) []string {
 	return terraformArgs
 }
 
+// FormatParallelismArgs formats the configured parallelism as a command-line arg for terraform plan and apply (e.g.
+// -parallelism=5), returning no args when the parallelism is not limited
+func FormatParallelismArgs(options *Options) []string {
+	if options.Parallelism <= 0 {
+		return []string{}
+	}
+
+	return []string{fmt.Sprintf("-parallelism=%d", options.Parallelism)}
+}
+
 // FormatTerraformVarsAsArgs formats the given variables as command-line args for Terraform (e.g. of the format
 // -var key=value).
 func FormatTerraformVarsAsArgs(vars map[string]interface{}) []string {
@@ -3,6 +3,7 @@ package terraform
 // This code follows: https://github.com/gruntwork-io/terratest/blob/master/modules/terraform/options.go
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
 	"time"
 )
@@ -15,6 +16,7 @@ type Options struct {
 	VarFiles                 []string               // The var file paths to pass to Terraform commands using -var-file option.
 	Targets                  []string               // The target resources to pass to the terraform command with -target
 	EnvVars                  map[string]string      // Environment variables to set when running Terraform
+	EnvAllowlist             []string               // When set, only these inherited environment variables (names, or prefixes ending in *) are passed to Terraform
 	BackendConfig            map[string]interface{} // The vars to pass to the terraform init command for extra configuration for the backend
 	RetryableTerraformErrors map[string]string      // If Terraform apply fails with one of these (transient) errors, retry. The keys are a regexp to match against the error and the message is what to display to a user if that error is matched.
 	MaxRetries               int                    // Maximum number of times to retry errors matching RetryableTerraformErrors
@@ -25,4 +27,7 @@ type Options struct {
 	OutputMaxLineSize        int                    // The max size of one line in stdout and stderr (in bytes)
 	Logger                   *logrus.Entry
 	PluginCacheDir           string
+	Context                  context.Context // If set, cancelling the context interrupts the running terraform command
+	ShutdownGracePeriod      time.Duration   // The time terraform has to exit after being interrupted before it is killed
+	Parallelism              int             // When greater than zero, limits the concurrent operations of terraform plan and apply with -parallelism
 }
@@ -10,5 +10,7 @@ func Plan(options *Options, tfplan string, destroy bool) (string, error) {
 		args = append(args, "-destroy")
 	}
 
+	args = append(args, FormatParallelismArgs(options)...)
+
 	return RunTerraformCommand(true, options, FormatArgs(options, args...)...)
 }
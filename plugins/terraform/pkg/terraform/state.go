@@ -0,0 +1,68 @@
+package terraform
+
+import (
+	"strings"
+)
+
+// StateList runs terraform state list and returns the output and any error
+func StateList(options *Options) (string, error) {
+	args := []string{"state", "list"}
+
+	return RunTerraformCommand(false, options, FormatArgs(options, args...)...)
+}
+
+// ParseStateList parses the output of terraform state list into the addresses of the managed resources,
+// excluding data sources which are read rather than managed by the state
+func ParseStateList(out string) []string {
+	resources := []string{}
+
+	for _, line := range strings.Split(out, "\n") {
+		address := strings.TrimSpace(line)
+		if address == "" {
+			continue
+		}
+
+		// data sources may be nested within modules, e.g. module.network.data.aws_vpc.main
+		if strings.HasPrefix(resourceAddress(address), "data.") {
+			continue
+		}
+
+		resources = append(resources, address)
+	}
+
+	return resources
+}
+
+// resourceAddress strips the module path from an address, e.g. module.data["a.b"].module.vpc.aws_vpc.main is
+// aws_vpc.main. Module names and instance keys may themselves look like data sources, so only the remainder is inspected.
+func resourceAddress(address string) string {
+	for strings.HasPrefix(address, "module.") {
+		rest := address[len("module."):]
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			return address
+		}
+
+		// skip the module's instance key, e.g. [0] or ["us-east-1"], whose quoted strings may contain dots or brackets
+		if rest[end] == '[' {
+			quoted := false
+			for end++; end < len(rest); end++ {
+				if rest[end] == '"' && rest[end-1] != '\\' {
+					quoted = !quoted
+				} else if rest[end] == ']' && !quoted {
+					end++
+					break
+				}
+			}
+
+			if end >= len(rest) || rest[end] != '.' {
+				return address
+			}
+		}
+
+		address = rest[end+1:]
+	}
+
+	return address
+}
@@ -24,6 +24,7 @@ type Terraformer interface {
 	Init(options *Options) (out string, err error)
 	Apply(options *Options, tfplan string) (string, error)
 	WorkspaceSelect(options *Options, workspace string) (string, error)
+	StateList(options *Options) (string, error)
 }
 
 type Terraform struct{}
@@ -63,3 +64,7 @@ func (t Terraform) OutputToString(value interface{}) string {
 func (t Terraform) WorkspaceSelect(options *Options, workspace string) (string, error) {
 	return WorkspaceSelect(options, workspace)
 }
+
+func (t Terraform) StateList(options *Options) (string, error) {
+	return StateList(options)
+}
@@ -95,3 +95,99 @@ func TestOutputToString(t *testing.T) {
 		assert.Equal(t, tc.ExpectedString, result)
 	}
 }
+
+func TestParseStateList(t *testing.T) {
+	out := `aws_iam_role.read_only
+aws_s3_bucket.logs
+data.aws_caller_identity.current
+module.network.aws_vpc.main
+module.network.data.aws_availability_zones.available
+module.data.aws_s3_bucket.archive
+module.buckets["logs.data.archive"].aws_s3_bucket.this
+module.regions["us-east-1"].module.vpc[0].data.aws_region.current
+module.regions["us-east-1"].module.vpc[0].aws_vpc.main
+
+`
+
+	resources := ParseStateList(out)
+
+	assert.Equal(t, []string{
+		"aws_iam_role.read_only",
+		"aws_s3_bucket.logs",
+		"module.network.aws_vpc.main",
+		"module.data.aws_s3_bucket.archive",
+		`module.buckets["logs.data.archive"].aws_s3_bucket.this`,
+		`module.regions["us-east-1"].module.vpc[0].aws_vpc.main`,
+	}, resources, "Managed resources should be listed without data sources, even within modules named like data sources")
+	assert.Empty(t, ParseStateList(""), "Empty state should have no resources")
+}
+
+func TestParseWarnings(t *testing.T) {
+	out := `aws_s3_bucket.logs: Refreshing state... [id=logs]
+
+Warning: Deprecated Attribute
+
+  on main.tf line 12, in resource "aws_s3_bucket" "logs":
+  12:   acl = "private"
+
+╷
+│ Warning: Argument is deprecated
+│
+│   with aws_s3_bucket.logs,
+│   on main.tf line 4, in resource "aws_s3_bucket" "logs":
+│
+│ Use the aws_s3_bucket_acl resource instead
+╵
+╷
+│ Warning: Argument is deprecated
+╵
+╷
+│ Error: creating IAM Role: AccessDenied
+╵
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+`
+
+	warnings := ParseWarnings(out)
+
+	assert.Equal(t, []string{"Deprecated Attribute", "Argument is deprecated"}, warnings, "Warnings should be parsed once without errors or other output")
+	assert.Empty(t, ParseWarnings("Apply complete! Resources: 0 added, 0 changed, 0 destroyed."), "Output without warnings should have none")
+}
+
+func TestPlanAndApply_ShouldLimitParallelismWhenConfigured(t *testing.T) {
+	tests := map[string]struct {
+		parallelism   int
+		expectedPlan  string
+		expectedApply string
+	}{
+		"ShouldPassConfiguredParallelism": {
+			parallelism:   3,
+			expectedPlan:  "plan -out=stub.tfplan -input=false -no-color -parallelism=3",
+			expectedApply: "apply -input=false -no-color -auto-approve=true -parallelism=3 stub.tfplan",
+		},
+		"ShouldNotPassParallelismByDefault": {
+			expectedPlan:  "plan -out=stub.tfplan -input=false -no-color",
+			expectedApply: "apply -input=false -no-color -auto-approve=true stub.tfplan",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// echo the arguments terraform would be run with
+			options := tfOptions
+			options.TerraformBinary = "echo"
+			options.Parallelism = test.parallelism
+
+			// act
+			plan, err := Plan(&options, "stub.tfplan", false)
+			assert.NoError(t, err)
+
+			apply, err := Apply(&options, "stub.tfplan")
+			assert.NoError(t, err)
+
+			// assert
+			assert.Equal(t, test.expectedPlan, plan)
+			assert.Equal(t, test.expectedApply, apply)
+		})
+	}
+}
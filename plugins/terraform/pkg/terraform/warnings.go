@@ -0,0 +1,31 @@
+package terraform
+
+import (
+	"strings"
+)
+
+// ParseWarnings parses the summaries of the warnings within terraform's output, e.g. deprecations, omitting duplicates.
+// Both the plain and boxed (terraform >= 0.15) diagnostic formats are supported
+func ParseWarnings(out string) []string {
+	var warnings []string
+	seen := map[string]bool{}
+
+	for _, line := range strings.Split(out, "\n") {
+		// boxed diagnostics prefix each line with │
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "│"))
+
+		if !strings.HasPrefix(line, "Warning: ") {
+			continue
+		}
+
+		warning := strings.TrimSpace(strings.TrimPrefix(line, "Warning: "))
+		if warning == "" || seen[warning] {
+			continue
+		}
+
+		seen[warning] = true
+		warnings = append(warnings, warning)
+	}
+
+	return warnings
+}
@@ -0,0 +1,25 @@
+package plugins_terraform
+
+import (
+	"github.com/optum/runiac/pkg/config"
+)
+
+// summarizePlan counts the resources a plan creates, updates and deletes. Replaced resources are both created and
+// deleted, unchanged resources and data source reads are not counted
+func summarizePlan(p plan) config.PlanSummary {
+	summary := config.PlanSummary{}
+	for _, c := range p.ResourceChanges {
+		for _, action := range c.Change.Actions {
+			switch action {
+			case "create":
+				summary.Add++
+			case "update":
+				summary.Change++
+			case "delete":
+				summary.Destroy++
+			}
+		}
+	}
+
+	return summary
+}
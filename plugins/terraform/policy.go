@@ -0,0 +1,38 @@
+package plugins_terraform
+
+import (
+	"fmt"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/pkg/shell"
+)
+
+// PolicyCheckFailed is returned when a step's plan does not pass the configured policy command
+type PolicyCheckFailed struct {
+	Command string
+	Output  string
+}
+
+func (err PolicyCheckFailed) Error() string {
+	return fmt.Sprintf("plan failed policy check '%s'", err.Command)
+}
+
+var runPolicyCommand = defaultRunPolicyCommand
+
+// defaultRunPolicyCommand runs the configured policy command against the plan json file, e.g. `conftest test`.
+// The plan file is appended as the final argument and exposed as RUNIAC_PLAN_JSON.
+func defaultRunPolicyCommand(exec config.StepExecution, planFile string) (string, error) {
+	cmd := shell.Command{
+		Command:             "sh",
+		Args:                []string{"-c", fmt.Sprintf(`%s "$1"`, exec.PolicyCommand), "runiac-policy", planFile},
+		Env:                 map[string]string{"RUNIAC_PLAN_JSON": planFile},
+		EnvAllowlist:        exec.EnvAllowlist,
+		WorkingDir:          exec.Dir,
+		Logger:              exec.Logger,
+		NonInteractive:      true,
+		Context:             exec.Context,
+		ShutdownGracePeriod: exec.ShutdownGracePeriod,
+	}
+
+	return shell.RunShellCommandAndGetOutput(cmd)
+}
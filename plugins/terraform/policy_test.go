@@ -0,0 +1,474 @@
+package plugins_terraform
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTerraformer records the terraform commands executed without running terraform
+type stubTerraformer struct {
+	terraform.Terraform
+	applied *bool
+}
+
+func (t stubTerraformer) Init(options *terraform.Options) (string, error) { return "", nil }
+
+func (t stubTerraformer) WorkspaceSelect(options *terraform.Options, workspace string) (string, error) {
+	return "", nil
+}
+
+func (t stubTerraformer) Plan(options *terraform.Options, tfplan string, destroy bool) (string, error) {
+	return "", nil
+}
+
+func (t stubTerraformer) Show(options *terraform.Options, tfplan string) (string, error) {
+	return `{"resource_changes":[]}`, nil
+}
+
+func (t stubTerraformer) Apply(options *terraform.Options, tfplan string) (string, error) {
+	*t.applied = true
+	return "", nil
+}
+
+func (t stubTerraformer) OutputAll(options *terraform.Options) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (t stubTerraformer) StateList(options *terraform.Options) (string, error) {
+	return "aws_s3_bucket.logs\ndata.aws_caller_identity.current\nmodule.network.aws_vpc.main\n", nil
+}
+
+func stubPolicyExecution(warnOnly bool) config.StepExecution {
+	return config.StepExecution{
+		Fs:                 afero.NewMemMapFs(),
+		Logger:             logger,
+		Dir:                "/tracks/step1_deploy",
+		StepName:           "step1_deploy",
+		Region:             "us-east-1",
+		RegionDeployType:   config.PrimaryRegionDeployType,
+		PolicyCommand:      "conftest test",
+		PolicyWarnOnly:     warnOnly,
+		OptionalStepParams: map[string]string{},
+	}
+}
+
+func TestExecuteTerraformInDir_ShouldNotApplyWhenPolicyCheckFails(t *testing.T) {
+	applied := false
+	terraformer = stubTerraformer{applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	var receivedPlanFile string
+	runPolicyCommand = func(exec config.StepExecution, planFile string) (string, error) {
+		receivedPlanFile = planFile
+		return "FAIL - deny public buckets", errors.New("exit status 1")
+	}
+	defer func() { runPolicyCommand = defaultRunPolicyCommand }()
+
+	exec := stubPolicyExecution(false)
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.False(t, applied, "Apply should not run when the policy check fails")
+	require.Equal(t, config.Fail, output.Status, "Step should fail when the policy check fails")
+	require.IsType(t, PolicyCheckFailed{}, output.Err, "Step error should be a policy check failure")
+	require.Equal(t, "FAIL - deny public buckets", output.PolicyOutput, "Policy output should be recorded on the step")
+
+	plan, err := afero.ReadFile(exec.Fs, receivedPlanFile)
+	require.NoError(t, err, "Plan json should be written for the policy command")
+	require.Equal(t, `{"resource_changes":[]}`, string(plan))
+}
+
+func TestExecuteTerraformInDir_ShouldRunPolicyCommandAgainstPlanOfRelativeStepDir(t *testing.T) {
+	applied := false
+	terraformer = stubTerraformer{applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	// steps are gathered relative to the working directory, e.g. tracks/network/step1_vpc
+	dir, err := ioutil.TempDir(".", "step1_deploy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.False(t, filepath.IsAbs(dir))
+
+	exec := stubPolicyExecution(false)
+	exec.Fs = afero.NewOsFs()
+	exec.Dir = dir
+	exec.PolicyCommand = `grep -q resource_changes "$RUNIAC_PLAN_JSON" && grep -q resource_changes`
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.NoError(t, output.Err, "The policy command should find the plan from within the step directory: %s", output.PolicyOutput)
+	require.Equal(t, config.Success, output.Status)
+	require.True(t, applied, "Apply should run when the policy check passes")
+}
+
+func TestExecuteTerraformInDir_ShouldApplyWhenPolicyCheckFailsInWarnOnlyMode(t *testing.T) {
+	applied := false
+	terraformer = stubTerraformer{applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	runPolicyCommand = func(exec config.StepExecution, planFile string) (string, error) {
+		return "WARN - deny public buckets", errors.New("exit status 1")
+	}
+	defer func() { runPolicyCommand = defaultRunPolicyCommand }()
+
+	// act
+	output := executeTerraformInDir(stubPolicyExecution(true), false)
+
+	// assert
+	require.True(t, applied, "Apply should run when policy failures are warn only")
+	require.Equal(t, config.Success, output.Status)
+	require.NoError(t, output.Err)
+	require.Equal(t, "WARN - deny public buckets", output.PolicyOutput, "Policy output should be recorded on the step")
+}
+
+func TestExecuteTerraformInDir_ShouldNotApplyWhenTestingAgainstPlan(t *testing.T) {
+	applied := false
+	terraformer = stubTerraformer{applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.TestAgainstPlan = true
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.False(t, applied, "Apply should not run when testing against the plan")
+	require.Equal(t, config.Success, output.Status)
+
+	plan, err := afero.ReadFile(exec.Fs, planJSONFile(exec))
+	require.NoError(t, err, "Plan json should be written for plan assertion tests")
+	require.Equal(t, `{"resource_changes":[]}`, string(plan))
+}
+
+func TestExecuteTerraformInDir_ShouldRecordInventoryWhenEmittingInventory(t *testing.T) {
+	applied := false
+	terraformer = stubTerraformer{applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.EmitInventory = true
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.Equal(t, config.Success, output.Status)
+	require.Equal(t, []string{"aws_s3_bucket.logs", "module.network.aws_vpc.main"}, output.Resources, "Managed resources should be recorded on the step output")
+
+	exec.DryRun = true
+	applied = false
+	output = executeTerraformInDir(exec, false)
+	require.False(t, applied)
+	require.Equal(t, []string{"aws_s3_bucket.logs", "module.network.aws_vpc.main"}, output.Resources, "Deployed resources should be recorded when nothing is applied")
+
+	exec.EmitInventory = false
+	require.Empty(t, executeTerraformInDir(exec, false).Resources, "Resources should only be listed when emitting an inventory")
+}
+
+// planRecordingTerraformer records the plans terraform was asked to create and apply
+type planRecordingTerraformer struct {
+	stubTerraformer
+	planned *[]string
+	applied *[]string
+}
+
+func (t planRecordingTerraformer) Plan(options *terraform.Options, tfplan string, destroy bool) (string, error) {
+	*t.planned = append(*t.planned, tfplan)
+	return "", nil
+}
+
+func (t planRecordingTerraformer) Apply(options *terraform.Options, tfplan string) (string, error) {
+	*t.applied = append(*t.applied, tfplan)
+	return "", nil
+}
+
+func TestExecuteTerraformInDir_ShouldApplyBundledPlan(t *testing.T) {
+	var planned, applied []string
+	terraformer = planRecordingTerraformer{planned: &planned, applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.BundledPlanFile = "/bundle/plans/track/step1_deploy/primary-us-east-1.tfplan"
+	_ = afero.WriteFile(exec.Fs, exec.BundledPlanFile, []byte("plan"), 0644)
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.Equal(t, config.Success, output.Status)
+	require.Empty(t, planned, "The step should not be planned again when applying a bundled plan")
+	require.Equal(t, []string{exec.BundledPlanFile}, applied, "The bundled plan should be applied")
+	require.Equal(t, exec.BundledPlanFile, output.PlanFile)
+}
+
+func TestExecuteTerraformInDir_ShouldFailWhenBundleHasNoPlanForStep(t *testing.T) {
+	var planned, applied []string
+	terraformer = planRecordingTerraformer{planned: &planned, applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.BundledPlanFile = "/bundle/plans/track/step1_deploy/primary-us-east-1.tfplan"
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.Equal(t, config.Fail, output.Status)
+	require.Error(t, output.Err)
+	require.Empty(t, planned, "The step should not be planned when applying a plan bundle")
+	require.Empty(t, applied, "Nothing should be applied without a bundled plan")
+}
+
+func TestExecuteTerraformInDir_ShouldRecordPlanFile(t *testing.T) {
+	var planned, applied []string
+	terraformer = planRecordingTerraformer{planned: &planned, applied: &applied}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.Equal(t, config.Success, output.Status)
+	require.Equal(t, []string{planFile(exec)}, planned)
+	require.Equal(t, filepath.Join(exec.Dir, planFile(exec)), output.PlanFile, "The plan should be recorded for plan bundles")
+}
+
+// deletingTerraformer plans deleting and replacing resources
+type deletingTerraformer struct {
+	stubTerraformer
+}
+
+func (t deletingTerraformer) Show(options *terraform.Options, tfplan string) (string, error) {
+	return `{"resource_changes":[
+		{"address":"aws_s3_bucket.logs","change":{"actions":["delete"]}},
+		{"address":"aws_iam_role.app","change":{"actions":["delete","create"]}},
+		{"address":"aws_vpc.main","change":{"actions":["no-op"]}}
+	]}`, nil
+}
+
+func TestExecuteTerraformInDir_ShouldRecordPlannedDeletionsWithoutApplyingDuringDryRun(t *testing.T) {
+	applied := false
+	terraformer = deletingTerraformer{stubTerraformer{applied: &applied}}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.DryRun = true
+	exec.AllowDestroyPlanChanges = true // the replaced role would otherwise fail the destroy plan guard
+
+	// act
+	output := executeTerraformInDir(exec, true)
+
+	// assert
+	require.False(t, applied, "A dry run destroy should not be applied")
+	require.Equal(t, config.Success, output.Status)
+	require.Equal(t, []string{"aws_s3_bucket.logs", "aws_iam_role.app"}, output.PlannedDeletions)
+}
+
+func TestExecuteTerraformInDir_ShouldSummarizePlanDuringDryRun(t *testing.T) {
+	tests := map[string]struct {
+		terraformer     func(applied *bool) terraform.Terraformer
+		expectedSummary config.PlanSummary
+	}{
+		"ShouldCountReplacementsAsCreatedAndDestroyed": {
+			terraformer: func(applied *bool) terraform.Terraformer {
+				return deletingTerraformer{stubTerraformer{applied: applied}}
+			},
+			expectedSummary: config.PlanSummary{Add: 1, Destroy: 2},
+		},
+		"ShouldSummarizePlanWithoutChanges": {
+			terraformer:     func(applied *bool) terraform.Terraformer { return stubTerraformer{applied: applied} },
+			expectedSummary: config.PlanSummary{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			applied := false
+			terraformer = test.terraformer(&applied)
+			defer func() { terraformer = terraform.Terraform{} }()
+
+			exec := stubPolicyExecution(false)
+			exec.PolicyCommand = ""
+			exec.DryRun = true
+
+			// act
+			output := executeTerraformInDir(exec, false)
+
+			// assert
+			require.False(t, applied)
+			require.Equal(t, config.Success, output.Status)
+			require.NotNil(t, output.PlanSummary)
+			require.Equal(t, test.expectedSummary, *output.PlanSummary)
+		})
+	}
+}
+
+// warningTerraformer reports deprecation warnings during plan and apply
+type warningTerraformer struct {
+	stubTerraformer
+}
+
+func (t warningTerraformer) Plan(options *terraform.Options, tfplan string, destroy bool) (string, error) {
+	return "Warning: Argument is deprecated\n\nPlan: 1 to add, 0 to change, 0 to destroy.\n", nil
+}
+
+func (t warningTerraformer) Apply(options *terraform.Options, tfplan string) (string, error) {
+	*t.applied = true
+	return "Warning: Argument is deprecated\n\nWarning: Provider version constraint is deprecated\n\nApply complete!\n", nil
+}
+
+func TestExecuteTerraformInDir_ShouldRecordWarningsWithoutFailing(t *testing.T) {
+	applied := false
+	terraformer = warningTerraformer{stubTerraformer{applied: &applied}}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.True(t, applied)
+	require.Equal(t, config.Success, output.Status)
+	require.NoError(t, output.Err)
+	require.Equal(t, []string{"Argument is deprecated", "Provider version constraint is deprecated"}, output.Warnings)
+}
+
+// failingTerraformer fails plan or apply the way terraform does, with the explanation in its output
+type failingTerraformer struct {
+	stubTerraformer
+	planOutput  string
+	applyOutput string
+}
+
+func (t failingTerraformer) Plan(options *terraform.Options, tfplan string, destroy bool) (string, error) {
+	if t.planOutput != "" {
+		return t.planOutput, errors.New("exit status 1")
+	}
+
+	return "", nil
+}
+
+func (t failingTerraformer) Apply(options *terraform.Options, tfplan string) (string, error) {
+	*t.applied = true
+	return t.applyOutput, errors.New("exit status 1")
+}
+
+func TestExecuteTerraformInDir_ShouldRecordTerraformOutputOfFailedCommand(t *testing.T) {
+	tests := map[string]struct {
+		planOutput  string
+		applyOutput string
+		expected    string
+	}{
+		"Plan": {
+			planOutput: "Error: Reference to undeclared input variable\n",
+			expected:   "Error: Reference to undeclared input variable\n",
+		},
+		"Apply": {
+			applyOutput: "Error: error creating S3 bucket: RequestError: send request failed: connection reset by peer\n",
+			expected:    "Error: error creating S3 bucket: RequestError: send request failed: connection reset by peer\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			applied := false
+			terraformer = failingTerraformer{stubTerraformer: stubTerraformer{applied: &applied}, planOutput: test.planOutput, applyOutput: test.applyOutput}
+			defer func() { terraformer = terraform.Terraform{} }()
+
+			planAndApplyRetrySleep = 0
+			defer func() { planAndApplyRetrySleep = 10 * time.Second }()
+
+			exec := stubPolicyExecution(false)
+			exec.PolicyCommand = ""
+
+			// act
+			output := executeTerraformInDir(exec, false)
+
+			// assert
+			require.Equal(t, config.Fail, output.Status)
+			require.EqualError(t, output.Err, "exit status 1")
+			require.Equal(t, test.expected, output.StreamOutput, "The terraform output should explain the failure for classifying it")
+		})
+	}
+}
+
+// driftingTerraformer plans recreating a resource during a destroy, e.g. due to drift
+type driftingTerraformer struct {
+	stubTerraformer
+}
+
+func (t driftingTerraformer) Show(options *terraform.Options, tfplan string) (string, error) {
+	return `{"resource_changes":[
+		{"address":"aws_s3_bucket.logs","change":{"actions":["delete"]}},
+		{"address":"data.aws_caller_identity.current","change":{"actions":["read"]}},
+		{"address":"aws_iam_role.app","change":{"actions":["create"]}}
+	]}`, nil
+}
+
+func TestExecuteTerraformInDir_ShouldGuardDestroyPlansAgainstUnexpectedChanges(t *testing.T) {
+	tests := map[string]struct {
+		allowChanges    bool
+		expectedApplied bool
+		expectedStatus  config.DeployResult
+	}{
+		"ShouldFailWithoutApplyingUnexpectedCreate": {
+			allowChanges:    false,
+			expectedApplied: false,
+			expectedStatus:  config.Fail,
+		},
+		"ShouldApplyWhenOverridden": {
+			allowChanges:    true,
+			expectedApplied: true,
+			expectedStatus:  config.Success,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			applied := false
+			terraformer = driftingTerraformer{stubTerraformer{applied: &applied}}
+			defer func() { terraformer = terraform.Terraform{} }()
+
+			exec := stubPolicyExecution(false)
+			exec.PolicyCommand = ""
+			exec.AllowDestroyPlanChanges = test.allowChanges
+
+			// act
+			output := executeTerraformInDir(exec, true)
+
+			// assert
+			require.Equal(t, test.expectedApplied, applied)
+			require.Equal(t, test.expectedStatus, output.Status)
+
+			if test.allowChanges {
+				require.NoError(t, output.Err)
+			} else {
+				require.Equal(t, UnexpectedDestroyChanges{Resources: []string{"aws_iam_role.app [create]"}}, output.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package plugins_terraform
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// rateLimitErrorPatterns match provider API throttling messages surfaced by terraform
+var rateLimitErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)throttl(ed|ing)`),
+	regexp.MustCompile(`(?i)rate exceeded`),
+	regexp.MustCompile(`(?i)rate ?limit ?(ed|exceeded)`),
+	regexp.MustCompile(`(?i)request ?limit ?exceeded`),
+	regexp.MustCompile(`(?i)too many requests`),
+	regexp.MustCompile(`(?i)slow ?down`),
+	regexp.MustCompile(`\b429\b`),
+}
+
+// errorLinePattern matches the lines of terraform's output reporting an error, the remaining lines may describe the
+// planned resources, e.g. a "slow-down" queue or port 429, and must not be mistaken for throttling
+var errorLinePattern = regexp.MustCompile(`(?i)\berror\b`)
+
+// planAndApplyRetrySleep is the time slept between terraform plan and apply attempts
+var planAndApplyRetrySleep = 10 * time.Second
+
+// rateLimitBackoff is the additional time slept before retrying an attempt that was rate limited
+var rateLimitBackoff = 30 * time.Second
+
+// IsRateLimitError determines whether a failed terraform command was caused by provider API throttling, reported by its
+// error or the error lines of its output
+func IsRateLimitError(resp string, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	lines := []string{err.Error()}
+	for _, line := range strings.Split(resp, "\n") {
+		if errorLinePattern.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, line := range lines {
+		for _, pattern := range rateLimitErrorPatterns {
+			if pattern.MatchString(line) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+package plugins_terraform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/optum/runiac/pkg/config"
+	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// throttledTerraformer fails apply with a throttling error for the first throttledApplies attempts
+type throttledTerraformer struct {
+	stubTerraformer
+	applies          *int
+	throttledApplies int
+	applyErr         string
+}
+
+func (t throttledTerraformer) Apply(options *terraform.Options, tfplan string) (string, error) {
+	*t.applies++
+	if *t.applies <= t.throttledApplies {
+		return t.applyErr, errors.New("exit status 1")
+	}
+
+	return "", nil
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := map[string]struct {
+		resp     string
+		err      error
+		expected bool
+	}{
+		"AWSThrottling":     {resp: "Error: ThrottlingException: Rate exceeded", err: errors.New("exit status 1"), expected: true},
+		"AzureTooMany":      {resp: "StatusCode=429 -- Original Error: Too Many Requests", err: errors.New("exit status 1"), expected: true},
+		"GCPRateLimit":      {resp: "googleapi: Error 403: Quota exceeded, rateLimitExceeded", err: errors.New("exit status 1"), expected: true},
+		"ErrorMessageOnly":  {resp: "", err: errors.New("RequestLimitExceeded: Request limit exceeded."), expected: true},
+		"RealError":         {resp: "Error: Invalid reference", err: errors.New("exit status 1"), expected: false},
+		"NoErrorWithNotice": {resp: "throttling configured", err: nil, expected: false},
+		"PlannedResources": {
+			resp:     "  + name = \"slow-down-queue\"\n  + port = 429\n  + policy = \"throttling\"\nError: Invalid reference",
+			err:      errors.New("exit status 1"),
+			expected: false,
+		},
+		"ErrorLineAfterPlan": {
+			resp:     "  + name = \"queue\"\nError: error creating queue: ThrottlingException: Rate exceeded",
+			err:      errors.New("exit status 1"),
+			expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.expected, IsRateLimitError(test.resp, test.err))
+		})
+	}
+}
+
+func TestExecuteTerraformInDir_ShouldRetryAndTagRateLimitedApply(t *testing.T) {
+	applies := 0
+	terraformer = throttledTerraformer{applies: &applies, throttledApplies: 1, applyErr: "Error: ThrottlingException: Rate exceeded"}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	planAndApplyRetrySleep, rateLimitBackoff = 0, 0
+	defer func() { planAndApplyRetrySleep, rateLimitBackoff = 10*time.Second, 30*time.Second }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.MaxRetries = 1
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.Equal(t, 2, applies, "Throttled apply should be retried")
+	require.Equal(t, config.Success, output.Status, "Step should succeed once the throttled apply is retried")
+	require.NoError(t, output.Err)
+	require.True(t, output.RateLimited, "Step should be tagged as rate limited")
+}
+
+func TestExecuteTerraformInDir_ShouldNotTagRealErrorsAsRateLimited(t *testing.T) {
+	applies := 0
+	terraformer = throttledTerraformer{applies: &applies, throttledApplies: 2, applyErr: "Error: Invalid reference"}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	planAndApplyRetrySleep, rateLimitBackoff = 0, 0
+	defer func() { planAndApplyRetrySleep, rateLimitBackoff = 10*time.Second, 30*time.Second }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.MaxRetries = 1
+
+	// act
+	output := executeTerraformInDir(exec, false)
+
+	// assert
+	require.Equal(t, config.Fail, output.Status)
+	require.Error(t, output.Err)
+	require.False(t, output.RateLimited, "Real errors should not be tagged as rate limited")
+}
+
+func TestExecuteTerraformInDir_ShouldNotBackOffAfterFinalRateLimitedAttempt(t *testing.T) {
+	applies := 0
+	terraformer = throttledTerraformer{applies: &applies, throttledApplies: 1, applyErr: "Error: ThrottlingException: Rate exceeded"}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	planAndApplyRetrySleep, rateLimitBackoff = 0, time.Hour
+	defer func() { planAndApplyRetrySleep, rateLimitBackoff = 10*time.Second, 30*time.Second }()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.MaxRetries = 0
+
+	// act
+	done := make(chan config.StepOutput)
+	go func() { done <- executeTerraformInDir(exec, false) }()
+
+	// assert
+	select {
+	case output := <-done:
+		require.Equal(t, 1, applies)
+		require.Equal(t, config.Fail, output.Status)
+		require.True(t, output.RateLimited, "Step should be tagged as rate limited")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Step should not back off after its final attempt")
+	}
+}
+
+func TestExecuteTerraformInDir_ShouldStopBackingOffWhenCancelled(t *testing.T) {
+	applies := 0
+	terraformer = throttledTerraformer{applies: &applies, throttledApplies: 1, applyErr: "Error: ThrottlingException: Rate exceeded"}
+	defer func() { terraformer = terraform.Terraform{} }()
+
+	planAndApplyRetrySleep, rateLimitBackoff = 0, time.Hour
+	defer func() { planAndApplyRetrySleep, rateLimitBackoff = 10*time.Second, 30*time.Second }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	exec := stubPolicyExecution(false)
+	exec.PolicyCommand = ""
+	exec.MaxRetries = 1
+	exec.Context = ctx
+
+	// act
+	done := make(chan config.StepOutput)
+	go func() { done <- executeTerraformInDir(exec, false) }()
+
+	// assert
+	select {
+	case output := <-done:
+		require.True(t, output.RateLimited, "Step should be tagged as rate limited")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Step should stop backing off once the deployment is cancelled")
+	}
+}
@@ -1,13 +1,15 @@
 package plugins_terraform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/optum/runiac/pkg/config"
 	"github.com/optum/runiac/pkg/retry"
-	"github.com/optum/runiac/pkg/shell"
+	"github.com/optum/runiac/pkg/testrunner"
 	"github.com/optum/runiac/plugins/terraform/pkg/terraform"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -43,7 +45,7 @@ func (stepper TerraformStepper) ExecuteStep(exec config.StepExecution) config.St
 func (stepper TerraformStepper) ExecuteStepTests(exec config.StepExecution) (output config.StepTestOutput) {
 	HandleDeployOverrides(exec.Logger, exec.Dir, exec.DeploymentRing)
 
-	envVars := map[string]string{}
+	envVars := GetProviderEnvVars(exec)
 
 	for k, v := range GetTerraformEnvVars(exec) {
 		envVars[fmt.Sprintf("TF_VAR_%s", k)] = v
@@ -55,6 +57,17 @@ func (stepper TerraformStepper) ExecuteStepTests(exec config.StepExecution) (out
 
 	testDir := fmt.Sprintf("%s/tests", exec.Dir)
 
+	// tests supporting plan assertion mode validate the intended changes instead of deployed resources
+	if exec.TestAgainstPlan {
+		planFile, err := filepath.Abs(planJSONFile(exec))
+		if err != nil {
+			planFile = planJSONFile(exec)
+		}
+
+		envVars["RUNIAC_TEST_AGAINST_PLAN"] = "true"
+		envVars["RUNIAC_PLAN_JSON"] = planFile
+	}
+
 	// ensure output directory exists for test reporting
 	outputDir := filepath.Join("/", "output", "junit")
 	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
@@ -65,21 +78,18 @@ func (stepper TerraformStepper) ExecuteStepTests(exec config.StepExecution) (out
 		}
 	}
 
+	testRunner, err := testrunner.Determine(exec, testDir)
+	if err != nil {
+		exec.Logger.WithError(err).Error("Unable to determine test runner")
+		output.Err = err
+		return
+	}
+
 	_ = retry.DoWithRetry(fmt.Sprintf("execute tests: %s", testDir), exec.MaxTestRetries, 20*time.Second, exec.Logger, func(retryCount int) error {
-		retryLogger := exec.Logger.WithField("retryCount", retryCount)
-		stepDeployID := fmt.Sprintf("%s-%s-%s-%s-%s", exec.Project, exec.TrackName, exec.StepName, exec.RegionDeployType, exec.Region)
-		cmd := shell.Command{
-			Command: "gotestsum",
-			//Command:        "/bin/bash",
-			Args:           []string{"--format", "standard-verbose", "--junitfile", fmt.Sprintf("/output/junit/%s.xml", stepDeployID), "--raw-command", "--", "test2json", "-p", stepDeployID, "./tests.test", "-test.v"},
-			Logger:         retryLogger,
-			SensitiveArgs:  false,
-			NonInteractive: true,
-			Env:            envVars,
-			WorkingDir:     testDir,
-		}
+		retryExec := exec
+		retryExec.Logger = exec.Logger.WithField("retryCount", retryCount)
 
-		output.StreamOutput, output.Err = shell.RunShellCommandAndGetAndStreamOutput(cmd)
+		output.StreamOutput, output.Err = testRunner.RunTests(retryExec, testDir, envVars)
 
 		return output.Err
 	})
@@ -97,6 +107,26 @@ func GetTerraformCLIVars(exec config.StepExecution) map[string]interface{} {
 	return vars
 }
 
+// GetProviderEnvVars returns the environment variables that tag the step's provider API calls with the deployment and its environment,
+// TF_APPEND_USER_AGENT is honored by terraform and its providers
+func GetProviderEnvVars(exec config.StepExecution) map[string]string {
+	envVars := map[string]string{}
+
+	if exec.UniqueExternalExecutionID != "" {
+		envVars["RUNIAC_RUN_ID"] = exec.UniqueExternalExecutionID
+	}
+
+	if exec.Environment != "" {
+		envVars["RUNIAC_ENVIRONMENT"] = exec.Environment
+	}
+
+	if exec.ProviderUserAgentSuffix != "" {
+		envVars["TF_APPEND_USER_AGENT"] = exec.ProviderUserAgentSuffix
+	}
+
+	return envVars
+}
+
 func GetTerraformEnvVars(exec config.StepExecution) map[string]string {
 	output := exec.OptionalStepParams
 	// set core accounts
@@ -149,6 +179,18 @@ func HandleDestroyOverrides(logger *logrus.Entry, execDir string,
 	handleOverride(logger, execDir, destroyRingOverrideFile)
 }
 
+// appendWarnings appends the warnings within terraform's output not already reported, plan and apply report the same
+// deprecations
+func appendWarnings(warnings []string, resp string) []string {
+	for _, warning := range terraform.ParseWarnings(resp) {
+		if !contains(warnings, warning) {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -194,6 +236,7 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 	resp, output.Err = terraformer.Init(tfOptions)
 
 	if output.Err != nil {
+		output.StreamOutput = resp
 		tfOptions.Logger.WithError(output.Err).Error("Error during terraform init")
 		return
 	}
@@ -208,16 +251,19 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 	resp, output.Err = terraformer.WorkspaceSelect(tfOptions, workspace)
 
 	if output.Err != nil {
+		output.StreamOutput = resp
 		tfOptions.Logger.WithError(output.Err).Error("Error during terraform init")
 		return
 	}
 
 	// terraform plan
-	_ = retry.DoWithRetry("terraform plan and apply", tfOptions.MaxRetries, 10*time.Second, tfOptions.Logger, func(attempt int) error {
+	_ = retry.DoWithRetry("terraform plan and apply", tfOptions.MaxRetries, planAndApplyRetrySleep, tfOptions.Logger, func(attempt int) error {
 
 		retryLogger := tfOptions.Logger.WithField("retryCount", attempt)
 
-		tfplan := fmt.Sprintf("%s%s%stfplan", exec.StepName, exec.RegionDeployType, exec.Region)
+		tfplan := planFile(exec)
+		output.Warnings = nil
+		output.StreamOutput = ""
 
 		// terraform plan
 		tfOptions, output.Err = getCommonTfOptions2(exec)
@@ -237,11 +283,39 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 
 		tfOptions.Vars = GetTerraformCLIVars(exec)
 
-		resp, output.Err = terraformer.Plan(tfOptions, tfplan, destroy)
+		if exec.BundledPlanFile != "" && !destroy {
+			// apply the reviewed plan from the bundle instead of planning again, terraform runs within the step directory
+			tfplan, output.Err = filepath.Abs(exec.BundledPlanFile)
 
-		if output.Err != nil {
-			tfOptions.Logger.WithError(output.Err).Error("Error running terraform plan")
-			return output.Err
+			if output.Err == nil {
+				_, output.Err = exec.Fs.Stat(tfplan)
+			}
+
+			if output.Err != nil {
+				tfOptions.Logger.WithError(output.Err).Error("Step has no plan in the applied plan bundle")
+				output.Err = fmt.Errorf("step %s has no %s %s plan in the applied plan bundle: %w", exec.StepName, exec.RegionDeployType, exec.Region, output.Err)
+
+				// the bundle will not change between attempts
+				return nil
+			}
+
+			tfOptions.Logger.Infof("Applying bundled plan %s", exec.BundledPlanFile)
+		} else {
+			resp, output.Err = terraformer.Plan(tfOptions, tfplan, destroy)
+
+			if output.Err != nil {
+				// the error is only the exit status, the output explains the failure for classification and reporting
+				output.StreamOutput = resp
+				tfOptions.Logger.WithError(output.Err).Error("Error running terraform plan")
+				return handleRateLimit(exec.Context, retryLogger, &output, resp, attempt == exec.MaxRetries)
+			}
+
+			output.Warnings = appendWarnings(output.Warnings, resp)
+		}
+
+		output.PlanFile = filepath.Join(exec.Dir, tfplan)
+		if filepath.IsAbs(tfplan) {
+			output.PlanFile = tfplan
 		}
 
 		// validate terraform plan
@@ -269,7 +343,44 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 		}
 		// aws_cloudtrail.central_logging_trail, aws_cloudtrail, central_logging_trail: [no-op]
 
+		// persist plan json for policy checks and plan assertion tests
+		if exec.PolicyCommand != "" || exec.TestAgainstPlan {
+			output.Err = afero.WriteFile(exec.Fs, planJSONFile(exec), []byte(resp), 0644)
+
+			if output.Err != nil {
+				retryLogger.WithError(output.Err).Error("Error writing plan json")
+				return output.Err
+			}
+		}
+
+		// validate plan against policies prior to apply
+		if exec.PolicyCommand != "" {
+			policyLogger := retryLogger.WithField("terraform", "policy")
+
+			// the policy command is executed within the step directory, so it receives the plan by its absolute path
+			planFile, err := filepath.Abs(planJSONFile(exec))
+			if err != nil {
+				planFile = planJSONFile(exec)
+			}
+
+			output.PolicyOutput, output.Err = runPolicyCommand(exec, planFile)
+
+			if output.Err != nil {
+				if !exec.PolicyWarnOnly {
+					policyLogger.WithError(output.Err).Errorf("Plan failed policy check:\n%s", output.PolicyOutput)
+					output.Err = PolicyCheckFailed{Command: exec.PolicyCommand, Output: output.PolicyOutput}
+
+					// policy results are deterministic for a given plan, retrying will not change the outcome
+					return nil
+				}
+
+				policyLogger.WithError(output.Err).Warnf("Plan failed policy check, continuing due to policy warn only mode:\n%s", output.PolicyOutput)
+				output.Err = nil
+			}
+		}
+
 		resourceChangesByAction := map[string][]string{}
+		output.PlannedDeletions = nil
 		for _, c := range plan.ResourceChanges {
 			key := fmt.Sprintf("%s", c.Change.Actions)
 			if resourceChangesByAction[key] == nil {
@@ -278,13 +389,33 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 
 			resourceChangesByAction[key] = append(resourceChangesByAction[key], c.Address)
 
+			for _, action := range c.Change.Actions {
+				if action == "delete" {
+					output.PlannedDeletions = append(output.PlannedDeletions, c.Address)
+				}
+			}
+
 			tfOptions.Logger.Info(fmt.Sprintf("%s, %s, %s: %s", c.Address, c.Type, c.Name, c.Change.Actions))
 		}
+
+		summary := summarizePlan(plan)
+		output.PlanSummary = &summary
+		// a destroy is only expected to delete resources, anything else indicates drift
+		if destroy && !exec.AllowDestroyPlanChanges {
+			if unexpected := unexpectedDestroyChanges(plan); len(unexpected) > 0 {
+				output.Err = UnexpectedDestroyChanges{Resources: unexpected}
+				retryLogger.WithError(output.Err).Error("Destroy plan contains unexpected changes, not applying")
+
+				// the plan will not change between attempts
+				return nil
+			}
+		}
+
 		applyChanges := true
 		//noChanges := len(resourceChangesByAction["[no-op]"]) == len(plan.ResourceChanges)
 
 		// only run apply on when not dry run and changes exist
-		if exec.DryRun {
+		if exec.DryRun || exec.TestAgainstPlan {
 			tfOptions.Logger.Info("---------- Skipping apply, this is a dry run ---------- ")
 			applyChanges = false
 		}
@@ -300,8 +431,23 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 			resp, output.Err = terraformer.Apply(baseOptions, tfplan)
 
 			if output.Err != nil {
+				output.StreamOutput = resp
 				baseOptions.Logger.WithError(output.Err).Error("Error running terraform apply")
-				return output.Err
+				return handleRateLimit(exec.Context, retryLogger, &output, resp, attempt == exec.MaxRetries)
+			}
+
+			output.Warnings = appendWarnings(output.Warnings, resp)
+		}
+
+		// list the resources managed by the step for the inventory, those already deployed when nothing was applied
+		if exec.EmitInventory && !destroy {
+			baseOptions.Logger = retryLogger.WithField("terraform", "state")
+			resp, err = terraformer.StateList(baseOptions)
+
+			if err != nil {
+				baseOptions.Logger.WithError(err).Warn("Unable to list resources for the inventory")
+			} else {
+				output.Resources = terraform.ParseStateList(resp)
 			}
 		}
 
@@ -321,6 +467,17 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 			baseOptions.Logger.WithError(output.Err).Error("Error running terraform output")
 		}
 
+		// record which previous step outputs the step referenced for the dependency graph
+		if !destroy {
+			consumed, err := ConsumedVariables(exec)
+
+			if err != nil {
+				retryLogger.WithError(err).Warn("Unable to determine the output variables consumed by the step")
+			} else {
+				output.ConsumedVariables = consumed
+			}
+		}
+
 		output.Status = config.Success
 
 		return nil
@@ -329,6 +486,38 @@ var executeTerraformInDir = func(exec config.StepExecution, destroy bool) (outpu
 	return
 }
 
+// handleRateLimit tags the step output when a failed terraform command was throttled by the provider, backing off
+// before the attempt is retried unless it was the final attempt or ctx is cancelled, and returns the step error for the
+// retry logic
+func handleRateLimit(ctx context.Context, logger *logrus.Entry, output *config.StepOutput, resp string, finalAttempt bool) error {
+	if !IsRateLimitError(resp, output.Err) {
+		return output.Err
+	}
+
+	output.RateLimited = true
+
+	if finalAttempt {
+		logger.WithError(output.Err).Warn("Provider API rate limit encountered on the final attempt")
+		return output.Err
+	}
+
+	logger.WithError(output.Err).Warnf("Provider API rate limit encountered, backing off for %s before retrying", rateLimitBackoff)
+
+	// a nil channel never receives, without a context the backoff is only ended by the timer
+	var cancelled <-chan struct{}
+	if ctx != nil {
+		cancelled = ctx.Done()
+	}
+
+	select {
+	case <-time.After(rateLimitBackoff):
+	case <-cancelled:
+		logger.Warn("Deployment cancelled, no longer backing off")
+	}
+
+	return output.Err
+}
+
 // GetBackendConfig parses a backend.tf file
 // TODO, replace this with a cleaner hcl2json2struct merge where backend.tf configurations take priority over defined defaults here
 func GetBackendConfig(exec config.StepExecution, backendParser TFBackendParser) TerraformBackend {
@@ -477,15 +666,29 @@ func interpolateString(exec config.StepExecution, s string) string {
 	return s
 }
 
+// planFile returns the name of the terraform plan file for a step execution
+func planFile(exec config.StepExecution) string {
+	return fmt.Sprintf("%s%s%stfplan", exec.StepName, exec.RegionDeployType, exec.Region)
+}
+
+// planJSONFile returns the path the json representation of the step execution's plan is written to
+func planJSONFile(exec config.StepExecution) string {
+	return filepath.Join(exec.Dir, fmt.Sprintf("%s.json", planFile(exec)))
+}
+
 func getCommonTfOptions2(exec config.StepExecution) (tfOptions *terraform.Options, err error) {
 	tfOptions = &terraform.Options{
 		TerraformDir:             exec.Dir,
-		EnvVars:                  map[string]string{},
+		EnvVars:                  GetProviderEnvVars(exec),
+		EnvAllowlist:             exec.EnvAllowlist,
 		Logger:                   exec.Logger,
 		NoColor:                  true,
 		RetryableTerraformErrors: map[string]string{".*": "General Terraform error occurred."},
 		MaxRetries:               exec.MaxRetries,
 		TimeBetweenRetries:       5 * time.Second,
+		Context:                  exec.Context,
+		ShutdownGracePeriod:      exec.ShutdownGracePeriod,
+		Parallelism:              exec.TerraformParallelism,
 	}
 
 	return
@@ -2,7 +2,6 @@ package plugins_terraform
 
 import (
 	"fmt"
-	"github.com/optum/runiac/pkg/steps"
 	"strings"
 	"testing"
 
@@ -18,46 +17,6 @@ var sut config.Stepper
 var logger = logrus.NewEntry(logrus.New())
 var DefaultStubAccountID = "1"
 
-func TestNewExecution_ShouldSetFields(t *testing.T) {
-	t.Parallel()
-
-	fs := afero.NewMemMapFs()
-	stubRegion := "region"
-	stubRegionalDeployType := config.RegionalRegionDeployType
-
-	stubStep := config.Step{
-		Dir:  "stub",
-		Name: "stubName",
-		DeployConfig: config.Config{
-			DeploymentRing:            "stubDeploymentRing",
-			Project:                   "stubProject",
-			DryRun:                    true,
-			RegionalRegions:           []string{"stub"},
-			UniqueExternalExecutionID: "stubExecutionID",
-			MaxRetries:                3,
-			MaxTestRetries:            2,
-		},
-		TrackName: "stubTrackName",
-	}
-	// act
-	mock := steps.NewExecution(stubStep, logger, fs, stubRegionalDeployType, stubRegion, map[string]map[string]string{})
-
-	// assert
-	require.Equal(t, stubStep.Dir, mock.Dir, "Dir should match stub value")
-	require.Equal(t, stubStep.Name, mock.StepName, "Name should match stub value")
-	require.Equal(t, stubRegion, mock.Region, "Region should match stub value")
-	require.Equal(t, stubRegionalDeployType, mock.RegionDeployType, "RegionDeployType should match stub value")
-	require.Equal(t, stubStep.DeployConfig.DeploymentRing, mock.DeploymentRing, "DeploymentRing should match stub value")
-	require.Equal(t, stubStep.DeployConfig.Project, mock.Project, "Project should match stub value")
-	require.Equal(t, stubStep.DeployConfig.DryRun, mock.DryRun, "DryRun should match stub value")
-	require.Equal(t, stubStep.TrackName, mock.TrackName, "TrackName should match stub value")
-	require.Equal(t, stubStep.DeployConfig.UniqueExternalExecutionID, mock.UniqueExternalExecutionID, "UniqueExternalExecutionID should match stub value")
-	require.Equal(t, stubStep.DeployConfig.RegionalRegions, mock.RegionGroupRegions, "RegionGroupRegions should match stub value")
-	require.Equal(t, stubStep.DeployConfig.MaxRetries, mock.MaxRetries, "MaxRetries should match stub value")
-	require.Equal(t, stubStep.DeployConfig.MaxTestRetries, mock.MaxTestRetries, "MaxTestRetries should match stub value")
-
-}
-
 func TestGetBackendConfig_ShouldParseAssumeRoleCoreAccountIDMapCorrectly(t *testing.T) {
 	t.Parallel()
 	fs := afero.NewMemMapFs()
@@ -230,7 +189,7 @@ func TestGetBackendConfig_ShouldReturnSameValueForKeyAsStepAsNoKey(t *testing.T)
 func TestHandleOverrides_ShouldSetFields(t *testing.T) {
 	var overrideSrc, overrideDst string
 
-	steps.CopyFile = func(src, dst string) (err error) {
+	CopyFile = func(src, dst string) (err error) {
 		overrideSrc = src
 		overrideDst = dst
 		return nil
@@ -550,3 +509,121 @@ func TestStringToBackendType(t *testing.T) {
 		require.Equal(t, tc.errorExists, err != nil, "The error result should match the expected")
 	}
 }
+
+func TestGetCommonTfOptions_ShouldTagProviderCallsWithRunID(t *testing.T) {
+	t.Parallel()
+
+	exec := config.StepExecution{
+		Dir:                       "stub",
+		Logger:                    logger,
+		UniqueExternalExecutionID: "run-123",
+		ProviderUserAgentSuffix:   "runiac/run-123",
+	}
+
+	// act
+	tfOptions, err := getCommonTfOptions2(exec)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "runiac/run-123", tfOptions.EnvVars["TF_APPEND_USER_AGENT"], "User agent suffix should be in the runner's environment")
+	require.Equal(t, "run-123", tfOptions.EnvVars["RUNIAC_RUN_ID"], "Run id should be in the runner's environment")
+}
+
+func TestGetCommonTfOptions_ShouldTagProviderCallsWithEnvironment(t *testing.T) {
+	t.Parallel()
+
+	exec := config.StepExecution{
+		Dir:         "stub",
+		Logger:      logger,
+		Environment: "prod",
+	}
+
+	// act
+	tfOptions, err := getCommonTfOptions2(exec)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "prod", tfOptions.EnvVars["RUNIAC_ENVIRONMENT"], "Environment should be in the runner's environment")
+	require.Equal(t, "prod", GetTerraformCLIVars(exec)["runiac_environment"], "Environment should be passed to the runner as an input variable")
+}
+
+func TestGetCommonTfOptions_ShouldNotSetUserAgentSuffixByDefault(t *testing.T) {
+	t.Parallel()
+
+	exec := config.StepExecution{Dir: "stub", Logger: logger}
+
+	// act
+	tfOptions, err := getCommonTfOptions2(exec)
+
+	// assert
+	require.NoError(t, err)
+	require.NotContains(t, tfOptions.EnvVars, "TF_APPEND_USER_AGENT")
+	require.NotContains(t, tfOptions.EnvVars, "RUNIAC_RUN_ID")
+}
+
+func TestConsumedVariables_ShouldMatchDeclaredStepOutputVariables(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "step/variables.tf", []byte(`
+variable "s3_bucket-bucket_arn" {
+  type = string
+}
+
+variable s3_bucket-regional-bucket_name {
+  type = string
+}
+
+variable "runiac_region" {}
+`), 0644)
+	_ = afero.WriteFile(fs, "step/main.tf", []byte(`variable "pretrack-project-project_id" {}`), 0644)
+	_ = afero.WriteFile(fs, "step/README.md", []byte(`variable "s3_bucket-bucket_id" {}`), 0644)
+
+	exec := config.StepExecution{
+		Fs:  fs,
+		Dir: "step",
+		DefaultStepOutputVariables: map[string]map[string]string{
+			"s3_bucket":          {"bucket_arn": "arn", "bucket_id": "id"},
+			"s3_bucket-regional": {"bucket_name": "name"},
+			"pretrack-project":   {"project_id": "id", "project_name": "name"},
+			"unused":             {"value": "value"},
+		},
+	}
+
+	// act
+	consumed, err := ConsumedVariables(exec)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"s3_bucket":          {"bucket_arn"},
+		"s3_bucket-regional": {"bucket_name"},
+		"pretrack-project":   {"project_id"},
+	}, consumed)
+}
+
+func TestGetCommonTfOptions_ShouldPassEnvAllowlistToTerraform(t *testing.T) {
+	t.Parallel()
+
+	exec := config.StepExecution{Dir: "stub", Logger: logger, EnvAllowlist: []string{"PATH", "AWS_*"}}
+
+	// act
+	tfOptions, err := getCommonTfOptions2(exec)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []string{"PATH", "AWS_*"}, tfOptions.EnvAllowlist)
+}
+
+func TestGetCommonTfOptions_ShouldPassTerraformParallelism(t *testing.T) {
+	t.Parallel()
+
+	exec := config.StepExecution{Dir: "stub", Logger: logger, TerraformParallelism: 4}
+
+	// act
+	tfOptions, err := getCommonTfOptions2(exec)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 4, tfOptions.Parallelism)
+}